@@ -0,0 +1,844 @@
+// Package binmon serves the binary remote monitor protocol of VICE for a
+// CPU6510, so that frontends made for VICE can debug programs running on it.
+//
+// Every request starts with the byte $02, the API version $02, the length of
+// the body, the request ID and the command, and every response with $02, the
+// API version, the length of the body, the response type, an error code and
+// the request ID. All numbers are little endian. Events the frontend did not
+// ask for, like a checkpoint being hit, have the request ID $FFFFFFFF.
+//
+// The supported commands are memory get and set, checkpoint get, set,
+// delete, list and toggle, condition set, registers get and set, advance
+// instructions, ping, banks and registers available, exit, quit, reset and
+// autostart of PRG files. Only the main memory space is supported, and memory
+// is accessed without side effects.
+package binmon
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+	"github.com/stefanalfbo/commodore64/internal/machine"
+)
+
+// The byte every message starts with, and the API version.
+const (
+	startByte  = 0x02
+	apiVersion = 0x02
+)
+
+// The commands, which are also the response types of their responses.
+const (
+	commandMemoryGet          = 0x01
+	commandMemorySet          = 0x02
+	commandCheckpointGet      = 0x11
+	commandCheckpointSet      = 0x12
+	commandCheckpointDelete   = 0x13
+	commandCheckpointList     = 0x14
+	commandCheckpointToggle   = 0x15
+	commandConditionSet       = 0x22
+	commandRegistersGet       = 0x31
+	commandRegistersSet       = 0x32
+	commandAdvance            = 0x71
+	commandPing               = 0x81
+	commandBanksAvailable     = 0x82
+	commandRegistersAvailable = 0x83
+	commandExit               = 0xAA
+	commandQuit               = 0xBB
+	commandReset              = 0xCC
+	commandAutostart          = 0xDD
+)
+
+// The types of the events.
+const (
+	eventJam     = 0x61
+	eventStopped = 0x62
+	eventResumed = 0x63
+)
+
+// The request ID of events.
+const eventRequestID = 0xFFFFFFFF
+
+// The error codes of the responses.
+const (
+	errorNone             = 0x00
+	errorObjectMissing    = 0x01
+	errorInvalidMemspace  = 0x02
+	errorLength           = 0x80
+	errorInvalidParameter = 0x81
+	errorAPIVersion       = 0x82
+	errorInvalidCommand   = 0x83
+	errorGeneralFailure   = 0x8F
+)
+
+// The main memory space, the only one there is.
+const mainMemspace = 0x00
+
+// The operations of a checkpoint.
+const (
+	operationLoad  = 0x01
+	operationStore = 0x02
+	operationExec  = 0x04
+)
+
+// The number of instructions the CPU runs between checks for requests.
+const stepsBetweenRequests = 1000
+
+// register is a register of the CPU as VICE numbers it.
+type register struct {
+	id   byte
+	name string
+	bits byte
+	get  func(s cpu6510.State) uint16
+	set  func(s *cpu6510.State, value uint16)
+}
+
+var registers = []register{
+	{0x00, "A", 8, func(s cpu6510.State) uint16 { return uint16(s.A) }, func(s *cpu6510.State, v uint16) { s.A = byte(v) }},
+	{0x01, "X", 8, func(s cpu6510.State) uint16 { return uint16(s.X) }, func(s *cpu6510.State, v uint16) { s.X = byte(v) }},
+	{0x02, "Y", 8, func(s cpu6510.State) uint16 { return uint16(s.Y) }, func(s *cpu6510.State, v uint16) { s.Y = byte(v) }},
+	{0x03, "PC", 16, func(s cpu6510.State) uint16 { return s.PC }, func(s *cpu6510.State, v uint16) { s.PC = v }},
+	{0x04, "SP", 8, func(s cpu6510.State) uint16 { return uint16(s.SP) }, func(s *cpu6510.State, v uint16) { s.SP = byte(v) }},
+	{0x05, "FL", 8, func(s cpu6510.State) uint16 { return uint16(s.P) }, func(s *cpu6510.State, v uint16) { s.P = byte(v) }},
+}
+
+// errQuit ends a session when the frontend quits the emulator.
+var errQuit = errors.New("frontend quit")
+
+// Server serves VICE frontends for a CPU, one at a time. The CPU only runs
+// while a frontend is connected, and the server is the only one that drives
+// it. Like VICE, the CPU keeps running when a frontend connects, and stops
+// when a checkpoint is hit or a command is received.
+type Server struct {
+	cpu *cpu6510.CPU
+}
+
+// NewServer creates a server for the CPU.
+func NewServer(cpu *cpu6510.CPU) *Server {
+	return &Server{cpu: cpu}
+}
+
+// ListenAndServe listens on the TCP address and serves frontends that connect
+// to it.
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on the listener and serves them one after the
+// other, until a frontend quits.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		err = s.serveConn(conn)
+		conn.Close()
+
+		if errors.Is(err, errQuit) {
+			return nil
+		}
+	}
+}
+
+// ServeConn serves a frontend on the connection until it quits or the
+// connection is closed. The caller closes the connection.
+func (s *Server) ServeConn(conn io.ReadWriter) error {
+	err := s.serveConn(conn)
+	if errors.Is(err, errQuit) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Server) serveConn(conn io.ReadWriter) error {
+	err := newSession(s.cpu, conn).serve()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
+
+// request is a request from the frontend.
+type request struct {
+	version byte
+	id      uint32
+	command byte
+	body    []byte
+}
+
+// checkpoint is what a session knows about a checkpoint beyond the breakpoint
+// of the CPU.
+type checkpoint struct {
+	stop      bool
+	temporary bool
+}
+
+// session is the conversation with a single frontend.
+type session struct {
+	cpu *cpu6510.CPU
+	out io.Writer
+	// The requests from the frontend, read by a goroutine of their own so
+	// that they are noticed while the CPU runs. Closed at the end of the
+	// input, after the error is stored in readErr.
+	requests chan request
+	readErr  error
+	// The checkpoints the frontend has set, by breakpoint number.
+	checkpoints map[int]checkpoint
+	// True while the CPU runs, false while it is stopped in the monitor.
+	running bool
+}
+
+// newSession starts reading requests from the connection.
+func newSession(cpu *cpu6510.CPU, conn io.ReadWriter) *session {
+	s := &session{
+		cpu:         cpu,
+		out:         conn,
+		requests:    make(chan request),
+		checkpoints: map[int]checkpoint{},
+		running:     true,
+	}
+
+	go func() {
+		for {
+			req, err := readRequest(conn)
+			if err != nil {
+				s.readErr = err
+				close(s.requests)
+				return
+			}
+			s.requests <- req
+		}
+	}()
+
+	return s
+}
+
+// readRequest reads the next request.
+func readRequest(r io.Reader) (request, error) {
+	var header [11]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return request{}, err
+	}
+
+	if header[0] != startByte {
+		return request{}, fmt.Errorf("request starts with $%02X instead of $%02X", header[0], startByte)
+	}
+
+	length := binary.LittleEndian.Uint32(header[2:6])
+	if length > 0x10000 {
+		return request{}, fmt.Errorf("request body of %d bytes is too large", length)
+	}
+
+	req := request{
+		version: header[1],
+		id:      binary.LittleEndian.Uint32(header[6:10]),
+		command: header[10],
+		body:    make([]byte, length),
+	}
+
+	if _, err := io.ReadFull(r, req.body); err != nil {
+		return request{}, err
+	}
+
+	return req, nil
+}
+
+// serve answers requests and runs the CPU until the frontend quits or the
+// input ends.
+func (s *session) serve() error {
+	defer s.removeCheckpoints()
+
+	for {
+		if !s.running {
+			req, ok := <-s.requests
+			if !ok {
+				return s.readErr
+			}
+
+			if err := s.handle(req); err != nil {
+				return err
+			}
+			continue
+		}
+
+		select {
+		case req, ok := <-s.requests:
+			if !ok {
+				return s.readErr
+			}
+
+			if err := s.stop(); err != nil {
+				return err
+			}
+
+			if err := s.handle(req); err != nil {
+				return err
+			}
+		default:
+			if err := s.run(stepsBetweenRequests); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// run executes up to the given number of instructions, and stops in the
+// monitor when a checkpoint that stops is hit or the CPU jams.
+func (s *session) run(steps int) error {
+	for i := 0; i < steps && s.running; i++ {
+		if _, err := s.cpu.Step(); err != nil {
+			if _, err := s.stopped(err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// stopped handles the error that stopped the CPU, and returns true when the
+// CPU has stopped in the monitor. A checkpoint that does not stop only
+// reports the hit, and lets the CPU carry on.
+func (s *session) stopped(err error) (bool, error) {
+	var hit *cpu6510.BreakpointError
+
+	if !errors.As(err, &hit) {
+		s.running = false
+
+		return true, s.send(eventJam, errorNone, eventRequestID, s.pcBody())
+	}
+
+	cp := s.checkpoints[hit.Breakpoint.ID]
+
+	if err := s.send(commandCheckpointGet, errorNone, eventRequestID, checkpointInfo(hit.Breakpoint, true, cp)); err != nil {
+		return false, err
+	}
+
+	if cp.temporary {
+		s.cpu.RemoveBreakpoint(hit.Breakpoint.ID)
+		delete(s.checkpoints, hit.Breakpoint.ID)
+	}
+
+	if !cp.stop {
+		return false, nil
+	}
+
+	return true, s.stop()
+}
+
+// stop stops the CPU in the monitor.
+func (s *session) stop() error {
+	s.running = false
+
+	return s.send(eventStopped, errorNone, eventRequestID, s.pcBody())
+}
+
+// resume lets the CPU run again.
+func (s *session) resume() error {
+	s.running = true
+
+	return s.send(eventResumed, errorNone, eventRequestID, s.pcBody())
+}
+
+// pcBody returns the body of the events with the program counter.
+func (s *session) pcBody() []byte {
+	return binary.LittleEndian.AppendUint16(nil, s.cpu.PC())
+}
+
+// send writes a response, or an event when the request ID is eventRequestID.
+func (s *session) send(responseType byte, errorCode byte, requestID uint32, body []byte) error {
+	message := []byte{startByte, apiVersion}
+	message = binary.LittleEndian.AppendUint32(message, uint32(len(body)))
+	message = append(message, responseType, errorCode)
+	message = binary.LittleEndian.AppendUint32(message, requestID)
+	message = append(message, body...)
+
+	_, err := s.out.Write(message)
+
+	return err
+}
+
+// removeCheckpoints removes the checkpoints the frontend has set.
+func (s *session) removeCheckpoints() {
+	for id := range s.checkpoints {
+		s.cpu.RemoveBreakpoint(id)
+		delete(s.checkpoints, id)
+	}
+}
+
+// handle answers the request.
+func (s *session) handle(req request) error {
+	if req.version != apiVersion {
+		return s.send(req.command, errorAPIVersion, req.id, nil)
+	}
+
+	handler, ok := handlers[req.command]
+	if !ok {
+		return s.send(req.command, errorInvalidCommand, req.id, nil)
+	}
+
+	if len(req.body) < handler.minLength {
+		return s.send(req.command, errorLength, req.id, nil)
+	}
+
+	return handler.handle(s, req)
+}
+
+// handler answers a command, whose body is at least minLength bytes.
+type handler struct {
+	minLength int
+	handle    func(s *session, req request) error
+}
+
+var handlers map[byte]handler
+
+func init() {
+	handlers = map[byte]handler{
+		commandMemoryGet:          {8, (*session).memoryGet},
+		commandMemorySet:          {8, (*session).memorySet},
+		commandCheckpointGet:      {4, (*session).checkpointGet},
+		commandCheckpointSet:      {8, (*session).checkpointSet},
+		commandCheckpointDelete:   {4, (*session).checkpointDelete},
+		commandCheckpointList:     {0, (*session).checkpointList},
+		commandCheckpointToggle:   {5, (*session).checkpointToggle},
+		commandConditionSet:       {5, (*session).conditionSet},
+		commandRegistersGet:       {1, (*session).registersGet},
+		commandRegistersSet:       {3, (*session).registersSet},
+		commandAdvance:            {3, (*session).advance},
+		commandPing:               {0, (*session).ping},
+		commandBanksAvailable:     {0, (*session).banksAvailable},
+		commandRegistersAvailable: {1, (*session).registersAvailable},
+		commandExit:               {0, (*session).exit},
+		commandQuit:               {0, (*session).quit},
+		commandReset:              {1, (*session).reset},
+		commandAutostart:          {4, (*session).autostart},
+	}
+}
+
+// memoryRange parses the start and end address and the memory space of the
+// memory commands, and returns the number of bytes in the range, which
+// wraps around at $FFFF.
+func memoryRange(body []byte) (uint16, int, byte) {
+	start := binary.LittleEndian.Uint16(body[1:3])
+	end := binary.LittleEndian.Uint16(body[3:5])
+
+	return start, int(end-start) + 1, body[5]
+}
+
+// memoryGet answers memory get: side effects, start, end, memory space and
+// bank. The response is the length and the bytes. The length only has 16
+// bits, so like in VICE it is 0 when the whole 64KB is read, and the client
+// has to go by the length of the response.
+func (s *session) memoryGet(req request) error {
+	start, length, memspace := memoryRange(req.body)
+	if memspace != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	body := binary.LittleEndian.AppendUint16(nil, uint16(length))
+	for i := 0; i < length; i++ {
+		body = append(body, s.cpu.Peek(start+uint16(i)))
+	}
+
+	return s.send(req.command, errorNone, req.id, body)
+}
+
+// memorySet answers memory set: side effects, start, end, memory space, bank
+// and the bytes.
+func (s *session) memorySet(req request) error {
+	start, length, memspace := memoryRange(req.body)
+	if memspace != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	data := req.body[8:]
+	if len(data) != length {
+		return s.send(req.command, errorLength, req.id, nil)
+	}
+
+	for i, value := range data {
+		s.cpu.Poke(start+uint16(i), value)
+	}
+
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// checkpointInfo returns the body of the checkpoint info response: number,
+// currently hit, start, end, stop when hit, enabled, operation, temporary,
+// hit count, ignore count, has condition and memory space.
+func checkpointInfo(breakpoint cpu6510.Breakpoint, hit bool, cp checkpoint) []byte {
+	var operation byte
+	if breakpoint.Access&cpu6510.AccessRead != 0 {
+		operation |= operationLoad
+	}
+	if breakpoint.Access&cpu6510.AccessWrite != 0 {
+		operation |= operationStore
+	}
+	if breakpoint.Access&cpu6510.AccessExec != 0 {
+		operation |= operationExec
+	}
+
+	body := binary.LittleEndian.AppendUint32(nil, uint32(breakpoint.ID))
+	body = append(body, boolByte(hit))
+	body = binary.LittleEndian.AppendUint16(body, breakpoint.Start)
+	body = binary.LittleEndian.AppendUint16(body, breakpoint.End)
+	body = append(body, boolByte(cp.stop), boolByte(!breakpoint.Disabled), operation, boolByte(cp.temporary))
+	body = binary.LittleEndian.AppendUint32(body, uint32(breakpoint.Hits))
+	body = binary.LittleEndian.AppendUint32(body, uint32(max(breakpoint.IgnoreCount-breakpoint.Hits, 0)))
+	body = append(body, boolByte(breakpoint.Condition != ""), mainMemspace)
+
+	return body
+}
+
+// boolByte returns 1 for true and 0 for false.
+func boolByte(value bool) byte {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+// findCheckpoint returns the breakpoint of the checkpoint with the number.
+func (s *session) findCheckpoint(id int) (cpu6510.Breakpoint, bool) {
+	if _, ok := s.checkpoints[id]; !ok {
+		return cpu6510.Breakpoint{}, false
+	}
+
+	for _, breakpoint := range s.cpu.Breakpoints() {
+		if breakpoint.ID == id {
+			return breakpoint, true
+		}
+	}
+
+	return cpu6510.Breakpoint{}, false
+}
+
+// checkpointGet answers checkpoint get: number.
+func (s *session) checkpointGet(req request) error {
+	id := int(binary.LittleEndian.Uint32(req.body))
+
+	breakpoint, ok := s.findCheckpoint(id)
+	if !ok {
+		return s.send(req.command, errorObjectMissing, req.id, nil)
+	}
+
+	return s.send(commandCheckpointGet, errorNone, req.id, checkpointInfo(breakpoint, false, s.checkpoints[id]))
+}
+
+// checkpointSet answers checkpoint set: start, end, stop when hit, enabled,
+// operation and temporary, optionally followed by the memory space.
+func (s *session) checkpointSet(req request) error {
+	body := req.body
+	if len(body) > 8 && body[8] != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	var access cpu6510.Access
+	if body[6]&operationLoad != 0 {
+		access |= cpu6510.AccessRead
+	}
+	if body[6]&operationStore != 0 {
+		access |= cpu6510.AccessWrite
+	}
+	if body[6]&operationExec != 0 {
+		access |= cpu6510.AccessExec
+	}
+
+	id, err := s.cpu.AddBreakpoint(cpu6510.Breakpoint{
+		Access: access,
+		Start:  binary.LittleEndian.Uint16(body[0:2]),
+		End:    binary.LittleEndian.Uint16(body[2:4]),
+	})
+	if err != nil {
+		return s.send(req.command, errorInvalidParameter, req.id, nil)
+	}
+
+	s.cpu.EnableBreakpoint(id, body[5] != 0)
+	s.checkpoints[id] = checkpoint{stop: body[4] != 0, temporary: body[7] != 0}
+
+	breakpoint, _ := s.findCheckpoint(id)
+
+	return s.send(commandCheckpointGet, errorNone, req.id, checkpointInfo(breakpoint, false, s.checkpoints[id]))
+}
+
+// checkpointDelete answers checkpoint delete: number.
+func (s *session) checkpointDelete(req request) error {
+	id := int(binary.LittleEndian.Uint32(req.body))
+
+	if _, ok := s.findCheckpoint(id); !ok {
+		return s.send(req.command, errorObjectMissing, req.id, nil)
+	}
+
+	s.cpu.RemoveBreakpoint(id)
+	delete(s.checkpoints, id)
+
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// checkpointList answers checkpoint list with a checkpoint info response for
+// every checkpoint, followed by the number of checkpoints.
+func (s *session) checkpointList(req request) error {
+	count := 0
+
+	for _, breakpoint := range s.cpu.Breakpoints() {
+		cp, ok := s.checkpoints[breakpoint.ID]
+		if !ok {
+			continue
+		}
+
+		if err := s.send(commandCheckpointGet, errorNone, req.id, checkpointInfo(breakpoint, false, cp)); err != nil {
+			return err
+		}
+		count++
+	}
+
+	return s.send(req.command, errorNone, req.id, binary.LittleEndian.AppendUint32(nil, uint32(count)))
+}
+
+// checkpointToggle answers checkpoint toggle: number and enabled.
+func (s *session) checkpointToggle(req request) error {
+	id := int(binary.LittleEndian.Uint32(req.body))
+
+	if _, ok := s.findCheckpoint(id); !ok {
+		return s.send(req.command, errorObjectMissing, req.id, nil)
+	}
+
+	s.cpu.EnableBreakpoint(id, req.body[4] != 0)
+
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// conditionSet answers condition set: number, length of the condition and
+// the condition, like A == $10.
+func (s *session) conditionSet(req request) error {
+	id := int(binary.LittleEndian.Uint32(req.body))
+	length := int(req.body[4])
+
+	if len(req.body) != 5+length {
+		return s.send(req.command, errorLength, req.id, nil)
+	}
+
+	if _, ok := s.findCheckpoint(id); !ok {
+		return s.send(req.command, errorObjectMissing, req.id, nil)
+	}
+
+	if err := s.cpu.SetBreakpointCondition(id, string(req.body[5:])); err != nil {
+		return s.send(req.command, errorInvalidParameter, req.id, nil)
+	}
+
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// registersBody returns the body of the registers response: the number of
+// registers, followed by the size of the item, the register ID and the value
+// of every register.
+func (s *session) registersBody() []byte {
+	state := s.cpu.State()
+
+	body := binary.LittleEndian.AppendUint16(nil, uint16(len(registers)))
+	for _, r := range registers {
+		body = append(body, 3, r.id)
+		body = binary.LittleEndian.AppendUint16(body, r.get(state))
+	}
+
+	return body
+}
+
+// registersGet answers registers get: memory space.
+func (s *session) registersGet(req request) error {
+	if req.body[0] != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	return s.send(commandRegistersGet, errorNone, req.id, s.registersBody())
+}
+
+// registersSet answers registers set: memory space, the number of registers,
+// and the size of the item, the register ID and the value of every register.
+// The response has all the registers.
+func (s *session) registersSet(req request) error {
+	if req.body[0] != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	state := s.cpu.State()
+	count := int(binary.LittleEndian.Uint16(req.body[1:3]))
+	items := req.body[3:]
+
+	for i := 0; i < count; i++ {
+		if len(items) < 1 || int(items[0]) < 3 || len(items) < int(items[0])+1 {
+			return s.send(req.command, errorLength, req.id, nil)
+		}
+
+		r, ok := findRegister(items[1])
+		if !ok {
+			return s.send(req.command, errorObjectMissing, req.id, nil)
+		}
+
+		r.set(&state, binary.LittleEndian.Uint16(items[2:4]))
+		items = items[int(items[0])+1:]
+	}
+
+	s.cpu.SetState(state)
+
+	return s.send(commandRegistersGet, errorNone, req.id, s.registersBody())
+}
+
+// findRegister returns the register with the ID.
+func findRegister(id byte) (register, bool) {
+	for _, r := range registers {
+		if r.id == id {
+			return r, true
+		}
+	}
+
+	return register{}, false
+}
+
+// advance answers advance instructions: step over subroutines and the number
+// of instructions. A checkpoint that stops the CPU ends it early, and a
+// checkpoint that does not is only reported.
+func (s *session) advance(req request) error {
+	stepOver := req.body[0] != 0
+	count := int(binary.LittleEndian.Uint16(req.body[1:3]))
+
+	if err := s.send(req.command, errorNone, req.id, nil); err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		pc := s.cpu.PC()
+		sp := s.cpu.SP()
+		overSubroutine := stepOver && s.cpu.Peek(pc) == 0x20
+
+		for {
+			cycles, err := s.cpu.Step()
+			if err != nil {
+				halted, err := s.stopped(err)
+				if halted || err != nil {
+					return err
+				}
+
+				// A checkpoint on execution is hit before the instruction
+				// runs, so it still has to be stepped.
+				if cycles == 0 {
+					continue
+				}
+			}
+
+			if !overSubroutine || s.cpu.PC() == pc+3 && s.cpu.SP() == sp {
+				break
+			}
+		}
+	}
+
+	return s.stop()
+}
+
+// ping answers ping.
+func (s *session) ping(req request) error {
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// banksAvailable answers banks available with the single bank, cpu: the
+// number of banks, followed by the size of the item, the bank ID, the length
+// of the name and the name of every bank.
+func (s *session) banksAvailable(req request) error {
+	name := "cpu"
+
+	body := binary.LittleEndian.AppendUint16(nil, 1)
+	body = append(body, byte(3+len(name)), 0, 0, byte(len(name)))
+	body = append(body, name...)
+
+	return s.send(req.command, errorNone, req.id, body)
+}
+
+// registersAvailable answers registers available: the number of registers,
+// followed by the size of the item, the register ID, the number of bits, the
+// length of the name and the name of every register.
+func (s *session) registersAvailable(req request) error {
+	if req.body[0] != mainMemspace {
+		return s.send(req.command, errorInvalidMemspace, req.id, nil)
+	}
+
+	body := binary.LittleEndian.AppendUint16(nil, uint16(len(registers)))
+	for _, r := range registers {
+		body = append(body, byte(3+len(r.name)), r.id, r.bits, byte(len(r.name)))
+		body = append(body, r.name...)
+	}
+
+	return s.send(req.command, errorNone, req.id, body)
+}
+
+// exit answers exit and lets the CPU run again.
+func (s *session) exit(req request) error {
+	if err := s.send(req.command, errorNone, req.id, nil); err != nil {
+		return err
+	}
+
+	return s.resume()
+}
+
+// quit answers quit and ends the session.
+func (s *session) quit(req request) error {
+	if err := s.send(req.command, errorNone, req.id, nil); err != nil {
+		return err
+	}
+
+	return errQuit
+}
+
+// reset answers reset: what to reset. Both a soft reset (0) and a hard
+// reset (1) run the reset sequence of the CPU, there are no drives to reset.
+func (s *session) reset(req request) error {
+	if req.body[0] > 1 {
+		return s.send(req.command, errorInvalidParameter, req.id, nil)
+	}
+
+	s.cpu.Reset()
+
+	return s.send(req.command, errorNone, req.id, nil)
+}
+
+// autostart answers autostart: run after loading, file index, the length of
+// the file name and the file name. The PRG file is loaded, and the program
+// counter is set to the SYS address of its BASIC stub. A program without one
+// fails rather than being started in what may be data. The file index is for
+// disk images, which are not supported.
+func (s *session) autostart(req request) error {
+	run := req.body[0] != 0
+	length := int(req.body[3])
+
+	if len(req.body) != 4+length {
+		return s.send(req.command, errorLength, req.id, nil)
+	}
+
+	if err := machine.LoadPRG(s.cpu, string(req.body[4:])); err != nil {
+		return s.send(req.command, errorGeneralFailure, req.id, nil)
+	}
+
+	if err := s.send(req.command, errorNone, req.id, nil); err != nil {
+		return err
+	}
+
+	if run {
+		return s.resume()
+	}
+
+	return nil
+}
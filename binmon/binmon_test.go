@@ -0,0 +1,526 @@
+package binmon
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// response is a response or an event from the server.
+type response struct {
+	responseType byte
+	errorCode    byte
+	requestID    uint32
+	body         []byte
+}
+
+// client is the frontend side of a session.
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	nextID uint32
+	done   chan error
+}
+
+// newClient starts a session for a CPU with a small program at $0200, and
+// stops the CPU at its start:
+//
+//	0200 LDA #$42
+//	0202 STA $1000
+//	0205 INX
+//	0206 JMP $0205
+func newClient(t *testing.T) (*client, *cpu6510.CPU) {
+	t.Helper()
+
+	cpu := cpu6510.NewCPU()
+	cpu.LoadAt(0x0200, []byte{0xA9, 0x42, 0x8D, 0x00, 0x10, 0xE8, 0x4C, 0x05, 0x02}, cpu6510.SetPCToLoadAddress())
+
+	serverConn, clientConn := net.Pipe()
+	c := &client{t: t, conn: clientConn, done: make(chan error, 1)}
+
+	go func() {
+		c.done <- NewServer(cpu).ServeConn(serverConn)
+		serverConn.Close()
+	}()
+
+	t.Cleanup(func() { clientConn.Close() })
+
+	if events := c.request(commandPing, nil); len(events) != 1 || events[0].responseType != eventStopped {
+		t.Fatalf("Ping should stop the running CPU, got %+v", events)
+	}
+	cpu.SetState(cpu6510.State{SP: 0xFF, PC: 0x0200, P: 0x20})
+
+	return c, cpu
+}
+
+// send sends a request and returns its request ID.
+func (c *client) send(command byte, body []byte) uint32 {
+	c.t.Helper()
+
+	c.nextID++
+
+	message := []byte{startByte, apiVersion}
+	message = binary.LittleEndian.AppendUint32(message, uint32(len(body)))
+	message = binary.LittleEndian.AppendUint32(message, c.nextID)
+	message = append(message, command)
+	message = append(message, body...)
+
+	if _, err := c.conn.Write(message); err != nil {
+		c.t.Fatalf("write error: %v", err)
+	}
+
+	return c.nextID
+}
+
+// read reads a response or an event.
+func (c *client) read() response {
+	c.t.Helper()
+
+	var header [12]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+
+	if header[0] != startByte || header[1] != apiVersion {
+		c.t.Fatalf("response should start with $02 $02, got % X", header[:2])
+	}
+
+	r := response{
+		responseType: header[6],
+		errorCode:    header[7],
+		requestID:    binary.LittleEndian.Uint32(header[8:12]),
+		body:         make([]byte, binary.LittleEndian.Uint32(header[2:6])),
+	}
+
+	if _, err := io.ReadFull(c.conn, r.body); err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+
+	return r
+}
+
+// request sends a request, and returns the messages before the response to
+// it, and the response last.
+func (c *client) request(command byte, body []byte) []response {
+	c.t.Helper()
+
+	id := c.send(command, body)
+
+	var messages []response
+	for {
+		r := c.read()
+		if r.requestID == id && r.responseType == command {
+			return messages
+		}
+		messages = append(messages, r)
+	}
+}
+
+// call sends a request and returns the response to it, which must not be
+// preceded by any events.
+func (c *client) call(command byte, body []byte) response {
+	c.t.Helper()
+
+	id := c.send(command, body)
+
+	r := c.read()
+	if r.requestID != id {
+		c.t.Fatalf("Request $%02X should be answered first, got %+v", command, r)
+	}
+
+	return r
+}
+
+// registerValues parses the body of a registers response.
+func registerValues(t *testing.T, body []byte) map[byte]uint16 {
+	t.Helper()
+
+	values := map[byte]uint16{}
+
+	count := int(binary.LittleEndian.Uint16(body))
+	items := body[2:]
+	for i := 0; i < count; i++ {
+		values[items[1]] = binary.LittleEndian.Uint16(items[2:4])
+		items = items[items[0]+1:]
+	}
+
+	return values
+}
+
+// checkpointSetBody returns the body of checkpoint set.
+func checkpointSetBody(start uint16, end uint16, stop bool, operation byte, temporary bool) []byte {
+	body := binary.LittleEndian.AppendUint16(nil, start)
+	body = binary.LittleEndian.AppendUint16(body, end)
+
+	return append(body, boolByte(stop), 1, operation, boolByte(temporary))
+}
+
+// exit lets the CPU run until it stops, and returns the events on the way.
+func (c *client) exit() []response {
+	c.t.Helper()
+
+	if r := c.call(commandExit, nil); r.errorCode != errorNone {
+		c.t.Fatalf("Exit should succeed, got error $%02X", r.errorCode)
+	}
+
+	var events []response
+	for {
+		r := c.read()
+		events = append(events, r)
+		if r.responseType == eventStopped || r.responseType == eventJam {
+			return events
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	c, cpu := newClient(t)
+
+	r := c.call(commandMemorySet, []byte{0, 0x00, 0xC0, 0x02, 0xC0, 0, 0, 0, 0x01, 0x02, 0x03})
+	if r.errorCode != errorNone {
+		t.Fatalf("Memory set should succeed, got error $%02X", r.errorCode)
+	}
+
+	if cpu.Peek(0xC000) != 0x01 || cpu.Peek(0xC002) != 0x03 {
+		t.Errorf("Memory set should write memory")
+	}
+
+	r = c.call(commandMemoryGet, []byte{0, 0x00, 0x02, 0x04, 0x02, 0, 0, 0})
+	if r.errorCode != errorNone || string(r.body) != "\x05\x00\xA9\x42\x8D\x00\x10" {
+		t.Errorf("Memory get should return the length and the bytes, got % X", r.body)
+	}
+
+	tests := []struct {
+		name     string
+		command  byte
+		body     []byte
+		expected byte
+	}{
+		{"Other memory space", commandMemoryGet, []byte{0, 0, 0, 0, 0, 1, 0, 0}, errorInvalidMemspace},
+		{"Short body", commandMemoryGet, []byte{0, 0, 0}, errorLength},
+		{"Too few bytes", commandMemorySet, []byte{0, 0x00, 0xC0, 0x01, 0xC0, 0, 0, 0, 0x01}, errorLength},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if r := c.call(test.command, test.body); r.errorCode != test.expected {
+				t.Errorf("Error should be $%02X, got $%02X", test.expected, r.errorCode)
+			}
+		})
+	}
+}
+
+func TestMemoryGetWholeMemory(t *testing.T) {
+	c, cpu := newClient(t)
+	cpu.Poke(0xFFFF, 0x37)
+
+	r := c.call(commandMemoryGet, []byte{0, 0x00, 0x00, 0xFF, 0xFF, 0, 0, 0})
+	if r.errorCode != errorNone || len(r.body) != 2+0x10000 {
+		t.Fatalf("Memory get should return all 64KB, got error $%02X and %d bytes", r.errorCode, len(r.body))
+	}
+
+	if binary.LittleEndian.Uint16(r.body) != 0 {
+		t.Errorf("Length should wrap around to 0 like in VICE, got %d", binary.LittleEndian.Uint16(r.body))
+	}
+
+	if r.body[2+0x0200] != 0xA9 || r.body[2+0xFFFF] != 0x37 {
+		t.Errorf("Memory get should return the bytes from $0000 to $FFFF")
+	}
+}
+
+func TestRegisters(t *testing.T) {
+	c, cpu := newClient(t)
+	cpu.SetState(cpu6510.State{A: 0x01, X: 0x02, Y: 0x03, SP: 0xFD, PC: 0xC000, P: 0x24})
+
+	r := c.call(commandRegistersGet, []byte{0})
+	expected := map[byte]uint16{0: 0x01, 1: 0x02, 2: 0x03, 3: 0xC000, 4: 0xFD, 5: 0x24}
+	for id, value := range registerValues(t, r.body) {
+		if expected[id] != value {
+			t.Errorf("Register %d should be $%04X, got $%04X", id, expected[id], value)
+		}
+	}
+
+	r = c.call(commandRegistersSet, []byte{0, 2, 0, 3, 0x00, 0x7F, 0x00, 3, 0x03, 0x00, 0x02})
+	if r.errorCode != errorNone || registerValues(t, r.body)[3] != 0x0200 {
+		t.Errorf("Registers set should return the registers, got error $%02X", r.errorCode)
+	}
+
+	if cpu.A() != 0x7F || cpu.PC() != 0x0200 {
+		t.Errorf("Registers set should set A and PC, got $%02X and $%04X", cpu.A(), cpu.PC())
+	}
+
+	if r := c.call(commandRegistersSet, []byte{0, 1, 0, 3, 0x09, 0x00, 0x00}); r.errorCode != errorObjectMissing {
+		t.Errorf("Unknown register should fail, got error $%02X", r.errorCode)
+	}
+
+	r = c.call(commandRegistersAvailable, []byte{0})
+	if r.errorCode != errorNone || binary.LittleEndian.Uint16(r.body) != 6 || string(r.body[2:8]) != "\x04\x00\x08\x01A\x04" {
+		t.Errorf("Registers available should describe the registers, got % X", r.body)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	c, cpu := newClient(t)
+
+	events := c.request(commandAdvance, []byte{0, 2, 0})
+	if len(events) != 0 {
+		t.Errorf("Advance should respond before it runs, got %+v", events)
+	}
+
+	if r := c.read(); r.responseType != eventStopped || binary.LittleEndian.Uint16(r.body) != 0x0205 {
+		t.Errorf("Advance should stop after two instructions, got %+v", r)
+	}
+
+	if cpu.Peek(0x1000) != 0x42 {
+		t.Errorf("Advance should have stored the accumulator")
+	}
+}
+
+func TestAdvanceThroughTracepoint(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x0202, 0x0202, false, operationExec, false))
+
+	c.request(commandAdvance, []byte{0, 3, 0})
+
+	if r := c.read(); r.responseType != commandCheckpointGet || r.body[4] != 1 {
+		t.Errorf("Tracepoint should be reported as hit, got %+v", r)
+	}
+
+	if r := c.read(); r.responseType != eventStopped || binary.LittleEndian.Uint16(r.body) != 0x0206 {
+		t.Errorf("Advance should run all three instructions and stop, got %+v", r)
+	}
+
+	if cpu.X() != 1 {
+		t.Errorf("Advance should have run the INX")
+	}
+}
+
+func TestAdvanceOverSubroutine(t *testing.T) {
+	c, cpu := newClient(t)
+
+	// 0300 JSR $0310, 0310 INY, 0311 RTS
+	c.call(commandMemorySet, []byte{0, 0x00, 0x03, 0x02, 0x03, 0, 0, 0, 0x20, 0x10, 0x03})
+	c.call(commandMemorySet, []byte{0, 0x10, 0x03, 0x11, 0x03, 0, 0, 0, 0xC8, 0x60})
+	c.call(commandRegistersSet, []byte{0, 1, 0, 3, 0x03, 0x00, 0x03})
+
+	c.request(commandAdvance, []byte{1, 1, 0})
+
+	if r := c.read(); r.responseType != eventStopped || binary.LittleEndian.Uint16(r.body) != 0x0303 {
+		t.Errorf("Advance should step over the subroutine, got %+v", r)
+	}
+
+	if cpu.Y() != 1 {
+		t.Errorf("Subroutine should have run")
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	c, cpu := newClient(t)
+
+	r := c.call(commandCheckpointSet, checkpointSetBody(0x0206, 0x0206, true, operationExec, false))
+	if r.responseType != commandCheckpointGet || binary.LittleEndian.Uint32(r.body) != 1 {
+		t.Fatalf("Checkpoint set should return the checkpoint info, got %+v", r)
+	}
+
+	events := c.exit()
+	if len(events) != 3 || events[0].responseType != eventResumed || events[1].responseType != commandCheckpointGet || events[1].body[4] != 1 {
+		t.Fatalf("Checkpoint should be reported as hit, got %+v", events)
+	}
+
+	if cpu.PC() != 0x0206 || binary.LittleEndian.Uint16(events[2].body) != 0x0206 {
+		t.Errorf("CPU should stop at the checkpoint, got $%04X", cpu.PC())
+	}
+
+	if r := c.call(commandCheckpointToggle, []byte{1, 0, 0, 0, 0}); r.errorCode != errorNone {
+		t.Errorf("Checkpoint toggle should succeed, got error $%02X", r.errorCode)
+	}
+
+	r = c.call(commandCheckpointGet, []byte{1, 0, 0, 0})
+	if r.errorCode != errorNone || r.body[10] != 0 || binary.LittleEndian.Uint32(r.body[13:17]) != 1 {
+		t.Errorf("Checkpoint should be disabled and hit once, got % X", r.body)
+	}
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x1000, 0x1000, true, operationStore, false))
+
+	id := c.send(commandCheckpointList, nil)
+	var infos int
+	for {
+		r := c.read()
+		if r.requestID != id {
+			t.Fatalf("Checkpoint list should only send responses, got %+v", r)
+		}
+		if r.responseType == commandCheckpointList {
+			if infos != 2 || binary.LittleEndian.Uint32(r.body) != 2 {
+				t.Errorf("Checkpoint list should list two checkpoints, got %d", infos)
+			}
+			break
+		}
+		infos++
+	}
+
+	if r := c.call(commandCheckpointDelete, []byte{1, 0, 0, 0}); r.errorCode != errorNone {
+		t.Errorf("Checkpoint delete should succeed, got error $%02X", r.errorCode)
+	}
+
+	if r := c.call(commandCheckpointGet, []byte{1, 0, 0, 0}); r.errorCode != errorObjectMissing {
+		t.Errorf("Deleted checkpoint should be missing, got error $%02X", r.errorCode)
+	}
+
+	if r := c.call(commandCheckpointSet, append(checkpointSetBody(0, 0, true, operationExec, false), 1)); r.errorCode != errorInvalidMemspace {
+		t.Errorf("Checkpoint in another memory space should fail, got error $%02X", r.errorCode)
+	}
+}
+
+func TestStoreCheckpoint(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x1000, 0x1000, true, operationStore, true))
+
+	events := c.exit()
+	if len(events) != 3 || events[1].responseType != commandCheckpointGet || cpu.PC() != 0x0205 {
+		t.Errorf("Store should stop the CPU after the store, got %+v at $%04X", events, cpu.PC())
+	}
+
+	if len(cpu.Breakpoints()) != 0 {
+		t.Errorf("Temporary checkpoint should be deleted after it is hit")
+	}
+}
+
+func TestTracepoint(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x0202, 0x0202, false, operationExec, false))
+	c.call(commandCheckpointSet, checkpointSetBody(0x0206, 0x0206, true, operationExec, false))
+
+	events := c.exit()
+	if len(events) != 4 || binary.LittleEndian.Uint32(events[1].body) != 1 || binary.LittleEndian.Uint32(events[2].body) != 2 {
+		t.Errorf("Tracepoint should be reported without stopping, got %+v", events)
+	}
+
+	if cpu.PC() != 0x0206 {
+		t.Errorf("CPU should stop at the second checkpoint, got $%04X", cpu.PC())
+	}
+}
+
+func TestConditionSet(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x0206, 0x0206, true, operationExec, false))
+
+	condition := "X == $03"
+	body := append([]byte{1, 0, 0, 0, byte(len(condition))}, condition...)
+	if r := c.call(commandConditionSet, body); r.errorCode != errorNone {
+		t.Fatalf("Condition set should succeed, got error $%02X", r.errorCode)
+	}
+
+	c.exit()
+
+	if cpu.X() != 3 {
+		t.Errorf("CPU should stop when the condition holds, got X = $%02X", cpu.X())
+	}
+
+	invalid := "X =="
+	body = append([]byte{1, 0, 0, 0, byte(len(invalid))}, invalid...)
+	if r := c.call(commandConditionSet, body); r.errorCode != errorInvalidParameter {
+		t.Errorf("Invalid condition should fail, got error $%02X", r.errorCode)
+	}
+}
+
+func TestJam(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandMemorySet, []byte{0, 0x05, 0x02, 0x05, 0x02, 0, 0, 0, 0x02})
+
+	events := c.exit()
+	if last := events[len(events)-1]; last.responseType != eventJam || binary.LittleEndian.Uint16(last.body) != 0x0205 {
+		t.Errorf("JAM should be reported, got %+v", last)
+	}
+
+	if !cpu.State().Jammed {
+		t.Errorf("CPU should be jammed")
+	}
+
+	if r := c.call(commandReset, []byte{0}); r.errorCode != errorNone || cpu.State().Jammed {
+		t.Errorf("Reset should bring the CPU back to life, got error $%02X", r.errorCode)
+	}
+}
+
+func TestAutostart(t *testing.T) {
+	c, cpu := newClient(t)
+
+	// 10 SYS 2061, followed by INX and JMP $080D.
+	path := filepath.Join(t.TempDir(), "program.prg")
+	prg := []byte{0x01, 0x08, 0x0B, 0x08, 0x0A, 0x00, 0x9E, '2', '0', '6', '1', 0x00, 0x00, 0x00, 0xE8, 0x4C, 0x0D, 0x08}
+	if err := os.WriteFile(path, prg, 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	body := append([]byte{0, 0, 0, byte(len(path))}, path...)
+	if r := c.call(commandAutostart, body); r.errorCode != errorNone {
+		t.Fatalf("Autostart should succeed, got error $%02X", r.errorCode)
+	}
+
+	if cpu.PC() != 0x080D || cpu.Peek(0x080D) != 0xE8 {
+		t.Errorf("Autostart should load the program and set PC to $080D, got $%04X", cpu.PC())
+	}
+
+	data := filepath.Join(t.TempDir(), "data.prg")
+	if err := os.WriteFile(data, []byte{0x00, 0xC0, 0xE8, 0x4C, 0x00, 0xC0}, 0o644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	body = append([]byte{0, 0, 0, byte(len(data))}, data...)
+	if r := c.call(commandAutostart, body); r.errorCode != errorGeneralFailure || cpu.PC() != 0x080D {
+		t.Errorf("Program without a SYS line should fail, got error $%02X", r.errorCode)
+	}
+
+	body = append([]byte{0, 0, 0, 7}, "missing"...)
+	if r := c.call(commandAutostart, body); r.errorCode != errorGeneralFailure {
+		t.Errorf("Missing file should fail, got error $%02X", r.errorCode)
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	c, _ := newClient(t)
+
+	if r := c.call(0x42, nil); r.errorCode != errorInvalidCommand {
+		t.Errorf("Unknown command should fail, got error $%02X", r.errorCode)
+	}
+
+	if _, err := c.conn.Write([]byte{startByte, 0x01, 0, 0, 0, 0, 9, 0, 0, 0, commandPing}); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if r := c.read(); r.requestID != 9 || r.errorCode != errorAPIVersion {
+		t.Errorf("Other API version should fail, got %+v", r)
+	}
+
+	r := c.call(commandBanksAvailable, nil)
+	if r.errorCode != errorNone || string(r.body) != "\x01\x00\x06\x00\x00\x03cpu" {
+		t.Errorf("Banks available should return the cpu bank, got % X", r.body)
+	}
+}
+
+func TestQuit(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.call(commandCheckpointSet, checkpointSetBody(0x0206, 0x0206, true, operationExec, false))
+
+	if r := c.call(commandQuit, nil); r.errorCode != errorNone {
+		t.Errorf("Quit should succeed, got error $%02X", r.errorCode)
+	}
+
+	if err := <-c.done; err != nil {
+		t.Errorf("Session should end without an error, got %v", err)
+	}
+
+	if len(cpu.Breakpoints()) != 0 {
+		t.Errorf("Checkpoints of the frontend should be removed")
+	}
+}
@@ -2,91 +2,57 @@ package main
 
 import (
 	"bytes"
-	"io"
-	"os"
+	"strings"
 	"testing"
 )
 
-func captureStdout(t *testing.T, fn func()) string {
-	t.Helper()
-
-	original := os.Stdout
-	reader, writer, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("os.Pipe: %v", err)
-	}
-	os.Stdout = writer
-
-	fn()
-
-	writer.Close()
-	os.Stdout = original
-
-	out, err := io.ReadAll(reader)
-	if err != nil {
-		t.Fatalf("ReadAll: %v", err)
-	}
-	return string(out)
-}
-
-func TestGetByteAddress(t *testing.T) {
-	buffer := bytes.NewReader([]byte{0xAB})
-	addr, err := getByteAddress(buffer)
-	if err != nil {
-		t.Fatalf("getByteAddress error: %v", err)
-	}
-	if addr != 0xAB {
-		t.Fatalf("expected 0xAB, got 0x%02X", addr)
-	}
-}
-
-func TestGetWordAddress(t *testing.T) {
-	buffer := bytes.NewReader([]byte{0x34, 0x12})
-	addr, err := getWordAddress(buffer)
-	if err != nil {
-		t.Fatalf("getWordAddress error: %v", err)
-	}
-	if addr != 0x1234 {
-		t.Fatalf("expected 0x1234, got 0x%04X", addr)
-	}
-}
-
 func TestRunDisassemblesSequence(t *testing.T) {
-	// LDA #$10, ORA ($20), X, LDA $44, X, STA $5678, X, ASL A, BRK
+	// LDA #$10, ORA ($20,X), LDA $44,X, STA $5678,X, ASL A, BEQ $0010, BRK
 	buffer := bytes.NewReader([]byte{
 		0xA9, 0x10,
 		0x01, 0x20,
 		0xB5, 0x44,
 		0x9D, 0x78, 0x56,
 		0x0A,
+		0xF0, 0x04,
 		0x00,
 	})
 
-	output := captureStdout(t, func() {
-		if err := run(buffer); err != nil {
-			t.Fatalf("run error: %v", err)
-		}
-	})
+	var output strings.Builder
+	if err := run(buffer, &output); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
 
 	expected := "" +
 		"LDA #$10\n" +
-		"ORA ($20), X\n" +
-		"LDA $44, X\n" +
-		"STA $5678, X\n" +
+		"ORA ($20,X)\n" +
+		"LDA $44,X\n" +
+		"STA $5678,X\n" +
 		"ASL A\n" +
+		"BEQ $0010\n" +
 		"BRK\n"
 
-	if output != expected {
-		t.Fatalf("unexpected output:\nexpected:\n%q\ngot:\n%q", expected, output)
+	if output.String() != expected {
+		t.Fatalf("unexpected output:\nexpected:\n%q\ngot:\n%q", expected, output.String())
 	}
 }
 
-func TestDisassembleUnknownInstructionPanics(t *testing.T) {
-	defer func() {
-		if recovered := recover(); recovered == nil {
-			t.Fatalf("expected panic for unknown instruction")
-		}
-	}()
+func TestRunDisassemblesUndocumentedOpcodes(t *testing.T) {
+	var output strings.Builder
+	if err := run(bytes.NewReader([]byte{0xA7, 0x10, 0x02}), &output); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+
+	if output.String() != "LAX $10\nJAM\n" {
+		t.Fatalf("unexpected output: %q", output.String())
+	}
+}
+
+func TestRunCutOffInstruction(t *testing.T) {
+	var output strings.Builder
+	err := run(bytes.NewReader([]byte{0xEA, 0x8D, 0x00}), &output)
 
-	disassemble(bytes.NewReader(nil), 0x02)
+	if err == nil || output.String() != "NOP\n" {
+		t.Fatalf("expected an error after NOP, got %v and %q", err, output.String())
+	}
 }
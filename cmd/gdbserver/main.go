@@ -0,0 +1,44 @@
+// Command gdbserver lets a debugger that speaks the GDB remote serial
+// protocol attach to a CPU6510, see the gdbstub package for the supported
+// packets. Without any ROMs the CPU is connected to 64KB of RAM, with ROMs it
+// gets the memory map of the C64 and starts from the reset vector of the
+// KERNAL.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/stefanalfbo/commodore64/gdbstub"
+	"github.com/stefanalfbo/commodore64/internal/machine"
+	"github.com/stefanalfbo/commodore64/rom"
+)
+
+func main() {
+	var paths rom.Paths
+	paths.RegisterFlags(flag.CommandLine)
+	prg := flag.String("prg", "", "PRG file with a BASIC stub to load, starting at its SYS address")
+	address := flag.String("listen", "localhost:6510", "TCP address to listen on for the debugger")
+	flag.Parse()
+
+	cpu, err := machine.New(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up the CPU: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *prg != "" {
+		if err := machine.LoadPRG(cpu, *prg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load %q: %v\n", *prg, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Waiting for a debugger on %s\n", *address)
+
+	if err := gdbstub.NewServer(cpu).ListenAndServe(*address); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to serve: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+// Command monitor is an interactive machine-language monitor for the
+// CPU6510, with commands that follow the syntax of the VICE monitor. Without
+// any ROMs the CPU is connected to 64KB of RAM, with ROMs it gets the memory
+// map of the C64 and starts from the reset vector of the KERNAL.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/stefanalfbo/commodore64/internal/machine"
+	"github.com/stefanalfbo/commodore64/rom"
+)
+
+func main() {
+	var paths rom.Paths
+	paths.RegisterFlags(flag.CommandLine)
+	prg := flag.String("prg", "", "PRG file with a BASIC stub to load, starting at its SYS address")
+	flag.Parse()
+
+	cpu, err := machine.New(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up the CPU: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *prg != "" {
+		if err := machine.LoadPRG(cpu, *prg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load %q: %v\n", *prg, err)
+			os.Exit(1)
+		}
+	}
+
+	m := newMonitor(cpu, os.Stdout)
+	m.interrupt = func() (context.Context, context.CancelFunc) {
+		return signal.NotifyContext(context.Background(), os.Interrupt)
+	}
+
+	if err := m.repl(os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read command: %v\n", err)
+		os.Exit(1)
+	}
+}
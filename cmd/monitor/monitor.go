@@ -0,0 +1,944 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// The number of bytes m shows, and the number of instructions d shows, when
+// no end address is given.
+const (
+	defaultDumpLength        = 0x80
+	defaultDisassembleLength = 16
+)
+
+// monitor is a machine-language monitor for the CPU, with commands that
+// follow the syntax of the VICE monitor. Numbers are hexadecimal, with or
+// without a leading $.
+type monitor struct {
+	cpu *cpu6510.CPU
+	out io.Writer
+	// Returns a context that is cancelled when the user interrupts the
+	// running CPU.
+	interrupt func() (context.Context, context.CancelFunc)
+	// The addresses m and d continue from when no address is given.
+	dumpAddress        uint16
+	disassembleAddress uint16
+	// True once the user has asked to leave the monitor.
+	quit bool
+}
+
+// command is a monitor command, which gets the arguments that follow its
+// name.
+type command struct {
+	run  func(m *monitor, args []string) error
+	help string
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"m":       {(*monitor).memory, "m [start [end]]                  dump memory"},
+		"d":       {(*monitor).disassemble, "d [start [end]]                  disassemble"},
+		"r":       {(*monitor).registers, "r [reg = value [, ...]]          show or set registers"},
+		"g":       {(*monitor).goTo, "g [address]                      run until a breakpoint or interrupt"},
+		"z":       {(*monitor).step, "z [count]                        step into"},
+		"n":       {(*monitor).next, "n [count]                        step over subroutine calls"},
+		"break":   {(*monitor).breakpoint, "break [load|store|exec] [start [end]] [if cond]"},
+		"watch":   {(*monitor).watch, "watch [load|store] [start [end]] [if cond]"},
+		"del":     {(*monitor).delete, "del [number]                     delete a breakpoint, or all"},
+		"enable":  {(*monitor).enable, "enable number                    enable a breakpoint"},
+		"disable": {(*monitor).disable, "disable number                   disable a breakpoint"},
+		"ignore":  {(*monitor).ignore, "ignore number [count]            ignore hits of a breakpoint"},
+		"f":       {(*monitor).fill, "f start end data                 fill memory"},
+		"t":       {(*monitor).transfer, "t start end destination          copy memory"},
+		"h":       {(*monitor).hunt, "h start end data                 search memory"},
+		"l":       {(*monitor).load, "l \"file\" device [address]        load a PRG file"},
+		"s":       {(*monitor).save, "s \"file\" device start end        save a PRG file"},
+		"x":       {(*monitor).exit, "x                                leave the monitor"},
+		"help":    {(*monitor).help, "help                             show the commands"},
+	}
+
+	for alias, name := range map[string]string{
+		"mem": "m", "disass": "d", "registers": "r", "goto": "g", "step": "z",
+		"next": "n", "delete": "del", "fill": "f", "move": "t", "hunt": "h",
+		"load": "l", "save": "s", "exit": "x", "quit": "x", "q": "x", "?": "help",
+		"bk": "break", "w": "watch",
+	} {
+		commands[alias] = commands[name]
+	}
+}
+
+// newMonitor creates a monitor for the CPU, which writes to out.
+func newMonitor(cpu *cpu6510.CPU, out io.Writer) *monitor {
+	return &monitor{
+		cpu: cpu,
+		out: out,
+		interrupt: func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		},
+		dumpAddress:        cpu.PC(),
+		disassembleAddress: cpu.PC(),
+	}
+}
+
+// repl reads commands from in until the end of the input or until the user
+// leaves the monitor.
+func (m *monitor) repl(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+
+	for !m.quit {
+		fmt.Fprintf(m.out, "(C:$%04x) ", m.cpu.PC())
+
+		if !scanner.Scan() {
+			fmt.Fprintln(m.out)
+			return scanner.Err()
+		}
+
+		if err := m.execute(scanner.Text()); err != nil {
+			fmt.Fprintf(m.out, "ERROR -- %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// execute runs a command line.
+func (m *monitor) execute(line string) error {
+	args, err := splitArguments(line)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	cmd, ok := commands[strings.ToLower(args[0])]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return cmd.run(m, args[1:])
+}
+
+// splitArguments splits the command line into its arguments, which are
+// separated by spaces or commas. A string in double quotes is a single
+// argument, which keeps its quotes.
+func splitArguments(line string) ([]string, error) {
+	var args []string
+
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == ' ' || line[i] == '\t' || line[i] == ',':
+			i++
+		case line[i] == '"':
+			end := strings.IndexByte(line[i+1:], '"')
+			if end < 0 {
+				return nil, errors.New("missing closing quote")
+			}
+			args = append(args, line[i:i+end+2])
+			i += end + 2
+		default:
+			end := strings.IndexAny(line[i:], " \t,\"")
+			if end < 0 {
+				end = len(line) - i
+			}
+			args = append(args, line[i:i+end])
+			i += end
+		}
+	}
+
+	return args, nil
+}
+
+// parseValue parses a hexadecimal number, with or without a leading $.
+func parseValue(arg string, bits int) (uint64, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(arg, "$"), 16, bits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", arg)
+	}
+
+	return value, nil
+}
+
+// parseAddress parses an address, which may have the C: prefix of the
+// memory space of the computer.
+func parseAddress(arg string) (uint16, error) {
+	if len(arg) > 2 && strings.EqualFold(arg[:2], "c:") {
+		arg = arg[2:]
+	}
+
+	value, err := parseValue(arg, 16)
+
+	return uint16(value), err
+}
+
+// hexCondition makes the numbers in the condition hexadecimal, like every
+// other number in the monitor, by giving a leading $ to the ones that start
+// with a digit. Registers start with a letter or a dot, and are left alone.
+func hexCondition(condition string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(condition); {
+		j := i
+		for j < len(condition) && (unicode.IsLetter(rune(condition[j])) || unicode.IsDigit(rune(condition[j]))) {
+			j++
+		}
+
+		if j == i {
+			b.WriteByte(condition[i])
+			i++
+			continue
+		}
+
+		if unicode.IsDigit(rune(condition[i])) && (i == 0 || !strings.ContainsRune("$%.", rune(condition[i-1]))) {
+			b.WriteByte('$')
+		}
+		b.WriteString(condition[i:j])
+		i = j
+	}
+
+	return b.String()
+}
+
+// parseRange parses the start and end address of a range.
+func parseRange(args []string) (uint16, uint16, error) {
+	if len(args) < 2 {
+		return 0, 0, errors.New("missing address range")
+	}
+
+	start, err := parseAddress(args[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := parseAddress(args[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("range $%04x-$%04x is empty", start, end)
+	}
+
+	return start, end, nil
+}
+
+// parseData parses a list of bytes, where a string in double quotes stands
+// for its characters.
+func parseData(args []string) ([]byte, error) {
+	var data []byte
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "\"") {
+			data = append(data, strings.Trim(arg, "\"")...)
+			continue
+		}
+
+		value, err := parseValue(arg, 8)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, byte(value))
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("missing data")
+	}
+
+	return data, nil
+}
+
+// parseFilename parses a file name in double quotes.
+func parseFilename(arg string) (string, error) {
+	if len(arg) < 2 || !strings.HasPrefix(arg, "\"") {
+		return "", fmt.Errorf("file name must be in double quotes, got %s", arg)
+	}
+
+	return strings.Trim(arg, "\""), nil
+}
+
+// memory dumps memory, 16 bytes per line, followed by the bytes as text.
+func (m *monitor) memory(args []string) error {
+	start, end, err := m.optionalRange(args, m.dumpAddress, defaultDumpLength)
+	if err != nil {
+		return err
+	}
+
+	address := int(start)
+	for address <= int(end) {
+		count := min(16, int(end)-address+1)
+
+		var hex, text strings.Builder
+		for i := 0; i < 16; i++ {
+			if i > 0 && i%4 == 0 {
+				hex.WriteString(" ")
+			}
+
+			if i >= count {
+				hex.WriteString("   ")
+				continue
+			}
+
+			value := m.cpu.Peek(uint16(address + i))
+			fmt.Fprintf(&hex, "%02x ", value)
+
+			if value >= 0x20 && value < 0x7F {
+				text.WriteByte(value)
+			} else {
+				text.WriteByte('.')
+			}
+		}
+
+		fmt.Fprintf(m.out, ">C:%04x  %s  %s\n", address, hex.String(), text.String())
+		address += count
+	}
+
+	m.dumpAddress = uint16(address)
+
+	return nil
+}
+
+// optionalRange parses an optional start and end address, where the start
+// defaults to the given address and the end to the given length from the
+// start.
+func (m *monitor) optionalRange(args []string, start uint16, length int) (uint16, uint16, error) {
+	var err error
+
+	if len(args) > 0 {
+		if start, err = parseAddress(args[0]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	end := uint16(min(int(start)+length-1, 0xFFFF))
+
+	if len(args) > 1 {
+		return parseRange(args)
+	}
+
+	return start, end, nil
+}
+
+// disassembleLine writes the disassembly of the instruction at the address,
+// and returns the address of the next instruction.
+func (m *monitor) disassembleLine(address uint16) uint16 {
+	disassembly := cpu6510.Disassemble(m.cpu.Peek, address)
+
+	var code []string
+	for _, value := range disassembly.Bytes {
+		code = append(code, fmt.Sprintf("%02X", value))
+	}
+
+	fmt.Fprintf(m.out, ".C:%04x  %-10s %s\n", address, strings.Join(code, " "), disassembly.Text)
+
+	return address + uint16(len(disassembly.Bytes))
+}
+
+// disassemble disassembles the instructions in the range, or a screenful of
+// them when no end address is given.
+func (m *monitor) disassemble(args []string) error {
+	address := m.disassembleAddress
+
+	if len(args) > 0 {
+		var err error
+		if address, err = parseAddress(args[0]); err != nil {
+			return err
+		}
+	}
+
+	if len(args) > 1 {
+		start, end, err := parseRange(args)
+		if err != nil {
+			return err
+		}
+
+		for address = start; address >= start && address <= end; {
+			address = m.disassembleLine(address)
+		}
+	} else {
+		for i := 0; i < defaultDisassembleLength; i++ {
+			address = m.disassembleLine(address)
+		}
+	}
+
+	m.disassembleAddress = address
+
+	return nil
+}
+
+// registers shows the registers, or sets them from assignments like
+// A = 10, X = 20.
+func (m *monitor) registers(args []string) error {
+	if len(args) > 0 {
+		return m.setRegisters(strings.Fields(strings.ReplaceAll(strings.Join(args, " "), "=", " = ")))
+	}
+
+	state := m.cpu.State()
+
+	fmt.Fprintln(m.out, "  ADDR A  X  Y  SP 00 01 NV-BDIZC CYC")
+	fmt.Fprintf(m.out, ".;%04x %02x %02x %02x %02x %02x %02x %08b %d\n",
+		state.PC, state.A, state.X, state.Y, state.SP,
+		m.cpu.Peek(0x0000), m.cpu.Peek(0x0001), state.P, state.Cycles)
+
+	return nil
+}
+
+// setRegisters sets the registers from a list of name, =, value.
+func (m *monitor) setRegisters(fields []string) error {
+	state := m.cpu.State()
+
+	for len(fields) > 0 {
+		if len(fields) < 3 || fields[1] != "=" {
+			return errors.New("expected register = value")
+		}
+
+		bits := 8
+		name := strings.ToUpper(fields[0])
+		if name == "PC" || name == "ADDR" {
+			bits = 16
+		}
+
+		value, err := parseValue(fields[2], bits)
+		if err != nil {
+			return err
+		}
+
+		switch name {
+		case "A":
+			state.A = byte(value)
+		case "X":
+			state.X = byte(value)
+		case "Y":
+			state.Y = byte(value)
+		case "SP":
+			state.SP = byte(value)
+		case "P", "FL":
+			state.P = byte(value)
+		case "PC", "ADDR":
+			state.PC = uint16(value)
+		default:
+			return fmt.Errorf("unknown register %q", fields[0])
+		}
+
+		fields = fields[3:]
+	}
+
+	m.cpu.SetState(state)
+	m.disassembleAddress = state.PC
+
+	return nil
+}
+
+// goTo runs the CPU, from the address when one is given.
+func (m *monitor) goTo(args []string) error {
+	if len(args) > 0 {
+		address, err := parseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		m.cpu.SetPC(address)
+	}
+
+	m.run(nil)
+
+	return nil
+}
+
+// step executes the given number of instructions, one by default.
+func (m *monitor) step(args []string) error {
+	count, err := parseCount(args)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		if _, err := m.cpu.Step(); err != nil {
+			m.stopped(err)
+			return nil
+		}
+	}
+
+	m.disassembleAddress = m.disassembleLine(m.cpu.PC())
+
+	return nil
+}
+
+// next executes the given number of instructions, one by default, and runs a
+// subroutine that is called by JSR until it returns.
+func (m *monitor) next(args []string) error {
+	count, err := parseCount(args)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < count; i++ {
+		pc := m.cpu.PC()
+		sp := m.cpu.SP()
+
+		if m.cpu.Peek(pc) != 0x20 {
+			if _, err := m.cpu.Step(); err != nil {
+				m.stopped(err)
+				return nil
+			}
+			continue
+		}
+
+		returned := m.run(func(s cpu6510.State) bool {
+			return s.PC == pc+3 && s.SP == sp
+		})
+		if !returned {
+			return nil
+		}
+	}
+
+	m.disassembleAddress = m.disassembleLine(m.cpu.PC())
+
+	return nil
+}
+
+// parseCount parses an optional count, which is one by default.
+func parseCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+
+	count, err := parseValue(args[0], 16)
+
+	return int(count), err
+}
+
+// run executes instructions until the condition holds, a breakpoint stops the
+// CPU, or the user interrupts it. It returns true when the condition holds.
+func (m *monitor) run(until func(cpu6510.State) bool) bool {
+	ctx, cancel := m.interrupt()
+	defer cancel()
+
+	for until == nil || !until(m.cpu.State()) {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(m.out, "Interrupted")
+			m.disassembleAddress = m.disassembleLine(m.cpu.PC())
+			return false
+		default:
+		}
+
+		if _, err := m.cpu.Step(); err != nil {
+			m.stopped(err)
+			return false
+		}
+	}
+
+	return true
+}
+
+// stopped reports why the CPU stopped, and where.
+func (m *monitor) stopped(err error) {
+	var hit *cpu6510.BreakpointError
+
+	if errors.As(err, &hit) {
+		fmt.Fprintf(m.out, "#%d (Stop on %s %04x)\n", hit.Breakpoint.ID, accessName(hit.Access), hit.Address)
+	} else {
+		fmt.Fprintln(m.out, err)
+	}
+
+	m.disassembleAddress = m.disassembleLine(m.cpu.PC())
+}
+
+// accessName returns the names VICE uses for the kinds of access.
+func accessName(access cpu6510.Access) string {
+	var names []string
+
+	if access&cpu6510.AccessExec != 0 {
+		names = append(names, "exec")
+	}
+	if access&cpu6510.AccessRead != 0 {
+		names = append(names, "load")
+	}
+	if access&cpu6510.AccessWrite != 0 {
+		names = append(names, "store")
+	}
+
+	return strings.Join(names, " ")
+}
+
+// The kinds of access that can be given to break and watch.
+var accessKinds = map[string]cpu6510.Access{
+	"load":  cpu6510.AccessRead,
+	"store": cpu6510.AccessWrite,
+	"exec":  cpu6510.AccessExec,
+}
+
+// addBreakpoint parses the arguments of break and watch, which are optional
+// kinds of access, a range and a condition, and adds the breakpoint or lists
+// the breakpoints when there is no address.
+func (m *monitor) addBreakpoint(args []string, label string, access cpu6510.Access) error {
+	var kinds cpu6510.Access
+	for len(args) > 0 && accessKinds[strings.ToLower(args[0])] != 0 {
+		kinds |= accessKinds[strings.ToLower(args[0])]
+		args = args[1:]
+	}
+
+	if kinds != 0 {
+		access = kinds
+	}
+
+	var condition string
+	for i, arg := range args {
+		if strings.EqualFold(arg, "if") {
+			condition = strings.Join(args[i+1:], " ")
+			args = args[:i]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		m.listBreakpoints()
+		return nil
+	}
+
+	breakpoint := cpu6510.Breakpoint{Access: access, Condition: hexCondition(condition)}
+
+	var err error
+	if breakpoint.Start, err = parseAddress(args[0]); err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		if breakpoint.Start, breakpoint.End, err = parseRange(args); err != nil {
+			return err
+		}
+	}
+
+	id, err := m.cpu.AddBreakpoint(breakpoint)
+	if err != nil {
+		return err
+	}
+
+	for _, added := range m.cpu.Breakpoints() {
+		if added.ID == id {
+			m.printBreakpoint(label, added)
+		}
+	}
+
+	return nil
+}
+
+// breakpoint adds a breakpoint, which stops on exec by default.
+func (m *monitor) breakpoint(args []string) error {
+	return m.addBreakpoint(args, "BREAK", cpu6510.AccessExec)
+}
+
+// watch adds a watchpoint, which stops on load and store by default.
+func (m *monitor) watch(args []string) error {
+	return m.addBreakpoint(args, "WATCH", cpu6510.AccessRead|cpu6510.AccessWrite)
+}
+
+// printBreakpoint writes a line that describes the breakpoint.
+func (m *monitor) printBreakpoint(label string, breakpoint cpu6510.Breakpoint) {
+	address := fmt.Sprintf("C:$%04x", breakpoint.Start)
+	if breakpoint.End != breakpoint.Start {
+		address += fmt.Sprintf("-$%04x", breakpoint.End)
+	}
+
+	line := fmt.Sprintf("%s: %d  %s  (Stop on %s)", label, breakpoint.ID, address, accessName(breakpoint.Access))
+	if breakpoint.Disabled {
+		line += " disabled"
+	}
+	if breakpoint.Condition != "" {
+		line += " if " + breakpoint.Condition
+	}
+	if breakpoint.Hits > 0 {
+		line += fmt.Sprintf(" hits %d", breakpoint.Hits)
+	}
+
+	fmt.Fprintln(m.out, line)
+}
+
+// listBreakpoints lists all breakpoints.
+func (m *monitor) listBreakpoints() {
+	breakpoints := m.cpu.Breakpoints()
+	if len(breakpoints) == 0 {
+		fmt.Fprintln(m.out, "No breakpoints are set")
+		return
+	}
+
+	for _, breakpoint := range breakpoints {
+		label := "BREAK"
+		if breakpoint.Access&cpu6510.AccessExec == 0 {
+			label = "WATCH"
+		}
+		m.printBreakpoint(label, breakpoint)
+	}
+}
+
+// parseID parses the number of a breakpoint, which is decimal like in VICE.
+func parseID(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("missing breakpoint number")
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid breakpoint number %q", args[0])
+	}
+
+	return id, nil
+}
+
+// delete deletes a breakpoint, or all of them when no number is given.
+func (m *monitor) delete(args []string) error {
+	if len(args) == 0 {
+		m.cpu.ClearBreakpoints()
+		return nil
+	}
+
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	if !m.cpu.RemoveBreakpoint(id) {
+		return fmt.Errorf("no breakpoint %d", id)
+	}
+
+	return nil
+}
+
+// enable turns a breakpoint on.
+func (m *monitor) enable(args []string) error {
+	return m.setEnabled(args, true)
+}
+
+// disable turns a breakpoint off.
+func (m *monitor) disable(args []string) error {
+	return m.setEnabled(args, false)
+}
+
+func (m *monitor) setEnabled(args []string, enabled bool) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	if !m.cpu.EnableBreakpoint(id, enabled) {
+		return fmt.Errorf("no breakpoint %d", id)
+	}
+
+	return nil
+}
+
+// ignore lets the given number of hits of a breakpoint pass, one by default.
+func (m *monitor) ignore(args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	count := 1
+	if len(args) > 1 {
+		if count, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid count %q", args[1])
+		}
+	}
+
+	if !m.cpu.IgnoreBreakpoint(id, count) {
+		return fmt.Errorf("no breakpoint %d", id)
+	}
+
+	return nil
+}
+
+// fill fills the range with the data, repeated as often as needed.
+func (m *monitor) fill(args []string) error {
+	start, end, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := parseData(args[2:])
+	if err != nil {
+		return err
+	}
+
+	for address := int(start); address <= int(end); address++ {
+		m.cpu.Poke(uint16(address), data[(address-int(start))%len(data)])
+	}
+
+	return nil
+}
+
+// transfer copies the range to the destination. The ranges may overlap.
+func (m *monitor) transfer(args []string) error {
+	start, end, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) < 3 {
+		return errors.New("missing destination address")
+	}
+
+	destination, err := parseAddress(args[2])
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, int(end)-int(start)+1)
+	for i := range data {
+		data[i] = m.cpu.Peek(start + uint16(i))
+	}
+
+	for i, value := range data {
+		m.cpu.Poke(destination+uint16(i), value)
+	}
+
+	return nil
+}
+
+// hunt lists the addresses in the range where the data is found.
+func (m *monitor) hunt(args []string) error {
+	start, end, err := parseRange(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := parseData(args[2:])
+	if err != nil {
+		return err
+	}
+
+	for address := int(start); address+len(data)-1 <= int(end); address++ {
+		found := true
+		for i, value := range data {
+			if m.cpu.Peek(uint16(address+i)) != value {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			fmt.Fprintf(m.out, "%04x\n", address)
+		}
+	}
+
+	return nil
+}
+
+// load loads a PRG file, at the given address instead of its load address
+// when one is given. The device number is accepted for compatibility with
+// VICE, files are always read from the host file system.
+func (m *monitor) load(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing file name")
+	}
+
+	filename, err := parseFilename(args[0])
+	if err != nil {
+		return err
+	}
+
+	prg, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var loaded cpu6510.Range
+	if len(args) > 2 {
+		address, err := parseAddress(args[2])
+		if err != nil {
+			return err
+		}
+
+		if len(prg) < 2 {
+			return errors.New("PRG file is missing the load address")
+		}
+
+		loaded, err = m.cpu.LoadAt(address, prg[2:])
+		if err != nil {
+			return err
+		}
+	} else {
+		loaded, err = m.cpu.LoadPRG(bytes.NewReader(prg))
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(m.out, "Loading %s from %04x to %04x\n", filename, loaded.Start, loaded.End)
+
+	return nil
+}
+
+// save saves the range to a PRG file. The device number is accepted for
+// compatibility with VICE, files are always written to the host file system.
+func (m *monitor) save(args []string) error {
+	if len(args) < 4 {
+		return errors.New("expected \"file\" device start end")
+	}
+
+	filename, err := parseFilename(args[0])
+	if err != nil {
+		return err
+	}
+
+	start, end, err := parseRange(args[2:])
+	if err != nil {
+		return err
+	}
+
+	prg := []byte{byte(start), byte(start >> 8)}
+	for address := int(start); address <= int(end); address++ {
+		prg = append(prg, m.cpu.Peek(uint16(address)))
+	}
+
+	if err := os.WriteFile(filename, prg, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(m.out, "Saving %s from %04x to %04x\n", filename, start, end)
+
+	return nil
+}
+
+// exit leaves the monitor.
+func (m *monitor) exit(args []string) error {
+	m.quit = true
+
+	return nil
+}
+
+// help lists the commands.
+func (m *monitor) help(args []string) error {
+	var lines []string
+	for _, cmd := range commands {
+		lines = append(lines, cmd.help)
+	}
+	sort.Strings(lines)
+
+	previous := ""
+	for _, line := range lines {
+		if line != previous {
+			fmt.Fprintln(m.out, line)
+		}
+		previous = line
+	}
+
+	fmt.Fprintln(m.out, "Numbers are hexadecimal, also in conditions like A == 10 && X > 3.")
+	fmt.Fprintln(m.out, "A number in a condition can be binary with a leading %.")
+
+	return nil
+}
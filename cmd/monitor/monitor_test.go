@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// newTestMonitor returns a monitor for a CPU with a small program at $0200:
+//
+//	0200 LDA #$42
+//	0202 JSR $0210
+//	0205 INX
+//	0206 JMP $0200
+//	0210 STA $1000
+//	0213 RTS
+func newTestMonitor(t *testing.T) (*monitor, *strings.Builder) {
+	t.Helper()
+
+	cpu := cpu6510.NewCPU()
+	cpu.LoadAt(0x0200, []byte{0xA9, 0x42, 0x20, 0x10, 0x02, 0xE8, 0x4C, 0x00, 0x02}, cpu6510.SetPCToLoadAddress())
+	cpu.LoadAt(0x0210, []byte{0x8D, 0x00, 0x10, 0x60})
+
+	out := &strings.Builder{}
+
+	return newMonitor(cpu, out), out
+}
+
+// run executes the command lines and returns the output.
+func run(t *testing.T, m *monitor, out *strings.Builder, lines ...string) string {
+	t.Helper()
+
+	out.Reset()
+	for _, line := range lines {
+		if err := m.execute(line); err != nil {
+			t.Fatalf("%q error: %v", line, err)
+		}
+	}
+
+	return out.String()
+}
+
+func TestSplitArguments(t *testing.T) {
+	args, err := splitArguments(`h 0200,0300 "HI" 42  `)
+	if err != nil {
+		t.Fatalf("splitArguments error: %v", err)
+	}
+
+	expected := []string{"h", "0200", "0300", `"HI"`, "42"}
+	if strings.Join(args, "|") != strings.Join(expected, "|") {
+		t.Errorf("Arguments should be %q, got %q", expected, args)
+	}
+
+	if _, err := splitArguments(`l "file`); err == nil {
+		t.Errorf("Unterminated string should fail")
+	}
+}
+
+func TestMemoryDump(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	output := run(t, m, out, "m 0200 0211")
+
+	expected := "" +
+		">C:0200  a9 42 20 10  02 e8 4c 00  02 00 00 00  00 00 00 00   .B ...L.........\n" +
+		">C:0210  8d 00                                                ..\n"
+
+	if output != expected {
+		t.Errorf("Dump should be\n%q, got\n%q", expected, output)
+	}
+
+	if output := run(t, m, out, "m"); !strings.HasPrefix(output, ">C:0212  ") {
+		t.Errorf("Dump should continue at $0212, got %q", output)
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	output := run(t, m, out, "d $0200 $0206")
+
+	expected := "" +
+		".C:0200  A9 42      LDA #$42\n" +
+		".C:0202  20 10 02   JSR $0210\n" +
+		".C:0205  E8         INX\n" +
+		".C:0206  4C 00 02   JMP $0200\n"
+
+	if output != expected {
+		t.Errorf("Disassembly should be\n%q, got\n%q", expected, output)
+	}
+
+	if lines := strings.Count(run(t, m, out, "d"), "\n"); lines != defaultDisassembleLength {
+		t.Errorf("Disassembly should show %d instructions, got %d", defaultDisassembleLength, lines)
+	}
+}
+
+func TestRegisters(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	run(t, m, out, "r A = 10, X=20, pc = C000, p = a1")
+
+	expected := "" +
+		"  ADDR A  X  Y  SP 00 01 NV-BDIZC CYC\n" +
+		".;c000 10 20 00 ff 00 00 10100001 0\n"
+
+	if output := run(t, m, out, "r"); output != expected {
+		t.Errorf("Registers should be\n%q, got\n%q", expected, output)
+	}
+
+	for _, line := range []string{"r Q = 1", "r A = 100", "r A 1"} {
+		if err := m.execute(line); err == nil {
+			t.Errorf("%q should fail", line)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	if output := run(t, m, out, "z"); output != ".C:0202  20 10 02   JSR $0210\n" {
+		t.Errorf("z should show the next instruction, got %q", output)
+	}
+
+	if output := run(t, m, out, "z"); output != ".C:0210  8D 00 10   STA $1000\n" {
+		t.Errorf("z should step into the subroutine, got %q", output)
+	}
+
+	if output := run(t, m, out, "z 2"); output != ".C:0205  E8         INX\n" {
+		t.Errorf("z 2 should step out of the subroutine, got %q", output)
+	}
+}
+
+func TestNext(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	run(t, m, out, "z")
+
+	if output := run(t, m, out, "n"); output != ".C:0205  E8         INX\n" {
+		t.Errorf("n should step over the subroutine, got %q", output)
+	}
+
+	if m.cpu.Peek(0x1000) != 0x42 {
+		t.Errorf("Subroutine should have been run")
+	}
+
+	if output := run(t, m, out, "n 2"); output != ".C:0200  A9 42      LDA #$42\n" {
+		t.Errorf("n 2 should step over two instructions, got %q", output)
+	}
+}
+
+func TestBreakAndGo(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	if output := run(t, m, out, "break 0205"); output != "BREAK: 1  C:$0205  (Stop on exec)\n" {
+		t.Errorf("break should report the breakpoint, got %q", output)
+	}
+
+	expected := "" +
+		"#1 (Stop on exec 0205)\n" +
+		".C:0205  E8         INX\n"
+
+	if output := run(t, m, out, "g"); output != expected {
+		t.Errorf("g should stop at the breakpoint, got %q", output)
+	}
+
+	if output := run(t, m, out, "break"); output != "BREAK: 1  C:$0205  (Stop on exec) hits 1\n" {
+		t.Errorf("break should list the breakpoints, got %q", output)
+	}
+
+	run(t, m, out, "disable 1", "break 0200 if X == 10")
+
+	if output := run(t, m, out, "g"); !strings.HasPrefix(output, "#2 (Stop on exec 0200)") || m.cpu.X() != 0x10 {
+		t.Errorf("g should stop at the conditional breakpoint with X = $10, got %q and X = $%02X", output, m.cpu.X())
+	}
+}
+
+func TestHexCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		expected  string
+	}{
+		{"X == 10", "X == $10"},
+		{"A == $10 && X > 3", "A == $10 && X > $3"},
+		{"(.Y<0ff)||PC!=c000", "(.Y<$0ff)||PC!=c000"},
+		{"P == %101", "P == %101"},
+	}
+
+	for _, test := range tests {
+		if condition := hexCondition(test.condition); condition != test.expected {
+			t.Errorf("hexCondition(%q) should be %q, got %q", test.condition, test.expected, condition)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	if output := run(t, m, out, "watch store 1000 10ff"); output != "WATCH: 1  C:$1000-$10ff  (Stop on store)\n" {
+		t.Errorf("watch should report the watchpoint, got %q", output)
+	}
+
+	if output := run(t, m, out, "g"); !strings.HasPrefix(output, "#1 (Stop on store 1000)\n.C:0213") {
+		t.Errorf("g should stop after the store, got %q", output)
+	}
+
+	run(t, m, out, "disable 1")
+
+	if output := run(t, m, out, "break"); !strings.Contains(output, "disabled") {
+		t.Errorf("Watchpoint should be listed as disabled, got %q", output)
+	}
+
+	if err := m.execute("enable 7"); err == nil {
+		t.Errorf("Enabling a missing breakpoint should fail")
+	}
+}
+
+func TestGoInterrupted(t *testing.T) {
+	m, out := newTestMonitor(t)
+	m.interrupt = func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, cancel
+	}
+
+	if output := run(t, m, out, "g 0205"); output != "Interrupted\n.C:0205  E8         INX\n" {
+		t.Errorf("g should stop when interrupted, got %q", output)
+	}
+}
+
+func TestFillTransferHunt(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	run(t, m, out, "f 3000 3007 01 02 03", "t 3000 3003 3002")
+
+	expected := []byte{0x01, 0x02, 0x01, 0x02, 0x03, 0x01, 0x01, 0x02}
+	for i, value := range expected {
+		if m.cpu.Peek(0x3000+uint16(i)) != value {
+			t.Errorf("$%04X should be $%02X, got $%02X", 0x3000+i, value, m.cpu.Peek(0x3000+uint16(i)))
+		}
+	}
+
+	if output := run(t, m, out, "h 3000 3007 01 02"); output != "3000\n3002\n3006\n" {
+		t.Errorf("h should find $3000, $3002 and $3006, got %q", output)
+	}
+
+	run(t, m, out, `f 4000 4001 "OK"`)
+
+	if output := run(t, m, out, `h 0000 ffff "OK"`); output != "4000\n" {
+		t.Errorf("h should find the string at $4000, got %q", output)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	m, out := newTestMonitor(t)
+	filename := filepath.Join(t.TempDir(), "test.prg")
+
+	if output := run(t, m, out, `s "`+filename+`" 0 0200 0208`); !strings.HasPrefix(output, "Saving") {
+		t.Errorf("s should report the save, got %q", output)
+	}
+
+	prg, err := os.ReadFile(filename)
+	if err != nil || len(prg) != 11 || prg[0] != 0x00 || prg[1] != 0x02 || prg[2] != 0xA9 {
+		t.Fatalf("PRG file should have the load address and 9 bytes, got % X", prg)
+	}
+
+	if output := run(t, m, out, `l "`+filename+`" 0 c000`); output != "Loading "+filename+" from c000 to c008\n" {
+		t.Errorf("l should load at $C000, got %q", output)
+	}
+
+	run(t, m, out, "f 0200 0208 00")
+
+	if output := run(t, m, out, `l "`+filename+`" 0`); output != "Loading "+filename+" from 0200 to 0208\n" {
+		t.Errorf("l should load at the load address, got %q", output)
+	}
+
+	if m.cpu.Peek(0x0200) != 0xA9 || m.cpu.Peek(0xC000) != 0xA9 {
+		t.Errorf("Memory should hold the loaded program")
+	}
+}
+
+func TestRepl(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	if err := m.repl(strings.NewReader("z\nbogus\nx\nr\n")); err != nil {
+		t.Fatalf("repl error: %v", err)
+	}
+
+	output := out.String()
+
+	if !strings.HasPrefix(output, "(C:$0200) .C:0202") {
+		t.Errorf("repl should prompt with the program counter, got %q", output)
+	}
+
+	if !strings.Contains(output, `ERROR -- unknown command "bogus"`) {
+		t.Errorf("repl should report unknown commands, got %q", output)
+	}
+
+	if strings.Contains(output, "ADDR") {
+		t.Errorf("repl should stop at x")
+	}
+}
+
+func TestHelp(t *testing.T) {
+	m, out := newTestMonitor(t)
+
+	output := run(t, m, out, "help")
+
+	for _, name := range []string{"m ", "d ", "r ", "g ", "z ", "n ", "break", "watch", "f ", "t ", "h ", "l ", "s "} {
+		if !strings.Contains(output, "\n"+name) && !strings.HasPrefix(output, name) {
+			t.Errorf("help should list %q", name)
+		}
+	}
+}
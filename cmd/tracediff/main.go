@@ -0,0 +1,178 @@
+// Command tracediff compares two execution traces in the nestest format, like
+// the ones written by the tracer of the CPU6510, and reports the first line
+// where they diverge. Only the program counter, the registers and the cycle
+// count are compared, so traces from other emulators can be compared even when
+// they disassemble differently or have extra columns.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// entry is the state of the CPU on a line of a trace.
+type entry struct {
+	pc     uint16
+	fields map[string]uint64
+}
+
+// The registers that are compared, in the order they are reported.
+var registers = []string{"A", "X", "Y", "P", "SP", "CYC"}
+
+var (
+	pcPattern       = regexp.MustCompile(`^([0-9A-Fa-f]{4})\s`)
+	registerPattern = regexp.MustCompile(`\b(A|X|Y|P|SP):([0-9A-Fa-f]{2})\b`)
+	cyclePattern    = regexp.MustCompile(`\bCYC:\s*(\d+)`)
+)
+
+// parseLine parses a line of a trace.
+func parseLine(line string) (entry, error) {
+	match := pcPattern.FindStringSubmatch(line)
+	if match == nil {
+		return entry{}, fmt.Errorf("no program counter in %q", line)
+	}
+
+	pc, _ := strconv.ParseUint(match[1], 16, 16)
+	e := entry{pc: uint16(pc), fields: map[string]uint64{}}
+
+	for _, match := range registerPattern.FindAllStringSubmatch(line, -1) {
+		value, _ := strconv.ParseUint(match[2], 16, 8)
+		e.fields[match[1]] = value
+	}
+
+	if match := cyclePattern.FindStringSubmatch(line); match != nil {
+		value, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return entry{}, err
+		}
+		e.fields["CYC"] = value
+	}
+
+	return e, nil
+}
+
+// differences returns the names of what differs between the entries. A
+// register that is missing from either entry is not compared.
+func differences(a entry, b entry, ignoreCycles bool) []string {
+	var names []string
+
+	if a.pc != b.pc {
+		names = append(names, "PC")
+	}
+
+	for _, name := range registers {
+		if name == "CYC" && ignoreCycles {
+			continue
+		}
+
+		valueA, okA := a.fields[name]
+		valueB, okB := b.fields[name]
+		if okA && okB && valueA != valueB {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// nextLine returns the next line of the trace that is not empty, and false at
+// the end of the trace.
+func nextLine(scanner *bufio.Scanner) (string, bool) {
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			return scanner.Text(), true
+		}
+	}
+
+	return "", false
+}
+
+// diff compares the traces and writes the first divergence to out. It returns
+// true when the traces match.
+func diff(a io.Reader, b io.Reader, out io.Writer, ignoreCycles bool) (bool, error) {
+	scannerA := bufio.NewScanner(a)
+	scannerB := bufio.NewScanner(b)
+
+	for number := 1; ; number++ {
+		lineA, okA := nextLine(scannerA)
+		lineB, okB := nextLine(scannerB)
+
+		if !okA || !okB {
+			if err := scannerA.Err(); err != nil {
+				return false, err
+			}
+			if err := scannerB.Err(); err != nil {
+				return false, err
+			}
+
+			if okA != okB {
+				fmt.Fprintf(out, "traces diverge at line %d: one trace ends\n", number)
+				fmt.Fprintf(out, "< %s\n> %s\n", lineA, lineB)
+
+				return false, nil
+			}
+
+			fmt.Fprintf(out, "traces match, %d lines\n", number-1)
+
+			return true, nil
+		}
+
+		entryA, err := parseLine(lineA)
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		entryB, err := parseLine(lineB)
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		if names := differences(entryA, entryB, ignoreCycles); len(names) > 0 {
+			fmt.Fprintf(out, "traces diverge at line %d in %s\n", number, strings.Join(names, ", "))
+			fmt.Fprintf(out, "< %s\n> %s\n", lineA, lineB)
+
+			return false, nil
+		}
+	}
+}
+
+func main() {
+	ignoreCycles := flag.Bool("ignore-cycles", false, "Do not compare the cycle counts")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] trace-a trace-b\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var files [2]*os.File
+	for i := range files {
+		file, err := os.Open(flag.Arg(i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open file %q: %v\n", flag.Arg(i), err)
+			os.Exit(2)
+		}
+		defer file.Close()
+		files[i] = file
+	}
+
+	match, err := diff(files[0], files[1], os.Stdout, *ignoreCycles)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to compare traces: %v\n", err)
+		os.Exit(2)
+	}
+
+	if !match {
+		os.Exit(1)
+	}
+}
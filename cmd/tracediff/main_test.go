@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const reference = "" +
+	"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 21 CYC:7\n" +
+	"C5F5  A2 00     LDX #$00                        A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 30 CYC:10\n" +
+	"C5F7  86 00     STX $00 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 36 CYC:12\n"
+
+func TestParseLine(t *testing.T) {
+	e, err := parseLine("C5F7  86 00     STX $00 = 00                    A:01 X:02 Y:03 P:26 SP:FD PPU:  0, 36 CYC:12")
+	if err != nil {
+		t.Fatalf("parseLine error: %v", err)
+	}
+
+	expected := map[string]uint64{"A": 0x01, "X": 0x02, "Y": 0x03, "P": 0x26, "SP": 0xFD, "CYC": 12}
+
+	if e.pc != 0xC5F7 {
+		t.Errorf("PC should be 0xC5F7, got 0x%04X", e.pc)
+	}
+
+	for name, value := range expected {
+		if e.fields[name] != value {
+			t.Errorf("%s should be %d, got %d", name, value, e.fields[name])
+		}
+	}
+
+	if _, err := parseLine("not a trace line"); err == nil {
+		t.Errorf("parseLine should fail without a program counter")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name         string
+		trace        string
+		ignoreCycles bool
+		match        bool
+		report       string
+	}{
+		{
+			"Same trace with other columns",
+			"" +
+				"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7\n" +
+				"C5F5  A2 00     LDX #$00                        A:00 X:00 Y:00 P:24 SP:FD CYC:10\n" +
+				"C5F7  86 00     STX $00                         A:00 X:00 Y:00 P:26 SP:FD CYC:12\n",
+			false, true, "traces match, 3 lines\n",
+		},
+		{
+			"Different registers",
+			"" +
+				"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7\n" +
+				"C5F5  A2 00     LDX #$00                        A:00 X:01 Y:00 P:A4 SP:FD CYC:10\n",
+			false, false, "traces diverge at line 2 in X, P\n",
+		},
+		{
+			"Different cycles",
+			"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:8\n",
+			false, false, "traces diverge at line 1 in CYC\n",
+		},
+		{
+			"Ignore cycles",
+			"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:8\n",
+			true, false, "traces diverge at line 2: one trace ends\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out strings.Builder
+
+			match, err := diff(strings.NewReader(reference), strings.NewReader(test.trace), &out, test.ignoreCycles)
+			if err != nil {
+				t.Fatalf("diff error: %v", err)
+			}
+
+			if match != test.match {
+				t.Errorf("Traces should match: %v", test.match)
+			}
+
+			if !strings.HasPrefix(out.String(), test.report) {
+				t.Errorf("Report should start with %q, got %q", test.report, out.String())
+			}
+		})
+	}
+}
@@ -3,7 +3,7 @@ package cpu6510
 // getValueByImmediateAddressingMode - returns the value in memory at the
 // current program counter.
 func (c *CPU) getValueByImmediateAddressingMode() byte {
-	value := c.ram[c.programCounter]
+	value := c.readMemory(c.programCounter)
 	c.programCounter++
 	return value
 }
@@ -30,28 +30,34 @@ func (c *CPU) addressAbsolute() uint16 {
 // address specified by the next two bytes in memory plus the value of the
 // X register.
 func (c *CPU) getValueByAbsoluteXAddressingMode() byte {
-	address := c.addressAbsoluteX()
+	base := c.addressAbsolute()
 
-	return c.readMemory(address)
+	return c.readIndexed(base, c.xRegister)
 }
 
 // addressAbsoluteX - returns the address specified by the next two bytes in
 // memory plus the value of the X register.
 func (c *CPU) addressAbsoluteX() uint16 {
-	address := c.readAddressFromMemory() + uint16(c.xRegister)
-	c.programCounter += 2
+	base := c.addressAbsolute()
 
-	return address
+	return c.addressIndexed(base, c.xRegister)
 }
 
 // getValueByAbsoluteYAddressingMode - returns the value in memory at the
 // address specified by the next two bytes in memory plus the value of the
 // Y register.
 func (c *CPU) getValueByAbsoluteYAddressingMode() byte {
-	address := c.readAddressFromMemory() + uint16(c.yRegister)
-	c.programCounter += 2
+	base := c.addressAbsolute()
 
-	return c.readMemory(address)
+	return c.readIndexed(base, c.yRegister)
+}
+
+// addressAbsoluteY - returns the address specified by the next two bytes in
+// memory plus the value of the Y register.
+func (c *CPU) addressAbsoluteY() uint16 {
+	base := c.addressAbsolute()
+
+	return c.addressIndexed(base, c.yRegister)
 }
 
 // getValueByZeroPageAddressingMode - returns the value in memory at the
@@ -64,7 +70,7 @@ func (c *CPU) getValueByZeroPageAddressingMode() byte {
 
 // addressZeroPage - returns the address specified by the next byte in memory.
 func (c *CPU) addressZeroPage() uint16 {
-	address := uint16(c.ram[c.programCounter])
+	address := uint16(c.readMemory(c.programCounter))
 	c.programCounter++
 
 	return address
@@ -82,22 +88,46 @@ func (c *CPU) getValueByZeroPageXAddressingMode() byte {
 // addressZeroPageX - returns the address specified by the next byte in memory
 // plus the value of the X register.
 func (c *CPU) addressZeroPageX() uint16 {
-	address := uint16(c.ram[c.programCounter] + c.xRegister)
+	zeroPageAddress := c.readMemory(c.programCounter)
 	c.programCounter++
 
-	return address
+	// The CPU reads from the unindexed address while it adds the index.
+	c.dummyRead(uint16(zeroPageAddress))
+
+	return uint16(zeroPageAddress + c.xRegister)
+}
+
+// getValueByZeroPageYAddressingMode - returns the value in memory at the
+// address specified by the next byte in memory plus the value of the Y
+// register.
+func (c *CPU) getValueByZeroPageYAddressingMode() byte {
+	address := c.addressZeroPageY()
+
+	return c.readMemory(address)
+}
+
+// addressZeroPageY - returns the address specified by the next byte in memory
+// plus the value of the Y register.
+func (c *CPU) addressZeroPageY() uint16 {
+	zeroPageAddress := c.readMemory(c.programCounter)
+	c.programCounter++
+
+	// The CPU reads from the unindexed address while it adds the index.
+	c.dummyRead(uint16(zeroPageAddress))
+
+	return uint16(zeroPageAddress + c.yRegister)
 }
 
 // addressIndexedIndirect - returns the address specified by the zero page
 // address plus the X register.
 func (c *CPU) addressIndexedIndirect() uint16 {
-	zeroPageAddress := uint16(uint8(c.ram[c.programCounter] + c.xRegister))
+	zeroPageAddress := c.readMemory(c.programCounter)
 	c.programCounter++
 
-	lowByte := c.readMemory(zeroPageAddress)
-	highByte := c.readMemory(zeroPageAddress + 1)
+	// The CPU reads from the unindexed address while it adds the index.
+	c.dummyRead(uint16(zeroPageAddress))
 
-	return ConvertTwoBytesToAddress(highByte, lowByte)
+	return c.readZeroPagePointer(zeroPageAddress + c.xRegister)
 }
 
 // getValueByIndexedIndirectAddressingMode - returns the value in memory at
@@ -115,13 +145,58 @@ func (c *CPU) getValueByIndexedIndirectAddressingMode() byte {
 // The Y register is added after the address fetched from the zero page
 // address to get the final address.
 func (c *CPU) getValueByIndirectIndexedAddressingMode() byte {
-	zeroPageAddress := uint16(c.ram[c.programCounter])
+	zeroPageAddress := c.readMemory(c.programCounter)
+	c.programCounter++
+
+	base := c.readZeroPagePointer(zeroPageAddress)
+
+	return c.readIndexed(base, c.yRegister)
+}
+
+// addressIndirectIndexed - returns the address pointed to by the zero page
+// address plus the Y register.
+func (c *CPU) addressIndirectIndexed() uint16 {
+	zeroPageAddress := c.readMemory(c.programCounter)
 	c.programCounter++
 
-	lowByte := c.readMemory(zeroPageAddress)
-	highByte := c.readMemory(zeroPageAddress + 1)
+	base := c.readZeroPagePointer(zeroPageAddress)
 
-	address := ConvertTwoBytesToAddress(highByte, lowByte) + uint16(c.yRegister)
+	return c.addressIndexed(base, c.yRegister)
+}
+
+// readZeroPagePointer - returns the address stored at the zero page address.
+// The pointer is read from the zero page, so the high byte of a pointer at
+// $FF is read from $00.
+func (c *CPU) readZeroPagePointer(zeroPageAddress byte) uint16 {
+	lowByte := c.readMemory(uint16(zeroPageAddress))
+	highByte := c.readMemory(uint16(zeroPageAddress + 1))
+
+	return ConvertTwoBytesToAddress(highByte, lowByte)
+}
+
+// readIndexed - returns the value in memory at the base address plus the
+// index. The CPU adds the index to the low byte of the address first, so when
+// the index crosses a page it reads from the wrong page before it reads from
+// the right one, which takes an extra cycle.
+func (c *CPU) readIndexed(base uint16, index byte) byte {
+	address := base + uint16(index)
+
+	if pageCrossed(base, address) {
+		c.dummyRead(base&0xFF00 | address&0x00FF)
+		c.cycles++
+	}
 
 	return c.readMemory(address)
 }
+
+// addressIndexed - returns the base address plus the index. Stores and
+// read-modify-write instructions cannot take back a write to the wrong page,
+// so they always spend the cycle reading from the address before the high
+// byte has been fixed.
+func (c *CPU) addressIndexed(base uint16, index byte) uint16 {
+	address := base + uint16(index)
+
+	c.dummyRead(base&0xFF00 | address&0x00FF)
+
+	return address
+}
@@ -0,0 +1,209 @@
+package cpu6510
+
+// addWithCarry - adds the value and the carry flag to the accumulator. When
+// the decimal mode flag is set the operands are treated as packed BCD, using
+// the NMOS 6510 behaviour where the zero flag reflects the binary sum while
+// the negative and overflow flags are taken from the intermediate result
+// before the high nibble is decimal adjusted.
+func addWithCarry(c *CPU, value byte) {
+	var carry int
+	if c.statusRegister.carryFlag {
+		carry = 1
+	}
+
+	a := int(c.accumulator)
+	v := int(value)
+	binary := a + v + carry
+
+	if !c.statusRegister.decimalModeFlag {
+		result := byte(binary)
+
+		c.statusRegister.carryFlag = binary > 0xFF
+		c.statusRegister.overflowFlag = (a^binary)&(v^binary)&0x80 != 0
+		c.accumulator = result
+
+		raiseStatusRegisterFlags(c, result)
+
+		return
+	}
+
+	// The low nibble is added and adjusted first.
+	tmp := (a & 0x0F) + (v & 0x0F) + carry
+	if tmp > 0x09 {
+		tmp += 0x06
+	}
+
+	if tmp <= 0x0F {
+		tmp = (tmp & 0x0F) + (a & 0xF0) + (v & 0xF0)
+	} else {
+		tmp = (tmp & 0x0F) + (a & 0xF0) + (v & 0xF0) + 0x10
+	}
+
+	c.statusRegister.zeroFlag = binary&0xFF == 0
+	c.statusRegister.negativeFlag = tmp&0x80 == 0x80
+	c.statusRegister.overflowFlag = (a^tmp)&0x80 != 0 && (a^v)&0x80 == 0
+
+	// Then the high nibble.
+	if tmp&0x1F0 > 0x90 {
+		tmp += 0x60
+	}
+
+	c.statusRegister.carryFlag = tmp&0xFF0 > 0xF0
+	c.accumulator = byte(tmp)
+}
+
+// subtractWithCarry - subtracts the value and the inverted carry flag (the
+// borrow) from the accumulator. On the NMOS 6510 all flags are set from the
+// binary subtraction, also in decimal mode, where only the accumulator is
+// decimal adjusted.
+func subtractWithCarry(c *CPU, value byte) {
+	var borrow int
+	if !c.statusRegister.carryFlag {
+		borrow = 1
+	}
+
+	a := int(c.accumulator)
+	v := int(value)
+	binary := a - v - borrow
+	result := byte(binary)
+
+	c.statusRegister.carryFlag = binary >= 0
+	c.statusRegister.overflowFlag = (a^binary)&0x80 != 0 && (a^v)&0x80 != 0
+
+	raiseStatusRegisterFlags(c, result)
+
+	if !c.statusRegister.decimalModeFlag {
+		c.accumulator = result
+
+		return
+	}
+
+	tmp := (a & 0x0F) - (v & 0x0F) - borrow
+	if tmp&0x10 != 0 {
+		tmp = ((tmp - 0x06) & 0x0F) | ((a & 0xF0) - (v & 0xF0) - 0x10)
+	} else {
+		tmp = (tmp & 0x0F) | ((a & 0xF0) - (v & 0xF0))
+	}
+
+	if tmp&0x100 != 0 {
+		tmp -= 0x60
+	}
+
+	c.accumulator = byte(tmp)
+}
+
+// ADC - ADd with Carry. ADC adds the given value and the carry flag to the
+// accumulator.
+func adc(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	value := getValue()
+
+	addWithCarry(c, value)
+}
+
+// ADCImmediate - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCImmediate(c *CPU) {
+	adc(c, c.getValueByImmediateAddressingMode)
+}
+
+// ADCZeroPage - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCZeroPage(c *CPU) {
+	adc(c, c.getValueByZeroPageAddressingMode)
+}
+
+// ADCZeroPageX - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCZeroPageX(c *CPU) {
+	adc(c, c.getValueByZeroPageXAddressingMode)
+}
+
+// ADCAbsolute - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCAbsolute(c *CPU) {
+	adc(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// ADCAbsoluteX - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCAbsoluteX(c *CPU) {
+	adc(c, c.getValueByAbsoluteXAddressingMode)
+}
+
+// ADCAbsoluteY - ADd with Carry. ADC adds the value in memory and the carry
+// flag to the accumulator.
+func ADCAbsoluteY(c *CPU) {
+	adc(c, c.getValueByAbsoluteYAddressingMode)
+}
+
+// ADCIndexedIndirect - ADd with Carry. ADC adds the value in memory and the
+// carry flag to the accumulator.
+func ADCIndexedIndirect(c *CPU) {
+	adc(c, c.getValueByIndexedIndirectAddressingMode)
+}
+
+// ADCIndirectIndexed - ADd with Carry. ADC adds the value in memory and the
+// carry flag to the accumulator.
+func ADCIndirectIndexed(c *CPU) {
+	adc(c, c.getValueByIndirectIndexedAddressingMode)
+}
+
+// SBC - SuBtract with Carry. SBC subtracts the given value and the borrow
+// (the inverted carry flag) from the accumulator.
+func sbc(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	value := getValue()
+
+	subtractWithCarry(c, value)
+}
+
+// SBCImmediate - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCImmediate(c *CPU) {
+	sbc(c, c.getValueByImmediateAddressingMode)
+}
+
+// SBCZeroPage - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCZeroPage(c *CPU) {
+	sbc(c, c.getValueByZeroPageAddressingMode)
+}
+
+// SBCZeroPageX - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCZeroPageX(c *CPU) {
+	sbc(c, c.getValueByZeroPageXAddressingMode)
+}
+
+// SBCAbsolute - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCAbsolute(c *CPU) {
+	sbc(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// SBCAbsoluteX - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCAbsoluteX(c *CPU) {
+	sbc(c, c.getValueByAbsoluteXAddressingMode)
+}
+
+// SBCAbsoluteY - SuBtract with Carry. SBC subtracts the value in memory and
+// the borrow from the accumulator.
+func SBCAbsoluteY(c *CPU) {
+	sbc(c, c.getValueByAbsoluteYAddressingMode)
+}
+
+// SBCIndexedIndirect - SuBtract with Carry. SBC subtracts the value in memory
+// and the borrow from the accumulator.
+func SBCIndexedIndirect(c *CPU) {
+	sbc(c, c.getValueByIndexedIndirectAddressingMode)
+}
+
+// SBCIndirectIndexed - SuBtract with Carry. SBC subtracts the value in memory
+// and the borrow from the accumulator.
+func SBCIndirectIndexed(c *CPU) {
+	sbc(c, c.getValueByIndirectIndexedAddressingMode)
+}
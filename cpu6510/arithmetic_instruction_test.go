@@ -0,0 +1,216 @@
+package cpu6510
+
+import "testing"
+
+func TestADCImmediate(t *testing.T) {
+	tests := []struct {
+		name         string
+		decimalMode  bool
+		carry        bool
+		accumulator  byte
+		value        byte
+		expected     byte
+		carryFlag    bool
+		zeroFlag     bool
+		overflowFlag bool
+		negativeFlag bool
+	}{
+		{"Add two positive numbers", false, false, 0x10, 0x20, 0x30, false, false, false, false},
+		{"Add with carry in", false, true, 0x10, 0x20, 0x31, false, false, false, false},
+		{"Carry out and zero result", false, false, 0xFF, 0x01, 0x00, true, true, false, false},
+		{"Positive overflow", false, false, 0x50, 0x50, 0xA0, false, false, true, true},
+		{"Negative overflow", false, false, 0xD0, 0x90, 0x60, true, false, true, false},
+		{"Positive plus negative", false, false, 0x50, 0xD0, 0x20, true, false, false, false},
+		{"Decimal add", true, false, 0x12, 0x34, 0x46, false, false, false, false},
+		{"Decimal add with nibble adjust", true, false, 0x15, 0x26, 0x41, false, false, false, false},
+		{"Decimal add with carry out", true, true, 0x58, 0x46, 0x05, true, false, true, true},
+		{"Decimal add with overflow", true, false, 0x81, 0x92, 0x73, true, false, true, false},
+		// The NMOS 6510 sets Z from the binary sum ($9A) and N from the
+		// intermediate result ($A0), although the accumulator ends up $00.
+		{"Decimal 99 plus 1", true, false, 0x99, 0x01, 0x00, true, false, false, true},
+		{"Decimal binary sum is zero", true, false, 0x80, 0x80, 0x60, true, true, true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.statusRegister.decimalModeFlag = test.decimalMode
+			cpu.statusRegister.carryFlag = test.carry
+			cpu.accumulator = test.accumulator
+			cpu.ram[1] = test.value
+
+			cpu.execute(InstructionAsHex("ADCImmediate"))
+
+			if cpu.accumulator != test.expected {
+				t.Errorf("Accumulator should be 0x%02X, got 0x%02X", test.expected, cpu.accumulator)
+			}
+
+			if cpu.statusRegister.carryFlag != test.carryFlag {
+				t.Errorf("Carry flag should be %t", test.carryFlag)
+			}
+
+			if cpu.statusRegister.zeroFlag != test.zeroFlag {
+				t.Errorf("Zero flag should be %t", test.zeroFlag)
+			}
+
+			if cpu.statusRegister.overflowFlag != test.overflowFlag {
+				t.Errorf("Overflow flag should be %t", test.overflowFlag)
+			}
+
+			if cpu.statusRegister.negativeFlag != test.negativeFlag {
+				t.Errorf("Negative flag should be %t", test.negativeFlag)
+			}
+
+			if cpu.programCounter != 2 {
+				t.Errorf("Program counter should be incremented by 2")
+			}
+		})
+	}
+}
+
+func TestSBCImmediate(t *testing.T) {
+	tests := []struct {
+		name         string
+		decimalMode  bool
+		carry        bool
+		accumulator  byte
+		value        byte
+		expected     byte
+		carryFlag    bool
+		zeroFlag     bool
+		overflowFlag bool
+		negativeFlag bool
+	}{
+		{"Subtract without borrow", false, true, 0x05, 0x03, 0x02, true, false, false, false},
+		{"Subtract with borrow", false, false, 0x05, 0x03, 0x01, true, false, false, false},
+		{"Zero result", false, true, 0x42, 0x42, 0x00, true, true, false, false},
+		{"Borrow out", false, true, 0x50, 0xF0, 0x60, false, false, false, false},
+		{"Positive minus negative overflows", false, true, 0x50, 0xB0, 0xA0, false, false, true, true},
+		{"Negative minus positive overflows", false, true, 0xD0, 0x70, 0x60, true, false, true, false},
+		{"Decimal subtract", true, true, 0x46, 0x12, 0x34, true, false, false, false},
+		{"Decimal subtract with nibble adjust", true, true, 0x40, 0x13, 0x27, true, false, false, false},
+		{"Decimal subtract with borrow in", true, false, 0x40, 0x13, 0x26, true, false, false, false},
+		{"Decimal subtract with borrow out", true, true, 0x12, 0x21, 0x91, false, false, false, true},
+		{"Decimal zero result", true, true, 0x99, 0x99, 0x00, true, true, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.statusRegister.decimalModeFlag = test.decimalMode
+			cpu.statusRegister.carryFlag = test.carry
+			cpu.accumulator = test.accumulator
+			cpu.ram[1] = test.value
+
+			cpu.execute(InstructionAsHex("SBCImmediate"))
+
+			if cpu.accumulator != test.expected {
+				t.Errorf("Accumulator should be 0x%02X, got 0x%02X", test.expected, cpu.accumulator)
+			}
+
+			if cpu.statusRegister.carryFlag != test.carryFlag {
+				t.Errorf("Carry flag should be %t", test.carryFlag)
+			}
+
+			if cpu.statusRegister.zeroFlag != test.zeroFlag {
+				t.Errorf("Zero flag should be %t", test.zeroFlag)
+			}
+
+			if cpu.statusRegister.overflowFlag != test.overflowFlag {
+				t.Errorf("Overflow flag should be %t", test.overflowFlag)
+			}
+
+			if cpu.statusRegister.negativeFlag != test.negativeFlag {
+				t.Errorf("Negative flag should be %t", test.negativeFlag)
+			}
+
+			if cpu.programCounter != 2 {
+				t.Errorf("Program counter should be incremented by 2")
+			}
+		})
+	}
+}
+
+func TestArithmeticAddressingModes(t *testing.T) {
+	tests := []struct {
+		instruction string
+		setup       func(cpu *CPU, value byte)
+		expectedPC  uint16
+	}{
+		{"ZeroPage", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = value
+		}, 2},
+		{"ZeroPageX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = value
+		}, 2},
+		{"Absolute", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1337] = value
+		}, 3},
+		{"AbsoluteX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+		{"AbsoluteY", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+		{"IndexedIndirect", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = 0x37
+			cpu.ram[0x15] = 0x13
+			cpu.ram[0x1337] = value
+		}, 2},
+		{"IndirectIndexed", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = 0x37
+			cpu.ram[0x14] = 0x13
+			cpu.ram[0x1338] = value
+		}, 2},
+	}
+
+	for _, test := range tests {
+		t.Run("ADC"+test.instruction, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.accumulator = 0x20
+			test.setup(cpu, 0x22)
+
+			cpu.execute(InstructionAsHex("ADC" + test.instruction))
+
+			if cpu.accumulator != 0x42 {
+				t.Errorf("Accumulator should be 0x42, got 0x%02X", cpu.accumulator)
+			}
+
+			if cpu.programCounter != test.expectedPC {
+				t.Errorf("Program counter should be 0x%04X, got 0x%04X", test.expectedPC, cpu.programCounter)
+			}
+		})
+
+		t.Run("SBC"+test.instruction, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.statusRegister.carryFlag = true
+			cpu.accumulator = 0x64
+			test.setup(cpu, 0x22)
+
+			cpu.execute(InstructionAsHex("SBC" + test.instruction))
+
+			if cpu.accumulator != 0x42 {
+				t.Errorf("Accumulator should be 0x42, got 0x%02X", cpu.accumulator)
+			}
+
+			if cpu.programCounter != test.expectedPC {
+				t.Errorf("Program counter should be 0x%04X, got 0x%04X", test.expectedPC, cpu.programCounter)
+			}
+		})
+	}
+}
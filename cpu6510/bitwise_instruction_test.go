@@ -695,7 +695,7 @@ func TestLSRAccumulator(t *testing.T) {
 
 	t.Run("Shift all bits right and set carry flag", func(t *testing.T) {
 		cpu := NewCPU()
-		cpu.accumulator = 0b10000000
+		cpu.accumulator = 0b00000001
 
 		cpu.execute(InstructionAsHex("LSRAccumulator"))
 
@@ -743,7 +743,7 @@ func TestLSRAbsolute(t *testing.T) {
 		cpu := NewCPU()
 		cpu.ram[1] = 0x37
 		cpu.ram[2] = 0x13
-		cpu.ram[0x1337] = 0b10000000
+		cpu.ram[0x1337] = 0b00000001
 
 		cpu.execute(InstructionAsHex("LSRAbsolute"))
 
@@ -795,7 +795,7 @@ func TestLSRAbsoluteX(t *testing.T) {
 		cpu.xRegister = 0x01
 		cpu.ram[1] = 0x37
 		cpu.ram[2] = 0x13
-		cpu.ram[0x1338] = 0b10000000
+		cpu.ram[0x1338] = 0b00000001
 
 		cpu.execute(InstructionAsHex("LSRAbsoluteX"))
 
@@ -844,7 +844,7 @@ func TestLSRZeroPage(t *testing.T) {
 	t.Run("Shift all bits right and set carry flag", func(t *testing.T) {
 		cpu := NewCPU()
 		cpu.ram[1] = 0x13
-		cpu.ram[0x13] = 0b10000000
+		cpu.ram[0x13] = 0b00000001
 
 		cpu.execute(InstructionAsHex("LSRZeroPage"))
 
@@ -893,7 +893,7 @@ func TestLSRZeroPageX(t *testing.T) {
 		cpu := NewCPU()
 		cpu.xRegister = 0x01
 		cpu.ram[1] = 0x13
-		cpu.ram[0x14] = 0b10000000
+		cpu.ram[0x14] = 0b00000001
 
 		cpu.execute(InstructionAsHex("LSRZeroPageX"))
 
@@ -203,28 +203,18 @@ func ORAIndirectIndexed(c *CPU) {
 	ora(c, c.getValueByIndirectIndexedAddressingMode)
 }
 
-// ASLAccumulator - Arithmetic Shift Left. ASL shifts all bits in the
-// accumulator.
-func ASLAccumulator(c *CPU) {
-	c.programCounter++
-
-	c.statusRegister.carryFlag = setCarryFlag(c.accumulator)
-
-	c.accumulator <<= 1
-
-	raiseStatusRegisterFlags(c, c.accumulator)
-
-}
-
-// ASLZeroPage - Arithmetic Shift Left. ASL shifts all bits in the memory
-// location specified by the single byte address.
-func ASLZeroPage(c *CPU) {
+// ASL - Arithmetic Shift Left. ASL shifts all bits in the memory location
+// specified by the address.
+func asl(c *CPU, getAddress func() uint16) {
 	c.programCounter++
 
-	address := uint16(c.ram[c.programCounter])
+	address := getAddress()
 
 	value := c.readMemory(address)
 
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
 	c.statusRegister.carryFlag = setCarryFlag(value)
 
 	value <<= 1
@@ -232,71 +222,43 @@ func ASLZeroPage(c *CPU) {
 	raiseStatusRegisterFlags(c, value)
 
 	c.writeMemory(address, value)
-
-	c.programCounter++
 }
 
-// ASLZeroPageX - Arithmetic Shift Left. ASL shifts all bits in the memory
-// location specified by the single byte address plus the X index register.
-func ASLZeroPageX(c *CPU) {
-	c.programCounter++
-
-	address := uint16(c.ram[c.programCounter] + c.xRegister)
+// ASLAccumulator - Arithmetic Shift Left. ASL shifts all bits in the
+// accumulator.
+func ASLAccumulator(c *CPU) {
+	implied(c)
 
-	value := c.readMemory(address)
+	c.statusRegister.carryFlag = setCarryFlag(c.accumulator)
 
-	c.statusRegister.carryFlag = setCarryFlag(value)
+	c.accumulator <<= 1
 
-	value <<= 1
+	raiseStatusRegisterFlags(c, c.accumulator)
 
-	raiseStatusRegisterFlags(c, value)
+}
 
-	c.writeMemory(address, value)
+// ASLZeroPage - Arithmetic Shift Left. ASL shifts all bits in the memory
+// location specified by the single byte address.
+func ASLZeroPage(c *CPU) {
+	asl(c, c.addressZeroPage)
+}
 
-	c.programCounter++
+// ASLZeroPageX - Arithmetic Shift Left. ASL shifts all bits in the memory
+// location specified by the single byte address plus the X index register.
+func ASLZeroPageX(c *CPU) {
+	asl(c, c.addressZeroPageX)
 }
 
 // ASLAbsolute - Arithmetic Shift Left. ASL shifts all bits in the memory
 // location specified by the two byte address.
 func ASLAbsolute(c *CPU) {
-	c.programCounter++
-
-	address := c.readAddressFromMemory()
-
-	value := c.readMemory(address)
-
-	c.statusRegister.carryFlag = setCarryFlag(value)
-
-	value <<= 1
-
-	raiseStatusRegisterFlags(c, value)
-
-	c.writeMemory(address, value)
-
-	c.programCounter += 2
+	asl(c, c.addressAbsolute)
 }
 
 // ASLAbsoluteX - Arithmetic Shift Left. ASL shifts all bits in the memory
 // location specified by the two byte address plus the X index register.
 func ASLAbsoluteX(c *CPU) {
-	c.programCounter++
-
-	// Indexed absolute addressing is an addressing mode in which the
-	// contents of the X register is added to a given base address, to
-	// obtain the "target" address.
-	address := c.readAddressFromMemory() + uint16(c.xRegister)
-
-	value := c.readMemory(address)
-
-	c.statusRegister.carryFlag = setCarryFlag(value)
-
-	value <<= 1
-
-	raiseStatusRegisterFlags(c, value)
-
-	c.writeMemory(address, value)
-
-	c.programCounter += 2
+	asl(c, c.addressAbsoluteX)
 }
 
 // LSR - Logical Shift Right. LSR shifts all bits in the memory location
@@ -308,7 +270,10 @@ func lsr(c *CPU, getAddress func() uint16) {
 
 	value := c.readMemory(address)
 
-	c.statusRegister.carryFlag = setCarryFlag(value)
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
+	c.statusRegister.carryFlag = value&0x01 == 0x01
 
 	value >>= 1
 
@@ -322,9 +287,9 @@ func lsr(c *CPU, getAddress func() uint16) {
 // LSRAccumulator - Logical Shift Right. LSR shifts all bits in the accumulator
 // register.
 func LSRAccumulator(c *CPU) {
-	c.programCounter++
+	implied(c)
 
-	c.statusRegister.carryFlag = setCarryFlag(c.accumulator)
+	c.statusRegister.carryFlag = c.accumulator&0x01 == 0x01
 
 	c.accumulator >>= 1
 
@@ -364,6 +329,9 @@ func rol(c *CPU, getAddress func() uint16) {
 
 	value := c.readMemory(address)
 
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
 	carry := c.statusRegister.carryFlag
 
 	c.statusRegister.carryFlag = value&0x80 == 0x80
@@ -381,7 +349,7 @@ func rol(c *CPU, getAddress func() uint16) {
 
 // ROLAccumulator - Rotate Left. ROL shifts all bits in the accumulator register.
 func ROLAccumulator(c *CPU) {
-	c.programCounter++
+	implied(c)
 
 	carry := c.statusRegister.carryFlag
 
@@ -427,6 +395,9 @@ func ror(c *CPU, getAddress func() uint16) {
 
 	value := c.readMemory(address)
 
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
 	carry := c.statusRegister.carryFlag
 
 	c.statusRegister.carryFlag = value&0x01 == 0x01
@@ -444,7 +415,7 @@ func ror(c *CPU, getAddress func() uint16) {
 
 // ROR - Rotate Right. ROR shifts all bits in the accumulator register.
 func RORAccumulator(c *CPU) {
-	c.programCounter++
+	implied(c)
 
 	carry := c.statusRegister.carryFlag
 
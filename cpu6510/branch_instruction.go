@@ -1,13 +1,28 @@
 package cpu6510
 
+// branchOnFlag - adds the signed offset in the next byte to the program
+// counter when the flag is true. A taken branch takes one extra cycle, and
+// one more when the target is on another page than the next instruction.
 func branchOnFlag(c *CPU, flag bool) {
 	c.programCounter++
 
+	operand := int16(int8(c.readMemory(c.programCounter)))
+	c.programCounter++
+
 	if flag {
-		operand := int16(int8(c.ram[c.programCounter]))
-		c.programCounter++
+		target := uint16(int16(c.programCounter) + operand)
+
+		// The CPU reads the next opcode while it adds the offset to the low
+		// byte of the program counter.
+		c.dummyRead(c.programCounter)
+		c.cycles++
+
+		if pageCrossed(c.programCounter, target) {
+			c.dummyRead(c.programCounter&0xFF00 | target&0x00FF)
+			c.cycles++
+		}
 
-		c.programCounter = (uint16(int16(c.programCounter) + operand))
+		c.programCounter = target
 	}
 }
 
@@ -14,28 +14,28 @@ func TestBranchInstruction(t *testing.T) {
 		expectedPC     uint16
 	}{
 		{"BPL", StatusRegister{negativeFlag: false}, 0x40, 0xC044},
-		{"BPL", StatusRegister{negativeFlag: true}, 0x40, 0xC003},
+		{"BPL", StatusRegister{negativeFlag: true}, 0x40, 0xC004},
 		{"BPL", StatusRegister{negativeFlag: false}, 0xFC, 0xC000},
 		{"BMI", StatusRegister{negativeFlag: true}, 0x40, 0xC044},
-		{"BMI", StatusRegister{negativeFlag: false}, 0x40, 0xC003},
+		{"BMI", StatusRegister{negativeFlag: false}, 0x40, 0xC004},
 		{"BMI", StatusRegister{negativeFlag: true}, 0xFC, 0xC000},
 		{"BVC", StatusRegister{overflowFlag: false}, 0x40, 0xC044},
-		{"BVC", StatusRegister{overflowFlag: true}, 0x40, 0xC003},
+		{"BVC", StatusRegister{overflowFlag: true}, 0x40, 0xC004},
 		{"BVC", StatusRegister{overflowFlag: false}, 0xFC, 0xC000},
 		{"BVS", StatusRegister{overflowFlag: true}, 0x40, 0xC044},
-		{"BVS", StatusRegister{overflowFlag: false}, 0x40, 0xC003},
+		{"BVS", StatusRegister{overflowFlag: false}, 0x40, 0xC004},
 		{"BVS", StatusRegister{overflowFlag: true}, 0xFC, 0xC000},
 		{"BCC", StatusRegister{carryFlag: false}, 0x40, 0xC044},
-		{"BCC", StatusRegister{carryFlag: true}, 0x40, 0xC003},
+		{"BCC", StatusRegister{carryFlag: true}, 0x40, 0xC004},
 		{"BCC", StatusRegister{carryFlag: false}, 0xFC, 0xC000},
 		{"BCS", StatusRegister{carryFlag: true}, 0x40, 0xC044},
-		{"BCS", StatusRegister{carryFlag: false}, 0x40, 0xC003},
+		{"BCS", StatusRegister{carryFlag: false}, 0x40, 0xC004},
 		{"BCS", StatusRegister{carryFlag: true}, 0xFC, 0xC000},
 		{"BNE", StatusRegister{zeroFlag: false}, 0x40, 0xC044},
-		{"BNE", StatusRegister{zeroFlag: true}, 0x40, 0xC003},
+		{"BNE", StatusRegister{zeroFlag: true}, 0x40, 0xC004},
 		{"BNE", StatusRegister{zeroFlag: false}, 0xFC, 0xC000},
 		{"BEQ", StatusRegister{zeroFlag: true}, 0x40, 0xC044},
-		{"BEQ", StatusRegister{zeroFlag: false}, 0x40, 0xC003},
+		{"BEQ", StatusRegister{zeroFlag: false}, 0x40, 0xC004},
 		{"BEQ", StatusRegister{zeroFlag: true}, 0xFC, 0xC000},
 	}
 
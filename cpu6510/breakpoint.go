@@ -0,0 +1,314 @@
+package cpu6510
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Access is a kind of memory access a breakpoint watches. The kinds can be
+// combined, like AccessRead|AccessWrite for a watchpoint on both.
+type Access int
+
+const (
+	// AccessExec is the fetch of an opcode.
+	AccessExec Access = 1 << iota
+	// AccessRead is a read from memory, other than an opcode fetch.
+	AccessRead
+	// AccessWrite is a write to memory.
+	AccessWrite
+)
+
+func (a Access) String() string {
+	var names []string
+
+	if a&AccessExec != 0 {
+		names = append(names, "exec")
+	}
+	if a&AccessRead != 0 {
+		names = append(names, "read")
+	}
+	if a&AccessWrite != 0 {
+		names = append(names, "write")
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Breakpoint stops the CPU when it accesses an address in the range from
+// Start to End inclusive. A breakpoint on AccessExec stops before the
+// instruction at the address is executed, a watchpoint on AccessRead or
+// AccessWrite stops after the instruction that made the access.
+type Breakpoint struct {
+	// The number of the breakpoint, given by AddBreakpoint.
+	ID int
+	// The kinds of memory access to stop on.
+	Access Access
+	// The range of addresses, a single address when End is zero.
+	Start uint16
+	End   uint16
+	// A condition expression that has to hold for the breakpoint to stop the
+	// CPU, like A == $10 && X > 3. The registers A, X, Y, SP, PC and P can be
+	// compared with numbers, which are decimal, hexadecimal with a leading $
+	// or binary with a leading %. For a watchpoint the registers are checked
+	// in the middle of the instruction. An empty condition always holds.
+	Condition string
+	// The number of hits to let pass before the breakpoint stops the CPU.
+	IgnoreCount int
+	// The number of times the breakpoint has been hit, with its condition
+	// holding, including the ignored hits.
+	Hits int
+	// True while the breakpoint is turned off.
+	Disabled bool
+
+	condition condition
+}
+
+// BreakpointError is returned by Step when a breakpoint has stopped the CPU.
+type BreakpointError struct {
+	// The breakpoint that stopped the CPU, with the hit counted.
+	Breakpoint Breakpoint
+	// The access that hit the breakpoint.
+	Access Access
+	// The address that was accessed.
+	Address uint16
+	// The value that was read or written, or the opcode.
+	Value byte
+}
+
+func (e *BreakpointError) Error() string {
+	return fmt.Sprintf("cpu6510: breakpoint %d hit on %s of $%04X", e.Breakpoint.ID, e.Access, e.Address)
+}
+
+// breakpoints holds the breakpoints of a CPU. The CPU only has one while any
+// breakpoint is set, so that execution is not slowed down by them otherwise.
+type breakpoints struct {
+	list   []*Breakpoint
+	nextID int
+	// The kinds of access any enabled breakpoint watches.
+	watched Access
+	// The first breakpoint that stopped the CPU since the last Step.
+	hit *BreakpointError
+	// The address of the opcode where an exec breakpoint stopped the CPU,
+	// which does not stop it again when execution continues from there.
+	stoppedAt uint16
+	stopped   bool
+}
+
+// AddBreakpoint adds the breakpoint and returns its number.
+func (c *CPU) AddBreakpoint(breakpoint Breakpoint) (int, error) {
+	if breakpoint.Access == 0 {
+		return 0, fmt.Errorf("breakpoint must watch some kind of access")
+	}
+
+	if breakpoint.End == 0 {
+		breakpoint.End = breakpoint.Start
+	}
+
+	if breakpoint.End < breakpoint.Start {
+		return 0, fmt.Errorf("breakpoint range $%04X-$%04X is empty", breakpoint.Start, breakpoint.End)
+	}
+
+	if breakpoint.Condition != "" {
+		cond, err := parseCondition(breakpoint.Condition)
+		if err != nil {
+			return 0, err
+		}
+		breakpoint.condition = cond
+	}
+
+	if c.breakpoints == nil {
+		c.breakpoints = &breakpoints{nextID: 1}
+	}
+
+	breakpoint.ID = c.breakpoints.nextID
+	breakpoint.Hits = 0
+	c.breakpoints.nextID++
+	c.breakpoints.list = append(c.breakpoints.list, &breakpoint)
+	c.breakpoints.update()
+
+	return breakpoint.ID, nil
+}
+
+// RemoveBreakpoint removes the breakpoint with the number, and returns false
+// when there is none.
+func (c *CPU) RemoveBreakpoint(id int) bool {
+	if c.breakpoints == nil {
+		return false
+	}
+
+	for i, breakpoint := range c.breakpoints.list {
+		if breakpoint.ID == id {
+			c.breakpoints.list = append(c.breakpoints.list[:i], c.breakpoints.list[i+1:]...)
+
+			if len(c.breakpoints.list) == 0 {
+				c.breakpoints = nil
+			} else {
+				c.breakpoints.update()
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClearBreakpoints removes all breakpoints.
+func (c *CPU) ClearBreakpoints() {
+	c.breakpoints = nil
+}
+
+// EnableBreakpoint turns the breakpoint with the number on or off, and returns
+// false when there is none.
+func (c *CPU) EnableBreakpoint(id int, enabled bool) bool {
+	breakpoint := c.findBreakpoint(id)
+	if breakpoint == nil {
+		return false
+	}
+
+	breakpoint.Disabled = !enabled
+	c.breakpoints.update()
+
+	return true
+}
+
+// IgnoreBreakpoint lets the next count hits of the breakpoint with the number
+// pass, and returns false when there is none.
+func (c *CPU) IgnoreBreakpoint(id int, count int) bool {
+	breakpoint := c.findBreakpoint(id)
+	if breakpoint == nil {
+		return false
+	}
+
+	breakpoint.IgnoreCount = breakpoint.Hits + count
+
+	return true
+}
+
+// SetBreakpointCondition replaces the condition expression of the breakpoint
+// with the number. An empty condition always holds.
+func (c *CPU) SetBreakpointCondition(id int, expression string) error {
+	breakpoint := c.findBreakpoint(id)
+	if breakpoint == nil {
+		return fmt.Errorf("no breakpoint %d", id)
+	}
+
+	var cond condition
+	if expression != "" {
+		var err error
+		if cond, err = parseCondition(expression); err != nil {
+			return err
+		}
+	}
+
+	breakpoint.Condition = expression
+	breakpoint.condition = cond
+
+	return nil
+}
+
+// Breakpoints returns a copy of the breakpoints, in the order they were
+// added.
+func (c *CPU) Breakpoints() []Breakpoint {
+	if c.breakpoints == nil {
+		return nil
+	}
+
+	list := make([]Breakpoint, len(c.breakpoints.list))
+	for i, breakpoint := range c.breakpoints.list {
+		list[i] = *breakpoint
+	}
+
+	return list
+}
+
+// findBreakpoint returns the breakpoint with the number, or nil.
+func (c *CPU) findBreakpoint(id int) *Breakpoint {
+	if c.breakpoints == nil {
+		return nil
+	}
+
+	for _, breakpoint := range c.breakpoints.list {
+		if breakpoint.ID == id {
+			return breakpoint
+		}
+	}
+
+	return nil
+}
+
+// update collects the kinds of access the enabled breakpoints watch.
+func (b *breakpoints) update() {
+	b.watched = 0
+
+	for _, breakpoint := range b.list {
+		if !breakpoint.Disabled {
+			b.watched |= breakpoint.Access
+		}
+	}
+}
+
+// checkBreakpoints counts a hit on every breakpoint that watches the access,
+// and remembers the first one that stops the CPU. Breakpoints are not checked
+// while the CPU is driven by Tick.
+func (c *CPU) checkBreakpoints(access Access, address uint16, value byte) {
+	b := c.breakpoints
+	if b.watched&access == 0 || c.ticker != nil {
+		return
+	}
+
+	if access == AccessExec {
+		if b.stopped && b.stoppedAt == address {
+			b.stopped = false
+			return
+		}
+		b.stopped = false
+	}
+
+	state := c.State()
+
+	for _, breakpoint := range b.list {
+		if breakpoint.Disabled || breakpoint.Access&access == 0 {
+			continue
+		}
+
+		if address < breakpoint.Start || address > breakpoint.End {
+			continue
+		}
+
+		if breakpoint.condition != nil && !breakpoint.condition(state) {
+			continue
+		}
+
+		breakpoint.Hits++
+
+		if breakpoint.Hits <= breakpoint.IgnoreCount || b.hit != nil {
+			continue
+		}
+
+		b.hit = &BreakpointError{
+			Breakpoint: *breakpoint,
+			Access:     access,
+			Address:    address,
+			Value:      value,
+		}
+
+		if access == AccessExec {
+			b.stopped = true
+			b.stoppedAt = address
+		}
+	}
+}
+
+// breakpointHit returns the breakpoint that stopped the CPU since the last
+// call, if any.
+func (c *CPU) breakpointHit() error {
+	if c.breakpoints == nil || c.breakpoints.hit == nil {
+		return nil
+	}
+
+	hit := c.breakpoints.hit
+	c.breakpoints.hit = nil
+
+	return hit
+}
@@ -0,0 +1,256 @@
+package cpu6510
+
+import (
+	"errors"
+	"testing"
+)
+
+// newBreakpointCPU returns a CPU with a small program at $0200:
+//
+//	0200 LDA #$42
+//	0202 STA $1000
+//	0205 LDX $1000
+//	0208 INX
+//	0209 JMP $0200
+func newBreakpointCPU() *CPU {
+	cpu := NewCPU()
+	cpu.LoadAt(0x0200, []byte{
+		0xA9, 0x42,
+		0x8D, 0x00, 0x10,
+		0xAE, 0x00, 0x10,
+		0xE8,
+		0x4C, 0x00, 0x02,
+	}, SetPCToLoadAddress())
+
+	return cpu
+}
+
+// stepUntilBreak steps the CPU until a breakpoint stops it, at most limit
+// instructions.
+func stepUntilBreak(t *testing.T, cpu *CPU, limit int) *BreakpointError {
+	t.Helper()
+
+	for i := 0; i < limit; i++ {
+		_, err := cpu.Step()
+
+		var hit *BreakpointError
+		if errors.As(err, &hit) {
+			return hit
+		}
+
+		if err != nil {
+			t.Fatalf("Step error: %v", err)
+		}
+	}
+
+	t.Fatalf("Breakpoint should stop the CPU within %d instructions", limit)
+
+	return nil
+}
+
+func TestExecBreakpoint(t *testing.T) {
+	cpu := newBreakpointCPU()
+	id, err := cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0208})
+	if err != nil {
+		t.Fatalf("AddBreakpoint error: %v", err)
+	}
+
+	hit := stepUntilBreak(t, cpu, 10)
+
+	if hit.Breakpoint.ID != id || hit.Access != AccessExec || hit.Address != 0x0208 || hit.Value != 0xE8 {
+		t.Errorf("Breakpoint should stop on exec of $0208, got %+v", hit)
+	}
+
+	if cpu.programCounter != 0x0208 || cpu.xRegister != 0x42 {
+		t.Errorf("CPU should stop before INX is executed")
+	}
+
+	if _, err := cpu.Step(); err != nil || cpu.xRegister != 0x43 {
+		t.Errorf("CPU should continue with INX, got %v", err)
+	}
+
+	hit = stepUntilBreak(t, cpu, 10)
+
+	if hit.Breakpoint.Hits != 2 {
+		t.Errorf("Breakpoint should be hit twice, got %d", hit.Breakpoint.Hits)
+	}
+}
+
+func TestWatchpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  Access
+		address uint16
+		pc      uint16
+	}{
+		{"Stop after a write", AccessWrite, 0x0202, 0x0205},
+		{"Stop after a read", AccessRead, 0x0205, 0x0208},
+		{"Stop after either", AccessRead | AccessWrite, 0x0202, 0x0205},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := newBreakpointCPU()
+			cpu.AddBreakpoint(Breakpoint{Access: test.access, Start: 0x0FF0, End: 0x10FF})
+
+			hit := stepUntilBreak(t, cpu, 10)
+
+			if hit.Address != 0x1000 || hit.Value != 0x42 {
+				t.Errorf("Watchpoint should report the access of $1000, got %+v", hit)
+			}
+
+			if cpu.programCounter != test.pc {
+				t.Errorf("CPU should stop after the instruction at $%04X, PC is $%04X", test.address, cpu.programCounter)
+			}
+		})
+	}
+}
+
+func TestOpcodeFetchIsNotARead(t *testing.T) {
+	cpu := newBreakpointCPU()
+	cpu.AddBreakpoint(Breakpoint{Access: AccessRead, Start: 0x0208})
+
+	for i := 0; i < 5; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Opcode fetch should not hit a read watchpoint, got %v", err)
+		}
+	}
+}
+
+func TestConditionalBreakpoint(t *testing.T) {
+	cpu := newBreakpointCPU()
+	cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0000, End: 0xFFFF, Condition: "X == $43"})
+
+	stepUntilBreak(t, cpu, 30)
+
+	if cpu.xRegister != 0x43 || cpu.programCounter != 0x0209 {
+		t.Errorf("CPU should stop when X becomes $43, got X=$%02X at $%04X", cpu.xRegister, cpu.programCounter)
+	}
+}
+
+func TestSetBreakpointCondition(t *testing.T) {
+	cpu := newBreakpointCPU()
+	id, _ := cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0208})
+
+	if err := cpu.SetBreakpointCondition(id, "X == $41"); err != nil {
+		t.Fatalf("SetBreakpointCondition error: %v", err)
+	}
+
+	for i := 0; i < 30; i++ {
+		if _, err := cpu.Step(); err != nil {
+			t.Fatalf("Breakpoint should not stop while X is never $41, got %v", err)
+		}
+	}
+
+	cpu.SetBreakpointCondition(id, "X == $42")
+
+	if hit := stepUntilBreak(t, cpu, 30); hit.Breakpoint.Condition != "X == $42" {
+		t.Errorf("Breakpoint should stop with the new condition, got %q", hit.Breakpoint.Condition)
+	}
+
+	if cpu.SetBreakpointCondition(id, "X ==") == nil || cpu.SetBreakpointCondition(id+1, "") == nil {
+		t.Errorf("Invalid condition or breakpoint should fail")
+	}
+
+	cpu.SetBreakpointCondition(id, "")
+
+	if cpu.Breakpoints()[0].Condition != "" {
+		t.Errorf("Condition should be removed")
+	}
+}
+
+func TestBreakpointIgnoreCount(t *testing.T) {
+	cpu := newBreakpointCPU()
+	id, _ := cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0200, IgnoreCount: 2})
+
+	hit := stepUntilBreak(t, cpu, 20)
+
+	if hit.Breakpoint.Hits != 3 {
+		t.Errorf("Breakpoint should stop on the third hit, got %d", hit.Breakpoint.Hits)
+	}
+
+	cpu.IgnoreBreakpoint(id, 1)
+	hit = stepUntilBreak(t, cpu, 20)
+
+	if hit.Breakpoint.Hits != 5 {
+		t.Errorf("Breakpoint should stop on the fifth hit, got %d", hit.Breakpoint.Hits)
+	}
+}
+
+func TestManageBreakpoints(t *testing.T) {
+	cpu := newBreakpointCPU()
+
+	first, _ := cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0208})
+	second, _ := cpu.AddBreakpoint(Breakpoint{Access: AccessWrite, Start: 0x1000})
+
+	if len(cpu.Breakpoints()) != 2 || first == second {
+		t.Errorf("There should be two breakpoints with different numbers")
+	}
+
+	cpu.EnableBreakpoint(second, false)
+
+	if hit := stepUntilBreak(t, cpu, 10); hit.Breakpoint.ID != first {
+		t.Errorf("Disabled breakpoint should not stop the CPU")
+	}
+
+	if !cpu.RemoveBreakpoint(first) || cpu.RemoveBreakpoint(first) {
+		t.Errorf("Breakpoint should be removed once")
+	}
+
+	if cpu.EnableBreakpoint(first, true) {
+		t.Errorf("Removed breakpoint should not be found")
+	}
+
+	cpu.RemoveBreakpoint(second)
+
+	if cpu.breakpoints != nil {
+		t.Errorf("Breakpoints should not be checked when none are set")
+	}
+}
+
+func TestAddBreakpointErrors(t *testing.T) {
+	cpu := NewCPU()
+
+	for _, breakpoint := range []Breakpoint{
+		{Start: 0x1000},
+		{Access: AccessExec, Start: 0x2000, End: 0x1000},
+		{Access: AccessExec, Start: 0x1000, Condition: "A ="},
+	} {
+		if _, err := cpu.AddBreakpoint(breakpoint); err == nil {
+			t.Errorf("Breakpoint %+v should not be added", breakpoint)
+		}
+	}
+}
+
+func TestRunStopsAtBreakpoint(t *testing.T) {
+	cpu := newBreakpointCPU()
+	cpu.AddBreakpoint(Breakpoint{Access: AccessExec, Start: 0x0209})
+
+	err := cpu.Run()
+
+	var hit *BreakpointError
+	if !errors.As(err, &hit) || cpu.programCounter != 0x0209 {
+		t.Errorf("Run should stop at the breakpoint, got %v", err)
+	}
+}
+
+func TestBreakpointsAreNotCheckedByTick(t *testing.T) {
+	cpu := newBreakpointCPU()
+	cpu.AddBreakpoint(Breakpoint{Access: AccessExec | AccessRead | AccessWrite, Start: 0x0000, End: 0xFFFF})
+
+	for i := 0; i < 4; i++ {
+		tickInstruction(cpu)
+	}
+
+	if cpu.xRegister != 0x43 || cpu.breakpoints.list[0].Hits != 0 {
+		t.Errorf("Tick should run past the breakpoints")
+	}
+}
+
+func TestBreakpointErrorMessage(t *testing.T) {
+	err := &BreakpointError{Breakpoint: Breakpoint{ID: 2}, Access: AccessWrite, Address: 0xD020}
+
+	if err.Error() != "cpu6510: breakpoint 2 hit on write of $D020" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
@@ -0,0 +1,24 @@
+package cpu6510
+
+// Bus is the address and data bus the CPU6510 uses to access memory. The bus
+// decides what answers at each address, so RAM, ROM and I/O chips can be
+// mapped in by implementing it.
+type Bus interface {
+	// Read returns the byte at the given address.
+	Read(address uint16) byte
+	// Write writes the byte to the given address.
+	Write(address uint16, value byte)
+}
+
+// RAM is 64KB of flat memory, where every address can be read and written.
+type RAM [memorySize]byte
+
+// Read returns the byte at the given address.
+func (r *RAM) Read(address uint16) byte {
+	return r[address]
+}
+
+// Write writes the byte to the given address.
+func (r *RAM) Write(address uint16, value byte) {
+	r[address] = value
+}
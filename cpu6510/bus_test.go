@@ -0,0 +1,57 @@
+package cpu6510
+
+import "testing"
+
+// romBus is a bus with RAM below $E000 and ROM from $E000, where writes to
+// the ROM are ignored.
+type romBus struct {
+	ram    RAM
+	writes []uint16
+}
+
+func (b *romBus) Read(address uint16) byte {
+	return b.ram[address]
+}
+
+func (b *romBus) Write(address uint16, value byte) {
+	b.writes = append(b.writes, address)
+
+	if address < 0xE000 {
+		b.ram[address] = value
+	}
+}
+
+func TestRAM(t *testing.T) {
+	ram := &RAM{}
+
+	ram.Write(0x1337, 0x42)
+
+	if ram.Read(0x1337) != 0x42 {
+		t.Errorf("RAM should return the written value")
+	}
+}
+
+func TestCPUWithBus(t *testing.T) {
+	bus := &romBus{}
+	// LDA #$42, STA $1337, STA $E000, BRK
+	copy(bus.ram[0x0200:], []byte{0xA9, 0x42, 0x8D, 0x37, 0x13, 0x8D, 0x00, 0xE0, 0x00})
+	bus.ram[0xFFFC] = 0x00
+	bus.ram[0xFFFD] = 0x02
+
+	cpu := NewCPUWithBus(bus)
+	cpu.Reset()
+	cpu.Run()
+
+	if bus.ram[0x1337] != 0x42 {
+		t.Errorf("Memory at 0x1337 should be 0x42")
+	}
+
+	if bus.ram[0xE000] != 0x00 {
+		t.Errorf("Memory at 0xE000 should not be written")
+	}
+
+	// STA $1337, STA $E000, and BRK pushing three bytes onto the stack.
+	if len(bus.writes) != 5 {
+		t.Errorf("Bus should be written 5 times, got %d", len(bus.writes))
+	}
+}
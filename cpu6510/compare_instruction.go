@@ -59,8 +59,6 @@ func CMPIndexedIndirect(c *CPU) {
 // register based on the result.
 func CMPIndirectIndexed(c *CPU) {
 	cmp(c, c.getValueByIndirectIndexedAddressingMode)
-
-	c.programCounter++
 }
 
 // CMPZeroPage - CoMPare. CMP compares the value in the accumulator with the
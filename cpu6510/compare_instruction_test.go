@@ -567,6 +567,10 @@ func TestCMPIndirectIndexed(t *testing.T) {
 		if cpu.statusRegister.negativeFlag {
 			t.Errorf("Negative flag should be cleared")
 		}
+
+		if cpu.programCounter != 2 {
+			t.Errorf("Program counter should be incremented by 2, got 0x%04X", cpu.programCounter)
+		}
 	})
 
 	t.Run("Accumulator is less than memory", func(t *testing.T) {
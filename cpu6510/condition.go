@@ -0,0 +1,237 @@
+package cpu6510
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// condition is a compiled condition expression of a breakpoint.
+type condition func(State) bool
+
+// The registers that can be used in a condition expression.
+var conditionRegisters = map[string]func(State) int{
+	"A":  func(s State) int { return int(s.A) },
+	"X":  func(s State) int { return int(s.X) },
+	"Y":  func(s State) int { return int(s.Y) },
+	"SP": func(s State) int { return int(s.SP) },
+	"PC": func(s State) int { return int(s.PC) },
+	"P":  func(s State) int { return int(s.P) },
+}
+
+// The comparison operators that can be used in a condition expression.
+var conditionOperators = map[string]func(a int, b int) bool{
+	"==": func(a int, b int) bool { return a == b },
+	"!=": func(a int, b int) bool { return a != b },
+	"<":  func(a int, b int) bool { return a < b },
+	"<=": func(a int, b int) bool { return a <= b },
+	">":  func(a int, b int) bool { return a > b },
+	">=": func(a int, b int) bool { return a >= b },
+}
+
+// conditionParser parses a condition expression, which compares registers
+// and numbers, like A == $10 && (X > 3 || PC != $C000). A register can be
+// written with a leading dot, like .A, and a number is decimal, hexadecimal
+// with a leading $, or binary with a leading %.
+type conditionParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseCondition compiles the condition expression.
+func parseCondition(expression string) (condition, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &conditionParser{tokens: tokens}
+
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in condition", p.tokens[p.pos])
+	}
+
+	return cond, nil
+}
+
+// tokenizeCondition splits the condition expression into tokens.
+func tokenizeCondition(expression string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(expression); {
+		char := rune(expression[i])
+
+		switch {
+		case unicode.IsSpace(char):
+			i++
+		case char == '(' || char == ')':
+			tokens = append(tokens, string(char))
+			i++
+		case strings.ContainsRune("=!<>&|", char):
+			j := i + 1
+			for j < len(expression) && strings.ContainsRune("=&|", rune(expression[j])) {
+				j++
+			}
+			tokens = append(tokens, expression[i:j])
+			i = j
+		case char == '$' || char == '%' || char == '.' || unicode.IsLetter(char) || unicode.IsDigit(char):
+			j := i + 1
+			for j < len(expression) && (unicode.IsLetter(rune(expression[j])) || unicode.IsDigit(rune(expression[j]))) {
+				j++
+			}
+			tokens = append(tokens, expression[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in condition", char)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	return tokens, nil
+}
+
+// next returns the next token, or an empty string at the end.
+func (p *conditionParser) next() string {
+	if p.pos == len(p.tokens) {
+		return ""
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	return token
+}
+
+// peek returns the next token without consuming it.
+func (p *conditionParser) peek() string {
+	if p.pos == len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+// or parses comparisons joined by ||.
+func (p *conditionParser) or() (condition, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "||" {
+		p.next()
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		a, b := left, right
+		left = func(s State) bool { return a(s) || b(s) }
+	}
+
+	return left, nil
+}
+
+// and parses comparisons joined by &&.
+func (p *conditionParser) and() (condition, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "&&" {
+		p.next()
+
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+
+		a, b := left, right
+		left = func(s State) bool { return a(s) && b(s) }
+	}
+
+	return left, nil
+}
+
+// comparison parses a comparison of two operands, or an expression in
+// parentheses.
+func (p *conditionParser) comparison() (condition, error) {
+	if p.peek() == "(" {
+		p.next()
+
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ) in condition")
+		}
+
+		return cond, nil
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.next()
+	compare, ok := conditionOperators[token]
+	if !ok {
+		return nil, fmt.Errorf("expected a comparison in condition, got %q", token)
+	}
+
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s State) bool { return compare(left(s), right(s)) }, nil
+}
+
+// operand parses a register or a number.
+func (p *conditionParser) operand() (func(State) int, error) {
+	token := p.next()
+
+	if register, ok := conditionRegisters[strings.ToUpper(strings.TrimPrefix(token, "."))]; ok {
+		return register, nil
+	}
+
+	value, err := parseNumber(token)
+	if err != nil {
+		return nil, fmt.Errorf("expected a register or a number in condition, got %q", token)
+	}
+
+	return func(State) int { return value }, nil
+}
+
+// parseNumber parses a decimal number, a hexadecimal number with a leading $,
+// or a binary number with a leading %.
+func parseNumber(token string) (int, error) {
+	base := 10
+
+	switch {
+	case strings.HasPrefix(token, "$"):
+		base = 16
+		token = token[1:]
+	case strings.HasPrefix(token, "%"):
+		base = 2
+		token = token[1:]
+	}
+
+	value, err := strconv.ParseUint(token, base, 16)
+
+	return int(value), err
+}
@@ -0,0 +1,61 @@
+package cpu6510
+
+import "testing"
+
+func TestParseCondition(t *testing.T) {
+	state := State{A: 0x10, X: 3, Y: 0xFF, SP: 0xFD, PC: 0xC000, P: 0x24}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"A == $10", true},
+		{"A != 16", false},
+		{".X < 4", true},
+		{"Y >= %11111111", true},
+		{"SP > $FD", false},
+		{"PC <= $C000", true},
+		{"P == $24", true},
+		{"a == $10", true},
+		{"A==$10&&X==3", true},
+		{"A == $10 && X == 4", false},
+		{"A == $11 || X == 3", true},
+		{"A == $11 || X == 3 && Y == 0", false},
+		{"(A == $11 || X == 3) && Y == $FF", true},
+		{"$C000 == PC", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			cond, err := parseCondition(test.expression)
+			if err != nil {
+				t.Fatalf("Condition should parse, got %v", err)
+			}
+
+			if cond(state) != test.expected {
+				t.Errorf("Condition should be %v", test.expected)
+			}
+		})
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"A",
+		"A = 1",
+		"A == ",
+		"Q == 1",
+		"A == $10000",
+		"(A == 1",
+		"A == 1)",
+		"A == 1 &&",
+		"A == #1",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := parseCondition(expression); err == nil {
+				t.Errorf("Condition %q should not parse", expression)
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 package cpu6510
 
-import "fmt"
+import "io"
 
 // The memory size of the CPU6510 is 64KB (65536 Bytes).
 const memorySize = 65536
@@ -47,6 +47,14 @@ func newStatusRegister(value byte) StatusRegister {
 	}
 }
 
+// loadStatusRegister creates the status register for a value that is loaded
+// into it, by PLP, RTI or SetStatus. The B flag only exists in the copies of
+// the register pushed by PHP and BRK, so it is cleared, and the unused bit is
+// always set.
+func loadStatusRegister(value byte) StatusRegister {
+	return newStatusRegister(value&^0x10 | 0x20)
+}
+
 // asByte returns the status register as a byte.
 func (sr *StatusRegister) asByte() byte {
 	var value byte
@@ -91,9 +99,16 @@ type CPU struct {
 	// about the most recently performed ALU operation, control the enabling
 	// and disabling of interrupts and set the CPU operating mode.
 	statusRegister StatusRegister
+	// The bus connects the CPU6510 to the memory and the I/O chips. Every
+	// memory access the CPU makes goes through the bus.
+	bus Bus
 	// The Random Access Memory (RAM) is a 64KB (65536 Bytes) memory that
-	// stores the program and data that the CPU6510 will execute.
-	ram [memorySize]byte
+	// stores the program and data that the CPU6510 will execute. It is nil
+	// when the CPU is connected to a bus of its own.
+	ram *RAM
+	// The on-chip I/O port at $0000/$0001, which sits between the CPU and the
+	// bus. It is nil when the CPU is connected to flat RAM.
+	ioPort *IOPort
 	// The X index register is an 8-Bit data register. The register is used
 	// in the Indexed Indirect, and Absolute indexed by X addressing modes.
 	xRegister byte
@@ -115,11 +130,82 @@ type CPU struct {
 	stackPointer byte
 	// True when an illegal JAM/KIL opcode has halted the CPU.
 	isJammed bool
+	// True while the IRQ line is asserted.
+	irqLine bool
+	// True when an NMI has been triggered but not yet taken.
+	nmiPending bool
+	// The number of clock cycles the CPU has executed.
+	cycles uint64
+	// The magic constants the unstable ANE and LXA opcodes OR the
+	// accumulator with.
+	aneMagic byte
+	lxaMagic byte
+	// How the CPU reacts to a JAM opcode, and the hook it calls when the
+	// policy is HookJAM.
+	jamPolicy JAMPolicy
+	jamHook   JAMHook
+	// Receives a line for every instruction that is executed, nil when
+	// tracing is turned off.
+	tracer io.Writer
+	// The breakpoints and watchpoints, nil when none are set.
+	breakpoints *breakpoints
+	// The traps that emulate routines, by address, nil when none are set.
+	traps map[uint16]Trap
+	// Runs the current instruction one cycle at a time when the CPU is
+	// driven by Tick, nil otherwise. It points to tickerState, which is kept
+	// between instructions to reuse its log.
+	ticker      *ticker
+	tickerState ticker
+	// The error of the last instruction completed by Tick.
+	tickErr error
+}
+
+// Option configures the behaviour of a CPU6510 processor that differs
+// between chips.
+type Option func(*CPU)
+
+// WithANEMagic sets the magic constant the unstable ANE opcode ORs the
+// accumulator with, which is $EE by default.
+func WithANEMagic(magic byte) Option {
+	return func(c *CPU) {
+		c.aneMagic = magic
+	}
+}
+
+// WithLXAMagic sets the magic constant the unstable LXA opcode ORs the
+// accumulator with, which is $EE by default.
+func WithLXAMagic(magic byte) Option {
+	return func(c *CPU) {
+		c.lxaMagic = magic
+	}
 }
 
-// NewCPU creates a new CPU6510 processor.
-func NewCPU() *CPU {
-	return &CPU{
+// NewCPU creates a new CPU6510 processor, connected to 64KB of flat RAM.
+// Without a bus there is nothing for the I/O port to control, so $0000 and
+// $0001 are plain RAM, like on a 6502.
+func NewCPU(options ...Option) *CPU {
+	ram := &RAM{}
+
+	c := newCPU(ram, options)
+	c.ram = ram
+
+	return c
+}
+
+// NewCPUWithBus creates a new CPU6510 processor, connected to the given bus
+// through its I/O port.
+func NewCPUWithBus(bus Bus, options ...Option) *CPU {
+	c := newCPU(bus, options)
+	c.ioPort = newIOPort(bus, c.Cycles)
+	c.bus = c.ioPort
+
+	return c
+}
+
+// newCPU creates a new CPU6510 processor, connected directly to the bus.
+func newCPU(bus Bus, options []Option) *CPU {
+	c := &CPU{
+		bus:            bus,
 		programCounter: 0,
 		statusRegister: StatusRegister{
 			carryFlag:            false,
@@ -135,66 +221,207 @@ func NewCPU() *CPU {
 		yRegister:    0,
 		accumulator:  0,
 		stackPointer: 0xFF,
+		aneMagic:     defaultMagicConstant,
+		lxaMagic:     defaultMagicConstant,
+	}
+
+	for _, option := range options {
+		option(c)
 	}
+
+	return c
 }
 
 func (c *CPU) pushOnStack(value byte) {
-	c.ram[stackBase+uint16(c.stackPointer)] = value
+	c.writeMemory(stackBase+uint16(c.stackPointer), value)
 	c.stackPointer--
 }
 
 func (c *CPU) popFromStack() byte {
 	c.stackPointer++
-	return c.ram[stackBase+uint16(c.stackPointer)]
+	return c.readMemory(stackBase + uint16(c.stackPointer))
 }
 
 // Next fetches the next instruction from memory.
 func (c *CPU) next() byte {
-	instruction := c.ram[c.programCounter]
+	instruction := c.accessMemory(c.programCounter, AccessExec)
 
 	return instruction
 }
 
 // readAddressFromMemory reads the address from the next two bytes in memory.
 func (c *CPU) readAddressFromMemory() uint16 {
-	var lowByte byte = c.ram[c.programCounter]
-	var highByte byte = c.ram[c.programCounter+1]
+	var lowByte byte = c.readMemory(c.programCounter)
+	var highByte byte = c.readMemory(c.programCounter + 1)
 
 	return ConvertTwoBytesToAddress(highByte, lowByte)
 }
 
 // readMemory reads the byte at the given address in memory.
 func (c *CPU) readMemory(address uint16) byte {
-	return c.ram[address]
+	return c.accessMemory(address, AccessRead)
+}
+
+// accessMemory reads the byte at the given address in memory, either to fetch
+// an opcode or as a plain read.
+func (c *CPU) accessMemory(address uint16, access Access) byte {
+	if c.ticker != nil {
+		if value, skip := c.ticker.skip(c); skip {
+			return value
+		}
+	}
+
+	value := c.bus.Read(address)
+
+	if c.ticker != nil {
+		c.ticker.record(BusCycle{Address: address, Value: value})
+	}
+
+	if c.breakpoints != nil {
+		c.checkBreakpoints(access, address, value)
+	}
+
+	return value
 }
 
 // writeMemory writes the byte at the given address in memory.
 func (c *CPU) writeMemory(address uint16, value byte) {
-	c.ram[address] = value
+	if c.ticker != nil {
+		if _, skip := c.ticker.skip(c); skip {
+			return
+		}
+		c.ticker.record(BusCycle{Address: address, Value: value, Write: true})
+	}
+
+	c.bus.Write(address, value)
+
+	if c.breakpoints != nil {
+		c.checkBreakpoints(AccessWrite, address, value)
+	}
 }
 
-// Execute executes the instruction.
-func (c *CPU) execute(instruction byte) {
+// dummyRead reads the byte at the given address and throws it away. The CPU
+// accesses the bus on every cycle, also when it has nothing useful to read.
+func (c *CPU) dummyRead(address uint16) {
+	c.readMemory(address)
+}
+
+// Execute executes the instruction. A JAM opcode is handled according to the
+// JAM policy of the CPU.
+func (c *CPU) execute(instruction byte) error {
 	if c.isJammed {
-		return
+		return nil
+	}
+
+	if c.tracer != nil {
+		c.trace()
 	}
 
-	if runInstruction, ok := lookupInstruction[instruction]; ok {
-		runInstruction(c)
-	} else {
-		panic(fmt.Sprintf("Unknown instruction, %x", instruction))
+	if isJAMOpcode(instruction) {
+		return c.jam(instruction)
 	}
+
+	lookupInstruction[instruction](c)
+	c.cycles += instructionCycles[instruction]
+
+	return nil
+}
+
+// IOPort returns the on-chip I/O port of the CPU, or nil when the CPU is
+// connected to flat RAM.
+func (c *CPU) IOPort() *IOPort {
+	return c.ioPort
+}
+
+// Peek returns the byte at the given address as the CPU sees it, without
+// using any clock cycles or hitting any breakpoints. It is meant for
+// debuggers, which look at memory between instructions.
+func (c *CPU) Peek(address uint16) byte {
+	return c.bus.Read(address)
 }
 
-// Run the CPU.
-func (c *CPU) Run() {
+// Poke writes the byte to the given address as the CPU would, without using
+// any clock cycles or hitting any breakpoints.
+func (c *CPU) Poke(address uint16, value byte) {
+	c.bus.Write(address, value)
+}
+
+// Cycles returns the number of clock cycles the CPU has executed.
+func (c *CPU) Cycles() uint64 {
+	return c.cycles
+}
+
+// Step executes a single instruction, or enters a pending interrupt, and
+// returns the number of clock cycles it took. A trap set on the program
+// counter is run instead of the instruction. The error is a *JAMError when
+// the instruction jams the CPU, ErrJammed when the CPU is already jammed, a
+// *BreakpointError when a breakpoint has stopped the CPU, or the error of the
+// trap.
+func (c *CPU) Step() (int, error) {
+	if c.isJammed {
+		return 0, ErrJammed
+	}
+
+	start := c.cycles
+
+	var err error
+	if !c.serviceInterrupt() {
+		var trapped bool
+		if trapped, err = c.runTrap(); !trapped {
+			instruction := c.next()
+			if err := c.breakpointHit(); err != nil {
+				return 0, err
+			}
+
+			err = c.execute(instruction)
+		}
+	}
+
+	if err == nil {
+		err = c.breakpointHit()
+	}
+
+	return int(c.cycles - start), err
+}
+
+// Run the CPU until it executes a BRK instruction. The error is returned when
+// a JAM opcode, a breakpoint or a trap stops it.
+func (c *CPU) Run() error {
 	for {
+		if c.serviceInterrupt() {
+			if err := c.breakpointHit(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if trapped, err := c.runTrap(); trapped {
+			if err != nil {
+				return err
+			}
+
+			if err := c.breakpointHit(); err != nil {
+				return err
+			}
+			continue
+		}
+
 		instruction := c.next()
-		c.execute(instruction)
+		if err := c.breakpointHit(); err != nil {
+			return err
+		}
+
+		if err := c.execute(instruction); err != nil {
+			return err
+		}
+
+		if err := c.breakpointHit(); err != nil {
+			return err
+		}
 
 		// Exit the loop when the instruction is BRK (0x00), or when jammed.
 		if instruction == 0x00 || c.isJammed {
-			break
+			return nil
 		}
 	}
 }
@@ -100,13 +100,17 @@ func TestStatusRegister(t *testing.T) {
 	})
 
 	t.Run("Break status flag", func(t *testing.T) {
-		t.Run("Set the break status flag", func(t *testing.T) {
+		t.Run("Push the break status flag", func(t *testing.T) {
 			cpu := NewCPU()
 
 			cpu.execute(InstructionAsHex("BRK"))
 
-			if !cpu.statusRegister.breakCommandFlag {
-				t.Errorf("Break status flag should be set")
+			if cpu.statusRegister.breakCommandFlag {
+				t.Errorf("Break status flag should not be set in the status register")
+			}
+
+			if cpu.ram[0x01FD]&0x10 != 0x10 {
+				t.Errorf("Break status flag should be set in the pushed status register")
 			}
 		})
 	})
@@ -137,3 +141,23 @@ func TestRunCpu(t *testing.T) {
 		t.Errorf("Carry flag should be cleared")
 	}
 }
+
+func TestPeekAndPoke(t *testing.T) {
+	cpu := NewCPUWithBus(&RAM{})
+	cpu.AddBreakpoint(Breakpoint{Access: AccessRead | AccessWrite, Start: 0x0000, End: 0xFFFF})
+
+	cpu.Poke(0x0000, 0x2F)
+	cpu.Poke(0xC000, 0x42)
+
+	if cpu.Peek(0xC000) != 0x42 {
+		t.Errorf("Peek should read what was poked")
+	}
+
+	if cpu.IOPort().Read(0x0000) != 0x2F {
+		t.Errorf("Poke should write the I/O port like the CPU")
+	}
+
+	if cpu.cycles != 0 || cpu.breakpointHit() != nil {
+		t.Errorf("Peek and Poke should not use cycles or hit breakpoints")
+	}
+}
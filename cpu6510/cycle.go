@@ -0,0 +1,257 @@
+package cpu6510
+
+// instructionCycles holds the number of clock cycles each instruction takes,
+// not counting the extra cycle for crossing a page boundary in the indexed
+// read addressing modes, or the extra cycles for a taken branch.
+var instructionCycles = map[byte]uint64{
+	0x00: 7, // BRK
+	0x01: 6, // ORA (zp,X)
+	0x03: 8, // SLO (zp,X)
+	0x04: 3, // NOP zp
+	0x05: 3, // ORA zp
+	0x06: 5, // ASL zp
+	0x07: 5, // SLO zp
+	0x08: 3, // PHP
+	0x09: 2, // ORA #
+	0x0A: 2, // ASL A
+	0x0B: 2, // ANC #
+	0x0C: 4, // NOP abs
+	0x0D: 4, // ORA abs
+	0x0E: 6, // ASL abs
+	0x0F: 6, // SLO abs
+	0x10: 2, // BPL
+	0x11: 5, // ORA (zp),Y
+	0x13: 8, // SLO (zp),Y
+	0x14: 4, // NOP zp,X
+	0x15: 4, // ORA zp,X
+	0x16: 6, // ASL zp,X
+	0x17: 6, // SLO zp,X
+	0x18: 2, // CLC
+	0x19: 4, // ORA abs,Y
+	0x1A: 2, // NOP
+	0x1B: 7, // SLO abs,Y
+	0x1C: 4, // NOP abs,X
+	0x1D: 4, // ORA abs,X
+	0x1E: 7, // ASL abs,X
+	0x1F: 7, // SLO abs,X
+	0x20: 6, // JSR
+	0x21: 6, // AND (zp,X)
+	0x23: 8, // RLA (zp,X)
+	0x24: 3, // BIT zp
+	0x25: 3, // AND zp
+	0x26: 5, // ROL zp
+	0x27: 5, // RLA zp
+	0x28: 4, // PLP
+	0x29: 2, // AND #
+	0x2A: 2, // ROL A
+	0x2B: 2, // ANC #
+	0x2C: 4, // BIT abs
+	0x2D: 4, // AND abs
+	0x2E: 6, // ROL abs
+	0x2F: 6, // RLA abs
+	0x30: 2, // BMI
+	0x31: 5, // AND (zp),Y
+	0x33: 8, // RLA (zp),Y
+	0x34: 4, // NOP zp,X
+	0x35: 4, // AND zp,X
+	0x36: 6, // ROL zp,X
+	0x37: 6, // RLA zp,X
+	0x38: 2, // SEC
+	0x39: 4, // AND abs,Y
+	0x3A: 2, // NOP
+	0x3B: 7, // RLA abs,Y
+	0x3C: 4, // NOP abs,X
+	0x3D: 4, // AND abs,X
+	0x3E: 7, // ROL abs,X
+	0x3F: 7, // RLA abs,X
+	0x40: 6, // RTI
+	0x41: 6, // EOR (zp,X)
+	0x43: 8, // SRE (zp,X)
+	0x44: 3, // NOP zp
+	0x45: 3, // EOR zp
+	0x46: 5, // LSR zp
+	0x47: 5, // SRE zp
+	0x48: 3, // PHA
+	0x49: 2, // EOR #
+	0x4A: 2, // LSR A
+	0x4B: 2, // ALR #
+	0x4C: 3, // JMP abs
+	0x4D: 4, // EOR abs
+	0x4E: 6, // LSR abs
+	0x4F: 6, // SRE abs
+	0x50: 2, // BVC
+	0x51: 5, // EOR (zp),Y
+	0x53: 8, // SRE (zp),Y
+	0x54: 4, // NOP zp,X
+	0x55: 4, // EOR zp,X
+	0x56: 6, // LSR zp,X
+	0x57: 6, // SRE zp,X
+	0x58: 2, // CLI
+	0x59: 4, // EOR abs,Y
+	0x5A: 2, // NOP
+	0x5B: 7, // SRE abs,Y
+	0x5C: 4, // NOP abs,X
+	0x5D: 4, // EOR abs,X
+	0x5E: 7, // LSR abs,X
+	0x5F: 7, // SRE abs,X
+	0x60: 6, // RTS
+	0x61: 6, // ADC (zp,X)
+	0x63: 8, // RRA (zp,X)
+	0x64: 3, // NOP zp
+	0x65: 3, // ADC zp
+	0x66: 5, // ROR zp
+	0x67: 5, // RRA zp
+	0x68: 4, // PLA
+	0x69: 2, // ADC #
+	0x6A: 2, // ROR A
+	0x6B: 2, // ARR #
+	0x6C: 5, // JMP (abs)
+	0x6D: 4, // ADC abs
+	0x6E: 6, // ROR abs
+	0x6F: 6, // RRA abs
+	0x70: 2, // BVS
+	0x71: 5, // ADC (zp),Y
+	0x73: 8, // RRA (zp),Y
+	0x74: 4, // NOP zp,X
+	0x75: 4, // ADC zp,X
+	0x76: 6, // ROR zp,X
+	0x77: 6, // RRA zp,X
+	0x78: 2, // SEI
+	0x79: 4, // ADC abs,Y
+	0x7A: 2, // NOP
+	0x7B: 7, // RRA abs,Y
+	0x7C: 4, // NOP abs,X
+	0x7D: 4, // ADC abs,X
+	0x7E: 7, // ROR abs,X
+	0x7F: 7, // RRA abs,X
+	0x80: 2, // NOP #
+	0x81: 6, // STA (zp,X)
+	0x82: 2, // NOP #
+	0x83: 6, // SAX (zp,X)
+	0x84: 3, // STY zp
+	0x85: 3, // STA zp
+	0x86: 3, // STX zp
+	0x87: 3, // SAX zp
+	0x88: 2, // DEY
+	0x89: 2, // NOP #
+	0x8A: 2, // TXA
+	0x8B: 2, // ANE #
+	0x8C: 4, // STY abs
+	0x8D: 4, // STA abs
+	0x8E: 4, // STX abs
+	0x8F: 4, // SAX abs
+	0x90: 2, // BCC
+	0x91: 6, // STA (zp),Y
+	0x93: 6, // SHA (zp),Y
+	0x94: 4, // STY zp,X
+	0x95: 4, // STA zp,X
+	0x96: 4, // STX zp,Y
+	0x97: 4, // SAX zp,Y
+	0x98: 2, // TYA
+	0x99: 5, // STA abs,Y
+	0x9A: 2, // TXS
+	0x9B: 5, // TAS abs,Y
+	0x9C: 5, // SHY abs,X
+	0x9D: 5, // STA abs,X
+	0x9E: 5, // SHX abs,Y
+	0x9F: 5, // SHA abs,Y
+	0xA0: 2, // LDY #
+	0xA1: 6, // LDA (zp,X)
+	0xA2: 2, // LDX #
+	0xA3: 6, // LAX (zp,X)
+	0xA4: 3, // LDY zp
+	0xA5: 3, // LDA zp
+	0xA6: 3, // LDX zp
+	0xA7: 3, // LAX zp
+	0xA8: 2, // TAY
+	0xA9: 2, // LDA #
+	0xAA: 2, // TAX
+	0xAB: 2, // LXA #
+	0xAC: 4, // LDY abs
+	0xAD: 4, // LDA abs
+	0xAE: 4, // LDX abs
+	0xAF: 4, // LAX abs
+	0xB0: 2, // BCS
+	0xB1: 5, // LDA (zp),Y
+	0xB3: 5, // LAX (zp),Y
+	0xB4: 4, // LDY zp,X
+	0xB5: 4, // LDA zp,X
+	0xB6: 4, // LDX zp,Y
+	0xB7: 4, // LAX zp,Y
+	0xB8: 2, // CLV
+	0xB9: 4, // LDA abs,Y
+	0xBA: 2, // TSX
+	0xBB: 4, // LAS abs,Y
+	0xBC: 4, // LDY abs,X
+	0xBD: 4, // LDA abs,X
+	0xBE: 4, // LDX abs,Y
+	0xBF: 4, // LAX abs,Y
+	0xC0: 2, // CPY #
+	0xC1: 6, // CMP (zp,X)
+	0xC2: 2, // NOP #
+	0xC3: 8, // DCP (zp,X)
+	0xC4: 3, // CPY zp
+	0xC5: 3, // CMP zp
+	0xC6: 5, // DEC zp
+	0xC7: 5, // DCP zp
+	0xC8: 2, // INY
+	0xC9: 2, // CMP #
+	0xCA: 2, // DEX
+	0xCB: 2, // SBX #
+	0xCC: 4, // CPY abs
+	0xCD: 4, // CMP abs
+	0xCE: 6, // DEC abs
+	0xCF: 6, // DCP abs
+	0xD0: 2, // BNE
+	0xD1: 5, // CMP (zp),Y
+	0xD3: 8, // DCP (zp),Y
+	0xD4: 4, // NOP zp,X
+	0xD5: 4, // CMP zp,X
+	0xD6: 6, // DEC zp,X
+	0xD7: 6, // DCP zp,X
+	0xD8: 2, // CLD
+	0xD9: 4, // CMP abs,Y
+	0xDA: 2, // NOP
+	0xDB: 7, // DCP abs,Y
+	0xDC: 4, // NOP abs,X
+	0xDD: 4, // CMP abs,X
+	0xDE: 7, // DEC abs,X
+	0xDF: 7, // DCP abs,X
+	0xE0: 2, // CPX #
+	0xE1: 6, // SBC (zp,X)
+	0xE2: 2, // NOP #
+	0xE3: 8, // ISC (zp,X)
+	0xE4: 3, // CPX zp
+	0xE5: 3, // SBC zp
+	0xE6: 5, // INC zp
+	0xE7: 5, // ISC zp
+	0xE8: 2, // INX
+	0xE9: 2, // SBC #
+	0xEA: 2, // NOP
+	0xEB: 2, // SBC #
+	0xEC: 4, // CPX abs
+	0xED: 4, // SBC abs
+	0xEE: 6, // INC abs
+	0xEF: 6, // ISC abs
+	0xF0: 2, // BEQ
+	0xF1: 5, // SBC (zp),Y
+	0xF3: 8, // ISC (zp),Y
+	0xF4: 4, // NOP zp,X
+	0xF5: 4, // SBC zp,X
+	0xF6: 6, // INC zp,X
+	0xF7: 6, // ISC zp,X
+	0xF8: 2, // SED
+	0xF9: 4, // SBC abs,Y
+	0xFA: 2, // NOP
+	0xFB: 7, // ISC abs,Y
+	0xFC: 4, // NOP abs,X
+	0xFD: 4, // SBC abs,X
+	0xFE: 7, // INC abs,X
+	0xFF: 7, // ISC abs,X
+}
+
+// pageCrossed - returns true if the two addresses are located on different
+// pages (the high bytes differ).
+func pageCrossed(a, b uint16) bool {
+	return a&0xFF00 != b&0xFF00
+}
@@ -0,0 +1,158 @@
+package cpu6510
+
+import "testing"
+
+// Base cycle counts for all opcodes, indexed by opcode. Zero marks the JAM
+// opcodes, which halt the CPU.
+var expectedCycles = [256]uint64{
+	//0 1  2  3  4  5  6  7  8  9  A  B  C  D  E  F
+	7, 6, 0, 8, 3, 3, 5, 5, 3, 2, 2, 2, 4, 4, 6, 6, // 0
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // 1
+	6, 6, 0, 8, 3, 3, 5, 5, 4, 2, 2, 2, 4, 4, 6, 6, // 2
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // 3
+	6, 6, 0, 8, 3, 3, 5, 5, 3, 2, 2, 2, 3, 4, 6, 6, // 4
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // 5
+	6, 6, 0, 8, 3, 3, 5, 5, 4, 2, 2, 2, 5, 4, 6, 6, // 6
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // 7
+	2, 6, 2, 6, 3, 3, 3, 3, 2, 2, 2, 2, 4, 4, 4, 4, // 8
+	2, 6, 0, 6, 4, 4, 4, 4, 2, 5, 2, 5, 5, 5, 5, 5, // 9
+	2, 6, 2, 6, 3, 3, 3, 3, 2, 2, 2, 2, 4, 4, 4, 4, // A
+	2, 5, 0, 5, 4, 4, 4, 4, 2, 4, 2, 4, 4, 4, 4, 4, // B
+	2, 6, 2, 8, 3, 3, 5, 5, 2, 2, 2, 2, 4, 4, 6, 6, // C
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // D
+	2, 6, 2, 8, 3, 3, 5, 5, 2, 2, 2, 2, 4, 4, 6, 6, // E
+	2, 5, 0, 8, 4, 4, 6, 6, 2, 4, 2, 7, 4, 4, 7, 7, // F
+}
+
+// isBranch - returns true for the relative branch opcodes ($10, $30, ... $F0).
+func isBranch(opcode byte) bool {
+	return opcode&0x1F == 0x10
+}
+
+func TestInstructionCycles(t *testing.T) {
+	for opcode, expected := range expectedCycles {
+		if expected == 0 {
+			continue
+		}
+
+		if _, ok := lookupInstruction[byte(opcode)]; !ok {
+			t.Errorf("Opcode 0x%02X is not implemented", opcode)
+			continue
+		}
+
+		cpu := NewCPU()
+		cpu.programCounter = 0x0200
+		if isBranch(byte(opcode)) {
+			// Bit 5 of the opcode selects whether the branch is taken on
+			// a set or a cleared flag, make sure that it is not taken.
+			cpu.statusRegister = newStatusRegister(0b00000000)
+			if opcode&0x20 == 0 {
+				cpu.statusRegister = newStatusRegister(0b11000011)
+			}
+		}
+
+		cpu.execute(byte(opcode))
+
+		if cpu.Cycles() != expected {
+			t.Errorf("Opcode 0x%02X should take %d cycles, got %d", opcode, expected, cpu.Cycles())
+		}
+	}
+}
+
+func TestCyclesAccumulate(t *testing.T) {
+	cpu := NewCPU()
+
+	// LDA #$01, STA $1337, INC $1337, BRK
+	copy(cpu.ram[:], []byte{0xA9, 0x01, 0x8D, 0x37, 0x13, 0xEE, 0x37, 0x13, 0x00})
+
+	cpu.Run()
+
+	if cpu.Cycles() != 2+4+6+7 {
+		t.Errorf("Cycles should be %d, got %d", 2+4+6+7, cpu.Cycles())
+	}
+}
+
+func TestPageCrossingCycles(t *testing.T) {
+	tests := []struct {
+		instruction string
+		index       byte
+		expected    uint64
+	}{
+		{"LDAAbsoluteX", 0x01, 4},
+		{"LDAAbsoluteX", 0x02, 5},
+		{"LDAAbsoluteY", 0x01, 4},
+		{"LDAAbsoluteY", 0x02, 5},
+		{"LDXAbsoluteY", 0x02, 5},
+		{"LDYAbsoluteX", 0x02, 5},
+		{"ORAAbsoluteX", 0x02, 5},
+		{"ANDAbsoluteY", 0x02, 5},
+		{"EORAbsoluteX", 0x02, 5},
+		{"ADCAbsoluteY", 0x02, 5},
+		{"SBCAbsoluteX", 0x02, 5},
+		{"CMPAbsoluteY", 0x02, 5},
+		{"LDAIndirectIndexed", 0x01, 5},
+		{"LDAIndirectIndexed", 0x02, 6},
+		{"CMPIndirectIndexed", 0x02, 6},
+		{"LAXAbsoluteY", 0x02, 5},
+		{"LAXIndirectIndexed", 0x02, 6},
+		{"LASAbsoluteY", 0x02, 5},
+		{"NOPAbsoluteX", 0x01, 4},
+		{"NOPAbsoluteX", 0x02, 5},
+		// Stores and read-modify-write instructions always take the extra
+		// cycle, so it is already part of their base cycle count.
+		{"STAAbsoluteX", 0x02, 5},
+		{"STAAbsoluteY", 0x02, 5},
+		{"STAIndirectIndexed", 0x02, 6},
+		{"ASLAbsoluteX", 0x02, 7},
+		{"INCAbsoluteX", 0x02, 7},
+		{"SLOAbsoluteY", 0x02, 7},
+		{"DCPIndirectIndexed", 0x02, 8},
+		{"SHAAbsoluteY", 0x02, 5},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		cpu.xRegister = test.index
+		cpu.yRegister = test.index
+		// The absolute address and the zero page pointer are both $12FE.
+		cpu.ram[1] = 0xFE
+		cpu.ram[2] = 0x12
+		cpu.ram[0xFE] = 0xFE
+		cpu.ram[0xFF] = 0x12
+
+		cpu.execute(InstructionAsHex(test.instruction))
+
+		if cpu.Cycles() != test.expected {
+			t.Errorf("%s with index 0x%02X should take %d cycles, got %d", test.instruction, test.index, test.expected, cpu.Cycles())
+		}
+	}
+}
+
+func TestBranchCycles(t *testing.T) {
+	tests := []struct {
+		name           string
+		programCounter uint16
+		zeroFlag       bool
+		offset         byte
+		expected       uint64
+	}{
+		{"Branch not taken", 0xC000, false, 0x10, 2},
+		{"Branch taken", 0xC000, true, 0x10, 3},
+		{"Branch taken backwards", 0xC010, true, 0xF0, 3},
+		{"Branch taken to the next page", 0xC0F0, true, 0x10, 4},
+		{"Branch taken to the previous page", 0xC000, true, 0xF0, 4},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		cpu.programCounter = test.programCounter
+		cpu.statusRegister.zeroFlag = test.zeroFlag
+		cpu.ram[test.programCounter+1] = test.offset
+
+		cpu.execute(InstructionAsHex("BEQ"))
+
+		if cpu.Cycles() != test.expected {
+			t.Errorf("%s should take %d cycles, got %d", test.name, test.expected, cpu.Cycles())
+		}
+	}
+}
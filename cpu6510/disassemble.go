@@ -0,0 +1,380 @@
+package cpu6510
+
+import "fmt"
+
+// operandMode is the addressing mode of an opcode, as far as the
+// disassembler is concerned.
+type operandMode int
+
+const (
+	impliedMode operandMode = iota
+	accumulatorMode
+	immediateMode
+	zeroPageMode
+	zeroPageXMode
+	zeroPageYMode
+	absoluteMode
+	absoluteXMode
+	absoluteYMode
+	indirectMode
+	indexedIndirectMode
+	indirectIndexedMode
+	relativeMode
+)
+
+// operandLength returns the number of bytes of the operand in the mode.
+func (m operandMode) operandLength() int {
+	switch m {
+	case impliedMode, accumulatorMode:
+		return 0
+	case absoluteMode, absoluteXMode, absoluteYMode, indirectMode:
+		return 2
+	}
+
+	return 1
+}
+
+// opcode describes an opcode for the disassembler.
+type opcode struct {
+	mnemonic string
+	mode     operandMode
+	// True for the opcodes that are not documented by MOS.
+	undocumented bool
+}
+
+// The opcodes of the NMOS 6510, indexed by opcode.
+var opcodes = [256]opcode{
+	0x00: {"BRK", impliedMode, false},
+	0x01: {"ORA", indexedIndirectMode, false},
+	0x02: {"JAM", impliedMode, true},
+	0x03: {"SLO", indexedIndirectMode, true},
+	0x04: {"NOP", zeroPageMode, true},
+	0x05: {"ORA", zeroPageMode, false},
+	0x06: {"ASL", zeroPageMode, false},
+	0x07: {"SLO", zeroPageMode, true},
+	0x08: {"PHP", impliedMode, false},
+	0x09: {"ORA", immediateMode, false},
+	0x0A: {"ASL", accumulatorMode, false},
+	0x0B: {"ANC", immediateMode, true},
+	0x0C: {"NOP", absoluteMode, true},
+	0x0D: {"ORA", absoluteMode, false},
+	0x0E: {"ASL", absoluteMode, false},
+	0x0F: {"SLO", absoluteMode, true},
+	0x10: {"BPL", relativeMode, false},
+	0x11: {"ORA", indirectIndexedMode, false},
+	0x12: {"JAM", impliedMode, true},
+	0x13: {"SLO", indirectIndexedMode, true},
+	0x14: {"NOP", zeroPageXMode, true},
+	0x15: {"ORA", zeroPageXMode, false},
+	0x16: {"ASL", zeroPageXMode, false},
+	0x17: {"SLO", zeroPageXMode, true},
+	0x18: {"CLC", impliedMode, false},
+	0x19: {"ORA", absoluteYMode, false},
+	0x1A: {"NOP", impliedMode, true},
+	0x1B: {"SLO", absoluteYMode, true},
+	0x1C: {"NOP", absoluteXMode, true},
+	0x1D: {"ORA", absoluteXMode, false},
+	0x1E: {"ASL", absoluteXMode, false},
+	0x1F: {"SLO", absoluteXMode, true},
+	0x20: {"JSR", absoluteMode, false},
+	0x21: {"AND", indexedIndirectMode, false},
+	0x22: {"JAM", impliedMode, true},
+	0x23: {"RLA", indexedIndirectMode, true},
+	0x24: {"BIT", zeroPageMode, false},
+	0x25: {"AND", zeroPageMode, false},
+	0x26: {"ROL", zeroPageMode, false},
+	0x27: {"RLA", zeroPageMode, true},
+	0x28: {"PLP", impliedMode, false},
+	0x29: {"AND", immediateMode, false},
+	0x2A: {"ROL", accumulatorMode, false},
+	0x2B: {"ANC", immediateMode, true},
+	0x2C: {"BIT", absoluteMode, false},
+	0x2D: {"AND", absoluteMode, false},
+	0x2E: {"ROL", absoluteMode, false},
+	0x2F: {"RLA", absoluteMode, true},
+	0x30: {"BMI", relativeMode, false},
+	0x31: {"AND", indirectIndexedMode, false},
+	0x32: {"JAM", impliedMode, true},
+	0x33: {"RLA", indirectIndexedMode, true},
+	0x34: {"NOP", zeroPageXMode, true},
+	0x35: {"AND", zeroPageXMode, false},
+	0x36: {"ROL", zeroPageXMode, false},
+	0x37: {"RLA", zeroPageXMode, true},
+	0x38: {"SEC", impliedMode, false},
+	0x39: {"AND", absoluteYMode, false},
+	0x3A: {"NOP", impliedMode, true},
+	0x3B: {"RLA", absoluteYMode, true},
+	0x3C: {"NOP", absoluteXMode, true},
+	0x3D: {"AND", absoluteXMode, false},
+	0x3E: {"ROL", absoluteXMode, false},
+	0x3F: {"RLA", absoluteXMode, true},
+	0x40: {"RTI", impliedMode, false},
+	0x41: {"EOR", indexedIndirectMode, false},
+	0x42: {"JAM", impliedMode, true},
+	0x43: {"SRE", indexedIndirectMode, true},
+	0x44: {"NOP", zeroPageMode, true},
+	0x45: {"EOR", zeroPageMode, false},
+	0x46: {"LSR", zeroPageMode, false},
+	0x47: {"SRE", zeroPageMode, true},
+	0x48: {"PHA", impliedMode, false},
+	0x49: {"EOR", immediateMode, false},
+	0x4A: {"LSR", accumulatorMode, false},
+	0x4B: {"ALR", immediateMode, true},
+	0x4C: {"JMP", absoluteMode, false},
+	0x4D: {"EOR", absoluteMode, false},
+	0x4E: {"LSR", absoluteMode, false},
+	0x4F: {"SRE", absoluteMode, true},
+	0x50: {"BVC", relativeMode, false},
+	0x51: {"EOR", indirectIndexedMode, false},
+	0x52: {"JAM", impliedMode, true},
+	0x53: {"SRE", indirectIndexedMode, true},
+	0x54: {"NOP", zeroPageXMode, true},
+	0x55: {"EOR", zeroPageXMode, false},
+	0x56: {"LSR", zeroPageXMode, false},
+	0x57: {"SRE", zeroPageXMode, true},
+	0x58: {"CLI", impliedMode, false},
+	0x59: {"EOR", absoluteYMode, false},
+	0x5A: {"NOP", impliedMode, true},
+	0x5B: {"SRE", absoluteYMode, true},
+	0x5C: {"NOP", absoluteXMode, true},
+	0x5D: {"EOR", absoluteXMode, false},
+	0x5E: {"LSR", absoluteXMode, false},
+	0x5F: {"SRE", absoluteXMode, true},
+	0x60: {"RTS", impliedMode, false},
+	0x61: {"ADC", indexedIndirectMode, false},
+	0x62: {"JAM", impliedMode, true},
+	0x63: {"RRA", indexedIndirectMode, true},
+	0x64: {"NOP", zeroPageMode, true},
+	0x65: {"ADC", zeroPageMode, false},
+	0x66: {"ROR", zeroPageMode, false},
+	0x67: {"RRA", zeroPageMode, true},
+	0x68: {"PLA", impliedMode, false},
+	0x69: {"ADC", immediateMode, false},
+	0x6A: {"ROR", accumulatorMode, false},
+	0x6B: {"ARR", immediateMode, true},
+	0x6C: {"JMP", indirectMode, false},
+	0x6D: {"ADC", absoluteMode, false},
+	0x6E: {"ROR", absoluteMode, false},
+	0x6F: {"RRA", absoluteMode, true},
+	0x70: {"BVS", relativeMode, false},
+	0x71: {"ADC", indirectIndexedMode, false},
+	0x72: {"JAM", impliedMode, true},
+	0x73: {"RRA", indirectIndexedMode, true},
+	0x74: {"NOP", zeroPageXMode, true},
+	0x75: {"ADC", zeroPageXMode, false},
+	0x76: {"ROR", zeroPageXMode, false},
+	0x77: {"RRA", zeroPageXMode, true},
+	0x78: {"SEI", impliedMode, false},
+	0x79: {"ADC", absoluteYMode, false},
+	0x7A: {"NOP", impliedMode, true},
+	0x7B: {"RRA", absoluteYMode, true},
+	0x7C: {"NOP", absoluteXMode, true},
+	0x7D: {"ADC", absoluteXMode, false},
+	0x7E: {"ROR", absoluteXMode, false},
+	0x7F: {"RRA", absoluteXMode, true},
+	0x80: {"NOP", immediateMode, true},
+	0x81: {"STA", indexedIndirectMode, false},
+	0x82: {"NOP", immediateMode, true},
+	0x83: {"SAX", indexedIndirectMode, true},
+	0x84: {"STY", zeroPageMode, false},
+	0x85: {"STA", zeroPageMode, false},
+	0x86: {"STX", zeroPageMode, false},
+	0x87: {"SAX", zeroPageMode, true},
+	0x88: {"DEY", impliedMode, false},
+	0x89: {"NOP", immediateMode, true},
+	0x8A: {"TXA", impliedMode, false},
+	0x8B: {"ANE", immediateMode, true},
+	0x8C: {"STY", absoluteMode, false},
+	0x8D: {"STA", absoluteMode, false},
+	0x8E: {"STX", absoluteMode, false},
+	0x8F: {"SAX", absoluteMode, true},
+	0x90: {"BCC", relativeMode, false},
+	0x91: {"STA", indirectIndexedMode, false},
+	0x92: {"JAM", impliedMode, true},
+	0x93: {"SHA", indirectIndexedMode, true},
+	0x94: {"STY", zeroPageXMode, false},
+	0x95: {"STA", zeroPageXMode, false},
+	0x96: {"STX", zeroPageYMode, false},
+	0x97: {"SAX", zeroPageYMode, true},
+	0x98: {"TYA", impliedMode, false},
+	0x99: {"STA", absoluteYMode, false},
+	0x9A: {"TXS", impliedMode, false},
+	0x9B: {"TAS", absoluteYMode, true},
+	0x9C: {"SHY", absoluteXMode, true},
+	0x9D: {"STA", absoluteXMode, false},
+	0x9E: {"SHX", absoluteYMode, true},
+	0x9F: {"SHA", absoluteYMode, true},
+	0xA0: {"LDY", immediateMode, false},
+	0xA1: {"LDA", indexedIndirectMode, false},
+	0xA2: {"LDX", immediateMode, false},
+	0xA3: {"LAX", indexedIndirectMode, true},
+	0xA4: {"LDY", zeroPageMode, false},
+	0xA5: {"LDA", zeroPageMode, false},
+	0xA6: {"LDX", zeroPageMode, false},
+	0xA7: {"LAX", zeroPageMode, true},
+	0xA8: {"TAY", impliedMode, false},
+	0xA9: {"LDA", immediateMode, false},
+	0xAA: {"TAX", impliedMode, false},
+	0xAB: {"LXA", immediateMode, true},
+	0xAC: {"LDY", absoluteMode, false},
+	0xAD: {"LDA", absoluteMode, false},
+	0xAE: {"LDX", absoluteMode, false},
+	0xAF: {"LAX", absoluteMode, true},
+	0xB0: {"BCS", relativeMode, false},
+	0xB1: {"LDA", indirectIndexedMode, false},
+	0xB2: {"JAM", impliedMode, true},
+	0xB3: {"LAX", indirectIndexedMode, true},
+	0xB4: {"LDY", zeroPageXMode, false},
+	0xB5: {"LDA", zeroPageXMode, false},
+	0xB6: {"LDX", zeroPageYMode, false},
+	0xB7: {"LAX", zeroPageYMode, true},
+	0xB8: {"CLV", impliedMode, false},
+	0xB9: {"LDA", absoluteYMode, false},
+	0xBA: {"TSX", impliedMode, false},
+	0xBB: {"LAS", absoluteYMode, true},
+	0xBC: {"LDY", absoluteXMode, false},
+	0xBD: {"LDA", absoluteXMode, false},
+	0xBE: {"LDX", absoluteYMode, false},
+	0xBF: {"LAX", absoluteYMode, true},
+	0xC0: {"CPY", immediateMode, false},
+	0xC1: {"CMP", indexedIndirectMode, false},
+	0xC2: {"NOP", immediateMode, true},
+	0xC3: {"DCP", indexedIndirectMode, true},
+	0xC4: {"CPY", zeroPageMode, false},
+	0xC5: {"CMP", zeroPageMode, false},
+	0xC6: {"DEC", zeroPageMode, false},
+	0xC7: {"DCP", zeroPageMode, true},
+	0xC8: {"INY", impliedMode, false},
+	0xC9: {"CMP", immediateMode, false},
+	0xCA: {"DEX", impliedMode, false},
+	0xCB: {"SBX", immediateMode, true},
+	0xCC: {"CPY", absoluteMode, false},
+	0xCD: {"CMP", absoluteMode, false},
+	0xCE: {"DEC", absoluteMode, false},
+	0xCF: {"DCP", absoluteMode, true},
+	0xD0: {"BNE", relativeMode, false},
+	0xD1: {"CMP", indirectIndexedMode, false},
+	0xD2: {"JAM", impliedMode, true},
+	0xD3: {"DCP", indirectIndexedMode, true},
+	0xD4: {"NOP", zeroPageXMode, true},
+	0xD5: {"CMP", zeroPageXMode, false},
+	0xD6: {"DEC", zeroPageXMode, false},
+	0xD7: {"DCP", zeroPageXMode, true},
+	0xD8: {"CLD", impliedMode, false},
+	0xD9: {"CMP", absoluteYMode, false},
+	0xDA: {"NOP", impliedMode, true},
+	0xDB: {"DCP", absoluteYMode, true},
+	0xDC: {"NOP", absoluteXMode, true},
+	0xDD: {"CMP", absoluteXMode, false},
+	0xDE: {"DEC", absoluteXMode, false},
+	0xDF: {"DCP", absoluteXMode, true},
+	0xE0: {"CPX", immediateMode, false},
+	0xE1: {"SBC", indexedIndirectMode, false},
+	0xE2: {"NOP", immediateMode, true},
+	0xE3: {"ISC", indexedIndirectMode, true},
+	0xE4: {"CPX", zeroPageMode, false},
+	0xE5: {"SBC", zeroPageMode, false},
+	0xE6: {"INC", zeroPageMode, false},
+	0xE7: {"ISC", zeroPageMode, true},
+	0xE8: {"INX", impliedMode, false},
+	0xE9: {"SBC", immediateMode, false},
+	0xEA: {"NOP", impliedMode, false},
+	0xEB: {"SBC", immediateMode, true},
+	0xEC: {"CPX", absoluteMode, false},
+	0xED: {"SBC", absoluteMode, false},
+	0xEE: {"INC", absoluteMode, false},
+	0xEF: {"ISC", absoluteMode, true},
+	0xF0: {"BEQ", relativeMode, false},
+	0xF1: {"SBC", indirectIndexedMode, false},
+	0xF2: {"JAM", impliedMode, true},
+	0xF3: {"ISC", indirectIndexedMode, true},
+	0xF4: {"NOP", zeroPageXMode, true},
+	0xF5: {"SBC", zeroPageXMode, false},
+	0xF6: {"INC", zeroPageXMode, false},
+	0xF7: {"ISC", zeroPageXMode, true},
+	0xF8: {"SED", impliedMode, false},
+	0xF9: {"SBC", absoluteYMode, false},
+	0xFA: {"NOP", impliedMode, true},
+	0xFB: {"ISC", absoluteYMode, true},
+	0xFC: {"NOP", absoluteXMode, true},
+	0xFD: {"SBC", absoluteXMode, false},
+	0xFE: {"INC", absoluteXMode, false},
+	0xFF: {"ISC", absoluteXMode, true},
+}
+
+// Disassembly is a single disassembled instruction.
+type Disassembly struct {
+	// The address of the opcode.
+	Address uint16
+	// The opcode followed by its operand.
+	Bytes []byte
+	// The mnemonic followed by the operand, like LDA ($20),Y. Branches show
+	// the address they branch to.
+	Text string
+	// True when the opcode is not documented by MOS.
+	Undocumented bool
+}
+
+// InstructionLength returns the number of bytes of the instruction with the
+// given opcode, including the opcode itself.
+func InstructionLength(opcode byte) int {
+	return 1 + opcodes[opcode].mode.operandLength()
+}
+
+// Disassemble disassembles the instruction at the address, reading its bytes
+// with the read function.
+func Disassemble(read func(address uint16) byte, address uint16) Disassembly {
+	value := read(address)
+	op := opcodes[value]
+
+	code := make([]byte, InstructionLength(value))
+	code[0] = value
+	for i := 1; i < len(code); i++ {
+		code[i] = read(address + uint16(i))
+	}
+
+	var word uint16
+	if len(code) == 3 {
+		word = ConvertTwoBytesToAddress(code[2], code[1])
+	}
+
+	var operand string
+	switch op.mode {
+	case accumulatorMode:
+		operand = "A"
+	case immediateMode:
+		operand = fmt.Sprintf("#$%02X", code[1])
+	case zeroPageMode:
+		operand = fmt.Sprintf("$%02X", code[1])
+	case zeroPageXMode:
+		operand = fmt.Sprintf("$%02X,X", code[1])
+	case zeroPageYMode:
+		operand = fmt.Sprintf("$%02X,Y", code[1])
+	case absoluteMode:
+		operand = fmt.Sprintf("$%04X", word)
+	case absoluteXMode:
+		operand = fmt.Sprintf("$%04X,X", word)
+	case absoluteYMode:
+		operand = fmt.Sprintf("$%04X,Y", word)
+	case indirectMode:
+		operand = fmt.Sprintf("($%04X)", word)
+	case indexedIndirectMode:
+		operand = fmt.Sprintf("($%02X,X)", code[1])
+	case indirectIndexedMode:
+		operand = fmt.Sprintf("($%02X),Y", code[1])
+	case relativeMode:
+		operand = fmt.Sprintf("$%04X", address+2+uint16(int8(code[1])))
+	}
+
+	text := op.mnemonic
+	if operand != "" {
+		text += " " + operand
+	}
+
+	return Disassembly{
+		Address:      address,
+		Bytes:        code,
+		Text:         text,
+		Undocumented: op.undocumented,
+	}
+}
@@ -0,0 +1,87 @@
+package cpu6510
+
+import "testing"
+
+func TestDisassemble(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     []byte
+		expected string
+	}{
+		{"Implied", []byte{0xE8}, "INX"},
+		{"Accumulator", []byte{0x0A}, "ASL A"},
+		{"Immediate", []byte{0xA9, 0x10}, "LDA #$10"},
+		{"Zero page", []byte{0xA5, 0x44}, "LDA $44"},
+		{"Zero page X", []byte{0xB5, 0x44}, "LDA $44,X"},
+		{"Zero page Y", []byte{0xB6, 0x44}, "LDX $44,Y"},
+		{"Absolute", []byte{0x8D, 0x78, 0x56}, "STA $5678"},
+		{"Absolute X", []byte{0x9D, 0x78, 0x56}, "STA $5678,X"},
+		{"Absolute Y", []byte{0x99, 0x78, 0x56}, "STA $5678,Y"},
+		{"Indirect", []byte{0x6C, 0xFC, 0xFF}, "JMP ($FFFC)"},
+		{"Indexed indirect", []byte{0x01, 0x20}, "ORA ($20,X)"},
+		{"Indirect indexed", []byte{0x11, 0x20}, "ORA ($20),Y"},
+		{"Branch forward", []byte{0xD0, 0x05}, "BNE $C007"},
+		{"Branch backward", []byte{0x10, 0xFE}, "BPL $C000"},
+		{"Undocumented", []byte{0xA7, 0x12}, "LAX $12"},
+		{"JAM", []byte{0x02}, "JAM"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memory := map[uint16]byte{}
+			for i, value := range test.code {
+				memory[0xC000+uint16(i)] = value
+			}
+			read := func(address uint16) byte { return memory[address] }
+
+			disassembly := Disassemble(read, 0xC000)
+
+			if disassembly.Text != test.expected {
+				t.Errorf("Disassembly should be %q, got %q", test.expected, disassembly.Text)
+			}
+
+			if len(disassembly.Bytes) != len(test.code) {
+				t.Errorf("Instruction should be %d bytes, got %d", len(test.code), len(disassembly.Bytes))
+			}
+		})
+	}
+}
+
+func TestDisassembleUndocumented(t *testing.T) {
+	documented := 0
+	for _, op := range opcodes {
+		if !op.undocumented {
+			documented++
+		}
+	}
+
+	if documented != 151 {
+		t.Errorf("There should be 151 documented opcodes, got %d", documented)
+	}
+
+	for _, opcode := range []byte{0x02, 0x1A, 0x80, 0xA7, 0xEB} {
+		if !opcodes[opcode].undocumented {
+			t.Errorf("Opcode 0x%02X should be undocumented", opcode)
+		}
+	}
+}
+
+func TestInstructionLength(t *testing.T) {
+	for opcode := 0; opcode < 256; opcode++ {
+		cpu := NewCPU()
+		cpu.execute(byte(opcode))
+
+		if isJAMOpcode(byte(opcode)) || opcodes[opcode].mode == relativeMode {
+			continue
+		}
+
+		switch opcodes[opcode].mnemonic {
+		case "BRK", "JSR", "RTI", "RTS", "JMP":
+			continue
+		}
+
+		if int(cpu.programCounter) != InstructionLength(byte(opcode)) {
+			t.Errorf("Opcode 0x%02X should be %d bytes, the CPU moved %d", opcode, InstructionLength(byte(opcode)), cpu.programCounter)
+		}
+	}
+}
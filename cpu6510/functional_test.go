@@ -0,0 +1,112 @@
+package cpu6510
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The functional test of Klaus Dormann, assembled with the default options,
+// is a 64KB image that starts at $0400 and traps at $3469 when every test
+// has passed. The number of the current test is kept at $0200.
+const (
+	functionalTestFile    = "6502_functional_test.bin"
+	functionalTestStart   = 0x0400
+	functionalTestSuccess = 0x3469
+	functionalTestCase    = 0x0200
+)
+
+// The decimal test, which checks ADC and SBC in decimal mode for every pair of
+// operands, is loaded and started at $0200. It leaves 0 in ERROR at $000B when
+// every result is correct.
+const (
+	decimalTestFile  = "6502_decimal_test.bin"
+	decimalTestStart = 0x0200
+	decimalTestError = 0x000B
+)
+
+// The STP opcode of the 65C02, which the test programs can end with.
+const stpOpcode = 0xDB
+
+// The number of cycles the test programs get before they are considered
+// stuck. The functional test needs about 100 million.
+const testProgramCycles = 500_000_000
+
+// loadTestProgram loads the test program from the testdata directory at the
+// address and sets the program counter to start. The test is skipped with
+// -short, or when the program is missing.
+func loadTestProgram(t *testing.T, name string, address uint16, start uint16) *CPU {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping test program in short mode")
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if os.IsNotExist(err) {
+		t.Skipf("testdata/%s not found, see testdata/README.md", name)
+	}
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	cpu := NewCPU()
+	if _, err := cpu.LoadAt(address, data); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	cpu.programCounter = start
+
+	return cpu
+}
+
+// runUntilTrap runs the CPU until it is trapped in an instruction that jumps
+// or branches to itself, which is how the test programs report both success
+// and failure, or until it is about to execute the stop opcode of the 65C02,
+// $DB. It returns the
+// address the CPU stopped at.
+func runUntilTrap(cpu *CPU) (uint16, error) {
+	for cpu.cycles < testProgramCycles {
+		pc := cpu.programCounter
+		if cpu.ram[pc] == stpOpcode {
+			return pc, nil
+		}
+
+		if _, err := cpu.Step(); err != nil {
+			return pc, err
+		}
+
+		if cpu.programCounter == pc {
+			return pc, nil
+		}
+	}
+
+	return cpu.programCounter, fmt.Errorf("no trap after %d cycles", cpu.cycles)
+}
+
+func TestFunctionalTest(t *testing.T) {
+	cpu := loadTestProgram(t, functionalTestFile, 0x0000, functionalTestStart)
+
+	pc, err := runUntilTrap(cpu)
+	if err != nil {
+		t.Fatalf("Functional test should trap, got %v at $%04X in test $%02X", err, pc, cpu.ram[functionalTestCase])
+	}
+
+	if pc != functionalTestSuccess {
+		t.Errorf("Functional test should trap at $%04X, got $%04X in test $%02X", functionalTestSuccess, pc, cpu.ram[functionalTestCase])
+	}
+}
+
+func TestDecimalTest(t *testing.T) {
+	cpu := loadTestProgram(t, decimalTestFile, decimalTestStart, decimalTestStart)
+
+	// The decimal test ends with STP, unless it is assembled to trap instead.
+	pc, err := runUntilTrap(cpu)
+	if err != nil {
+		t.Fatalf("Decimal test should finish, got %v at $%04X", err, pc)
+	}
+
+	if cpu.ram[decimalTestError] != 0 {
+		t.Errorf("Decimal test should pass, got ERROR = %d at $%04X with N1 = $%02X and N2 = $%02X", cpu.ram[decimalTestError], pc, cpu.ram[0x0000], cpu.ram[0x0001])
+	}
+}
@@ -1,25 +1,756 @@
 package cpu6510
 
+// The undocumented opcodes of the NMOS 6510. Most of them combine two
+// documented instructions, and are used by demos and loaders, since they are
+// stable on all C64s. ANE and LXA mix in a "magic" constant that differs
+// between chips, and SHA, SHX, SHY and TAS store a value ANDed with the high
+// byte of the address, which also corrupts the address when a page is
+// crossed.
+
+// The magic constant used by ANE and LXA, unless configured otherwise.
+const defaultMagicConstant byte = 0xEE
+
 // JAM - JaM/KIL. Illegal opcode that halts the CPU.
 func JAM(c *CPU) {
 	c.isJammed = true
 }
 
-// SLOIndexedIndirect - Shift Left then ORA with accumulator.
-func SLOIndexedIndirect(c *CPU) {
+// readModifyWrite reads the memory location specified by the address, writes
+// it back unmodified while it modifies it, and then writes the modified value
+// which is returned.
+func readModifyWrite(c *CPU, getAddress func() uint16, modify func(value byte) byte) byte {
 	c.programCounter++
 
-	address := c.addressIndexedIndirect()
+	address := getAddress()
 
 	value := c.readMemory(address)
 
-	c.statusRegister.carryFlag = setCarryFlag(value)
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
 
-	value <<= 1
+	value = modify(value)
 
 	c.writeMemory(address, value)
 
+	return value
+}
+
+// SLO - Shift Left then ORA.
+func slo(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		c.statusRegister.carryFlag = setCarryFlag(value)
+
+		return value << 1
+	})
+
 	c.accumulator |= value
 
 	raiseStatusRegisterFlags(c, c.accumulator)
 }
+
+// RLA - Rotate Left then AND.
+func rla(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		carry := c.statusRegister.carryFlag
+
+		c.statusRegister.carryFlag = value&0x80 == 0x80
+
+		value <<= 1
+
+		if carry {
+			value |= 0x01
+		}
+
+		return value
+	})
+
+	c.accumulator &= value
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// SRE - Shift Right then EOR.
+func sre(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		c.statusRegister.carryFlag = value&0x01 == 0x01
+
+		return value >> 1
+	})
+
+	c.accumulator ^= value
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// RRA - Rotate Right then ADC.
+func rra(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		carry := c.statusRegister.carryFlag
+
+		c.statusRegister.carryFlag = value&0x01 == 0x01
+
+		value >>= 1
+
+		if carry {
+			value |= 0x80
+		}
+
+		return value
+	})
+
+	addWithCarry(c, value)
+}
+
+// DCP - DeCrement then comPare.
+func dcp(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		return value - 1
+	})
+
+	raiseStatusRegisterFlags(c, c.accumulator-value)
+
+	c.statusRegister.carryFlag = c.accumulator >= value
+}
+
+// ISC - Increment then Subtract with Carry.
+func isc(c *CPU, getAddress func() uint16) {
+	value := readModifyWrite(c, getAddress, func(value byte) byte {
+		return value + 1
+	})
+
+	subtractWithCarry(c, value)
+}
+
+// SAX - Store A AND X. No flags are affected.
+func sax(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	c.writeMemory(address, c.accumulator&c.xRegister)
+}
+
+// LAX - Load A and X.
+func lax(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	value := getValue()
+
+	c.accumulator = value
+	c.xRegister = value
+
+	raiseStatusRegisterFlags(c, value)
+}
+
+// NOP - No OPeration, which reads a value and throws it away.
+func nop(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	getValue()
+}
+
+// ANCImmediate - AND then copy N to Carry. ANC performs a logical AND between
+// the accumulator and the value in memory, and copies the negative flag of
+// the result into the carry flag.
+func ANCImmediate(c *CPU) {
+	and(c, c.getValueByImmediateAddressingMode)
+
+	c.statusRegister.carryFlag = c.statusRegister.negativeFlag
+}
+
+// ALRImmediate - AND then Logical shift Right. ALR performs a logical AND
+// between the accumulator and the value in memory, and shifts the result one
+// bit right.
+func ALRImmediate(c *CPU) {
+	c.programCounter++
+
+	value := c.accumulator & c.getValueByImmediateAddressingMode()
+
+	c.statusRegister.carryFlag = value&0x01 == 0x01
+
+	c.accumulator = value >> 1
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// ARRImmediate - AND then Rotate Right. ARR performs a logical AND between the
+// accumulator and the value in memory, and rotates the result one bit right.
+// The carry and overflow flags are taken from bits 6 and 5 of the result,
+// which comes from the adder, and in decimal mode the adder also corrects the
+// result like ADC would.
+func ARRImmediate(c *CPU) {
+	c.programCounter++
+
+	value := c.accumulator & c.getValueByImmediateAddressingMode()
+
+	result := value >> 1
+	if c.statusRegister.carryFlag {
+		result |= 0x80
+	}
+
+	if !c.statusRegister.decimalModeFlag {
+		raiseStatusRegisterFlags(c, result)
+		c.statusRegister.carryFlag = result&0x40 == 0x40
+		c.statusRegister.overflowFlag = (result>>6)&0x01 != (result>>5)&0x01
+
+		c.accumulator = result
+
+		return
+	}
+
+	c.statusRegister.negativeFlag = c.statusRegister.carryFlag
+	c.statusRegister.zeroFlag = result == 0
+	c.statusRegister.overflowFlag = (result^value)&0x40 == 0x40
+
+	if value&0x0F+value&0x01 > 0x05 {
+		result = result&0xF0 | (result+0x06)&0x0F
+	}
+
+	c.statusRegister.carryFlag = uint16(value&0xF0)+uint16(value&0x10) > 0x50
+	if c.statusRegister.carryFlag {
+		result += 0x60
+	}
+
+	c.accumulator = result
+}
+
+// ANEImmediate - ANE/XAA. ANE stores the accumulator ORed with a magic
+// constant, ANDed with the X register and the value in memory, in the
+// accumulator. The magic constant differs between chips.
+func ANEImmediate(c *CPU) {
+	c.programCounter++
+
+	value := c.getValueByImmediateAddressingMode()
+
+	c.accumulator = (c.accumulator | c.aneMagic) & c.xRegister & value
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// LXAImmediate - LXA/LAX immediate. LXA stores the accumulator ORed with a
+// magic constant, ANDed with the value in memory, in both the accumulator and
+// the X register. The magic constant differs between chips.
+func LXAImmediate(c *CPU) {
+	c.programCounter++
+
+	value := c.getValueByImmediateAddressingMode()
+
+	c.accumulator = (c.accumulator | c.lxaMagic) & value
+	c.xRegister = c.accumulator
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// SBXImmediate - SuBtract from X. SBX subtracts the value in memory from the
+// accumulator ANDed with the X register, without borrow, and stores the result
+// in the X register. The flags are set like CMP does.
+func SBXImmediate(c *CPU) {
+	c.programCounter++
+
+	value := c.getValueByImmediateAddressingMode()
+
+	ax := c.accumulator & c.xRegister
+
+	c.xRegister = ax - value
+
+	raiseStatusRegisterFlags(c, c.xRegister)
+
+	c.statusRegister.carryFlag = ax >= value
+}
+
+// storeAndHighByte stores the value ANDed with the high byte of the base
+// address plus one, in the base address plus the index. When the index
+// crosses a page, the high byte of the address is replaced by the stored
+// value.
+func storeAndHighByte(c *CPU, base uint16, index byte, value byte) {
+	address := c.addressIndexed(base, index)
+
+	value &= byte(base>>8) + 1
+
+	if pageCrossed(base, address) {
+		address = uint16(value)<<8 | address&0x00FF
+	}
+
+	c.writeMemory(address, value)
+}
+
+// SHAIndirectIndexed - Store A AND X AND High byte. SHA stores the
+// accumulator ANDed with the X register and the high byte of the address plus
+// one, in the address stored at the single byte address, plus the Y index
+// register.
+func SHAIndirectIndexed(c *CPU) {
+	c.programCounter++
+
+	zeroPageAddress := c.readMemory(c.programCounter)
+	c.programCounter++
+
+	base := c.readZeroPagePointer(zeroPageAddress)
+
+	storeAndHighByte(c, base, c.yRegister, c.accumulator&c.xRegister)
+}
+
+// SHAAbsoluteY - Store A AND X AND High byte. SHA stores the accumulator
+// ANDed with the X register and the high byte of the address plus one, in the
+// two byte address plus the Y index register.
+func SHAAbsoluteY(c *CPU) {
+	c.programCounter++
+
+	base := c.addressAbsolute()
+
+	storeAndHighByte(c, base, c.yRegister, c.accumulator&c.xRegister)
+}
+
+// SHXAbsoluteY - Store X AND High byte. SHX stores the X register ANDed with
+// the high byte of the address plus one, in the two byte address plus the Y
+// index register.
+func SHXAbsoluteY(c *CPU) {
+	c.programCounter++
+
+	base := c.addressAbsolute()
+
+	storeAndHighByte(c, base, c.yRegister, c.xRegister)
+}
+
+// SHYAbsoluteX - Store Y AND High byte. SHY stores the Y register ANDed with
+// the high byte of the address plus one, in the two byte address plus the X
+// index register.
+func SHYAbsoluteX(c *CPU) {
+	c.programCounter++
+
+	base := c.addressAbsolute()
+
+	storeAndHighByte(c, base, c.xRegister, c.yRegister)
+}
+
+// TASAbsoluteY - Transfer A AND X to Stack pointer. TAS stores the
+// accumulator ANDed with the X register in the stack pointer, and stores the
+// stack pointer ANDed with the high byte of the address plus one, in the two
+// byte address plus the Y index register.
+func TASAbsoluteY(c *CPU) {
+	c.programCounter++
+
+	base := c.addressAbsolute()
+
+	c.stackPointer = c.accumulator & c.xRegister
+
+	storeAndHighByte(c, base, c.yRegister, c.stackPointer)
+}
+
+// LASAbsoluteY - Load A, X and Stack pointer. LAS ANDs the value in the two
+// byte address plus the Y index register with the stack pointer, and stores
+// the result in the accumulator, the X register and the stack pointer.
+func LASAbsoluteY(c *CPU) {
+	c.programCounter++
+
+	value := c.getValueByAbsoluteYAddressingMode() & c.stackPointer
+
+	c.accumulator = value
+	c.xRegister = value
+	c.stackPointer = value
+
+	raiseStatusRegisterFlags(c, value)
+}
+
+// SLOIndexedIndirect - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the address stored at the single byte address plus the
+// X index register one bit left, and ORs the result with the accumulator.
+func SLOIndexedIndirect(c *CPU) {
+	slo(c, c.addressIndexedIndirect)
+}
+
+// SLOZeroPage - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the single byte address one bit left, and ORs the
+// result with the accumulator.
+func SLOZeroPage(c *CPU) {
+	slo(c, c.addressZeroPage)
+}
+
+// SLOAbsolute - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the two byte address one bit left, and ORs the result
+// with the accumulator.
+func SLOAbsolute(c *CPU) {
+	slo(c, c.addressAbsolute)
+}
+
+// SLOIndirectIndexed - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the address stored at the single byte address, plus
+// the Y index register one bit left, and ORs the result with the accumulator.
+func SLOIndirectIndexed(c *CPU) {
+	slo(c, c.addressIndirectIndexed)
+}
+
+// SLOZeroPageX - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the single byte address plus the X index register one
+// bit left, and ORs the result with the accumulator.
+func SLOZeroPageX(c *CPU) {
+	slo(c, c.addressZeroPageX)
+}
+
+// SLOAbsoluteY - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the two byte address plus the Y index register one bit
+// left, and ORs the result with the accumulator.
+func SLOAbsoluteY(c *CPU) {
+	slo(c, c.addressAbsoluteY)
+}
+
+// SLOAbsoluteX - Shift Left then ORA. SLO shifts all bits in the memory
+// location specified by the two byte address plus the X index register one bit
+// left, and ORs the result with the accumulator.
+func SLOAbsoluteX(c *CPU) {
+	slo(c, c.addressAbsoluteX)
+}
+
+// RLAIndexedIndirect - Rotate Left then AND. RLA rotates all bits in the
+// memory location specified by the address stored at the single byte address
+// plus the X index register one bit left, and ANDs the result with the
+// accumulator.
+func RLAIndexedIndirect(c *CPU) {
+	rla(c, c.addressIndexedIndirect)
+}
+
+// RLAZeroPage - Rotate Left then AND. RLA rotates all bits in the memory
+// location specified by the single byte address one bit left, and ANDs the
+// result with the accumulator.
+func RLAZeroPage(c *CPU) {
+	rla(c, c.addressZeroPage)
+}
+
+// RLAAbsolute - Rotate Left then AND. RLA rotates all bits in the memory
+// location specified by the two byte address one bit left, and ANDs the result
+// with the accumulator.
+func RLAAbsolute(c *CPU) {
+	rla(c, c.addressAbsolute)
+}
+
+// RLAIndirectIndexed - Rotate Left then AND. RLA rotates all bits in the
+// memory location specified by the address stored at the single byte address,
+// plus the Y index register one bit left, and ANDs the result with the
+// accumulator.
+func RLAIndirectIndexed(c *CPU) {
+	rla(c, c.addressIndirectIndexed)
+}
+
+// RLAZeroPageX - Rotate Left then AND. RLA rotates all bits in the memory
+// location specified by the single byte address plus the X index register one
+// bit left, and ANDs the result with the accumulator.
+func RLAZeroPageX(c *CPU) {
+	rla(c, c.addressZeroPageX)
+}
+
+// RLAAbsoluteY - Rotate Left then AND. RLA rotates all bits in the memory
+// location specified by the two byte address plus the Y index register one bit
+// left, and ANDs the result with the accumulator.
+func RLAAbsoluteY(c *CPU) {
+	rla(c, c.addressAbsoluteY)
+}
+
+// RLAAbsoluteX - Rotate Left then AND. RLA rotates all bits in the memory
+// location specified by the two byte address plus the X index register one bit
+// left, and ANDs the result with the accumulator.
+func RLAAbsoluteX(c *CPU) {
+	rla(c, c.addressAbsoluteX)
+}
+
+// SREIndexedIndirect - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the address stored at the single byte address plus the
+// X index register one bit right, and EORs the result with the accumulator.
+func SREIndexedIndirect(c *CPU) {
+	sre(c, c.addressIndexedIndirect)
+}
+
+// SREZeroPage - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the single byte address one bit right, and EORs the
+// result with the accumulator.
+func SREZeroPage(c *CPU) {
+	sre(c, c.addressZeroPage)
+}
+
+// SREAbsolute - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the two byte address one bit right, and EORs the
+// result with the accumulator.
+func SREAbsolute(c *CPU) {
+	sre(c, c.addressAbsolute)
+}
+
+// SREIndirectIndexed - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the address stored at the single byte address, plus
+// the Y index register one bit right, and EORs the result with the
+// accumulator.
+func SREIndirectIndexed(c *CPU) {
+	sre(c, c.addressIndirectIndexed)
+}
+
+// SREZeroPageX - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the single byte address plus the X index register one
+// bit right, and EORs the result with the accumulator.
+func SREZeroPageX(c *CPU) {
+	sre(c, c.addressZeroPageX)
+}
+
+// SREAbsoluteY - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the two byte address plus the Y index register one bit
+// right, and EORs the result with the accumulator.
+func SREAbsoluteY(c *CPU) {
+	sre(c, c.addressAbsoluteY)
+}
+
+// SREAbsoluteX - Shift Right then EOR. SRE shifts all bits in the memory
+// location specified by the two byte address plus the X index register one bit
+// right, and EORs the result with the accumulator.
+func SREAbsoluteX(c *CPU) {
+	sre(c, c.addressAbsoluteX)
+}
+
+// RRAIndexedIndirect - Rotate Right then ADC. RRA rotates all bits in the
+// memory location specified by the address stored at the single byte address
+// plus the X index register one bit right, and adds the result and the carry
+// flag to the accumulator.
+func RRAIndexedIndirect(c *CPU) {
+	rra(c, c.addressIndexedIndirect)
+}
+
+// RRAZeroPage - Rotate Right then ADC. RRA rotates all bits in the memory
+// location specified by the single byte address one bit right, and adds the
+// result and the carry flag to the accumulator.
+func RRAZeroPage(c *CPU) {
+	rra(c, c.addressZeroPage)
+}
+
+// RRAAbsolute - Rotate Right then ADC. RRA rotates all bits in the memory
+// location specified by the two byte address one bit right, and adds the
+// result and the carry flag to the accumulator.
+func RRAAbsolute(c *CPU) {
+	rra(c, c.addressAbsolute)
+}
+
+// RRAIndirectIndexed - Rotate Right then ADC. RRA rotates all bits in the
+// memory location specified by the address stored at the single byte address,
+// plus the Y index register one bit right, and adds the result and the carry
+// flag to the accumulator.
+func RRAIndirectIndexed(c *CPU) {
+	rra(c, c.addressIndirectIndexed)
+}
+
+// RRAZeroPageX - Rotate Right then ADC. RRA rotates all bits in the memory
+// location specified by the single byte address plus the X index register one
+// bit right, and adds the result and the carry flag to the accumulator.
+func RRAZeroPageX(c *CPU) {
+	rra(c, c.addressZeroPageX)
+}
+
+// RRAAbsoluteY - Rotate Right then ADC. RRA rotates all bits in the memory
+// location specified by the two byte address plus the Y index register one bit
+// right, and adds the result and the carry flag to the accumulator.
+func RRAAbsoluteY(c *CPU) {
+	rra(c, c.addressAbsoluteY)
+}
+
+// RRAAbsoluteX - Rotate Right then ADC. RRA rotates all bits in the memory
+// location specified by the two byte address plus the X index register one bit
+// right, and adds the result and the carry flag to the accumulator.
+func RRAAbsoluteX(c *CPU) {
+	rra(c, c.addressAbsoluteX)
+}
+
+// DCPIndexedIndirect - DeCrement then comPare. DCP decreases the value held in
+// the memory location specified by the address stored at the single byte
+// address plus the X index register by one, and compares the result with the
+// accumulator.
+func DCPIndexedIndirect(c *CPU) {
+	dcp(c, c.addressIndexedIndirect)
+}
+
+// DCPZeroPage - DeCrement then comPare. DCP decreases the value held in the
+// memory location specified by the single byte address by one, and compares
+// the result with the accumulator.
+func DCPZeroPage(c *CPU) {
+	dcp(c, c.addressZeroPage)
+}
+
+// DCPAbsolute - DeCrement then comPare. DCP decreases the value held in the
+// memory location specified by the two byte address by one, and compares the
+// result with the accumulator.
+func DCPAbsolute(c *CPU) {
+	dcp(c, c.addressAbsolute)
+}
+
+// DCPIndirectIndexed - DeCrement then comPare. DCP decreases the value held in
+// the memory location specified by the address stored at the single byte
+// address, plus the Y index register by one, and compares the result with the
+// accumulator.
+func DCPIndirectIndexed(c *CPU) {
+	dcp(c, c.addressIndirectIndexed)
+}
+
+// DCPZeroPageX - DeCrement then comPare. DCP decreases the value held in the
+// memory location specified by the single byte address plus the X index
+// register by one, and compares the result with the accumulator.
+func DCPZeroPageX(c *CPU) {
+	dcp(c, c.addressZeroPageX)
+}
+
+// DCPAbsoluteY - DeCrement then comPare. DCP decreases the value held in the
+// memory location specified by the two byte address plus the Y index register
+// by one, and compares the result with the accumulator.
+func DCPAbsoluteY(c *CPU) {
+	dcp(c, c.addressAbsoluteY)
+}
+
+// DCPAbsoluteX - DeCrement then comPare. DCP decreases the value held in the
+// memory location specified by the two byte address plus the X index register
+// by one, and compares the result with the accumulator.
+func DCPAbsoluteX(c *CPU) {
+	dcp(c, c.addressAbsoluteX)
+}
+
+// ISCIndexedIndirect - Increment then Subtract with Carry. ISC increases the
+// value held in the memory location specified by the address stored at the
+// single byte address plus the X index register by one, and subtracts the
+// result and the borrow from the accumulator.
+func ISCIndexedIndirect(c *CPU) {
+	isc(c, c.addressIndexedIndirect)
+}
+
+// ISCZeroPage - Increment then Subtract with Carry. ISC increases the value
+// held in the memory location specified by the single byte address by one, and
+// subtracts the result and the borrow from the accumulator.
+func ISCZeroPage(c *CPU) {
+	isc(c, c.addressZeroPage)
+}
+
+// ISCAbsolute - Increment then Subtract with Carry. ISC increases the value
+// held in the memory location specified by the two byte address by one, and
+// subtracts the result and the borrow from the accumulator.
+func ISCAbsolute(c *CPU) {
+	isc(c, c.addressAbsolute)
+}
+
+// ISCIndirectIndexed - Increment then Subtract with Carry. ISC increases the
+// value held in the memory location specified by the address stored at the
+// single byte address, plus the Y index register by one, and subtracts the
+// result and the borrow from the accumulator.
+func ISCIndirectIndexed(c *CPU) {
+	isc(c, c.addressIndirectIndexed)
+}
+
+// ISCZeroPageX - Increment then Subtract with Carry. ISC increases the value
+// held in the memory location specified by the single byte address plus the X
+// index register by one, and subtracts the result and the borrow from the
+// accumulator.
+func ISCZeroPageX(c *CPU) {
+	isc(c, c.addressZeroPageX)
+}
+
+// ISCAbsoluteY - Increment then Subtract with Carry. ISC increases the value
+// held in the memory location specified by the two byte address plus the Y
+// index register by one, and subtracts the result and the borrow from the
+// accumulator.
+func ISCAbsoluteY(c *CPU) {
+	isc(c, c.addressAbsoluteY)
+}
+
+// ISCAbsoluteX - Increment then Subtract with Carry. ISC increases the value
+// held in the memory location specified by the two byte address plus the X
+// index register by one, and subtracts the result and the borrow from the
+// accumulator.
+func ISCAbsoluteX(c *CPU) {
+	isc(c, c.addressAbsoluteX)
+}
+
+// SAXIndexedIndirect - Store A AND X. SAX stores the accumulator ANDed with
+// the X register in the memory location specified by the address stored at the
+// single byte address plus the X index register.
+func SAXIndexedIndirect(c *CPU) {
+	sax(c, c.addressIndexedIndirect)
+}
+
+// SAXZeroPage - Store A AND X. SAX stores the accumulator ANDed with the X
+// register in the memory location specified by the single byte address.
+func SAXZeroPage(c *CPU) {
+	sax(c, c.addressZeroPage)
+}
+
+// SAXAbsolute - Store A AND X. SAX stores the accumulator ANDed with the X
+// register in the memory location specified by the two byte address.
+func SAXAbsolute(c *CPU) {
+	sax(c, c.addressAbsolute)
+}
+
+// SAXZeroPageY - Store A AND X. SAX stores the accumulator ANDed with the X
+// register in the memory location specified by the single byte address plus
+// the Y index register.
+func SAXZeroPageY(c *CPU) {
+	sax(c, c.addressZeroPageY)
+}
+
+// LAXIndexedIndirect - Load A and X. LAX loads the value in the memory
+// location specified by the address stored at the single byte address plus the
+// X index register into both the accumulator and the X register.
+func LAXIndexedIndirect(c *CPU) {
+	lax(c, c.getValueByIndexedIndirectAddressingMode)
+}
+
+// LAXZeroPage - Load A and X. LAX loads the value in the memory location
+// specified by the single byte address into both the accumulator and the X
+// register.
+func LAXZeroPage(c *CPU) {
+	lax(c, c.getValueByZeroPageAddressingMode)
+}
+
+// LAXAbsolute - Load A and X. LAX loads the value in the memory location
+// specified by the two byte address into both the accumulator and the X
+// register.
+func LAXAbsolute(c *CPU) {
+	lax(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// LAXIndirectIndexed - Load A and X. LAX loads the value in the memory
+// location specified by the address stored at the single byte address, plus
+// the Y index register into both the accumulator and the X register.
+func LAXIndirectIndexed(c *CPU) {
+	lax(c, c.getValueByIndirectIndexedAddressingMode)
+}
+
+// LAXZeroPageY - Load A and X. LAX loads the value in the memory location
+// specified by the single byte address plus the Y index register into both the
+// accumulator and the X register.
+func LAXZeroPageY(c *CPU) {
+	lax(c, c.getValueByZeroPageYAddressingMode)
+}
+
+// LAXAbsoluteY - Load A and X. LAX loads the value in the memory location
+// specified by the two byte address plus the Y index register into both the
+// accumulator and the X register.
+func LAXAbsoluteY(c *CPU) {
+	lax(c, c.getValueByAbsoluteYAddressingMode)
+}
+
+// NOPImmediate - No OPeration. Reads the byte after the opcode and throws it
+// away.
+func NOPImmediate(c *CPU) {
+	nop(c, c.getValueByImmediateAddressingMode)
+}
+
+// NOPZeroPage - No OPeration. Reads the memory location specified by the
+// single byte address and throws the value away.
+func NOPZeroPage(c *CPU) {
+	nop(c, c.getValueByZeroPageAddressingMode)
+}
+
+// NOPZeroPageX - No OPeration. Reads the memory location specified by the
+// single byte address plus the X index register and throws the value away.
+func NOPZeroPageX(c *CPU) {
+	nop(c, c.getValueByZeroPageXAddressingMode)
+}
+
+// NOPAbsolute - No OPeration. Reads the memory location specified by the two
+// byte address and throws the value away.
+func NOPAbsolute(c *CPU) {
+	nop(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// NOPAbsoluteX - No OPeration. Reads the memory location specified by the two
+// byte address plus the X index register and throws the value away.
+func NOPAbsoluteX(c *CPU) {
+	nop(c, c.getValueByAbsoluteXAddressingMode)
+}
@@ -70,3 +70,340 @@ func TestSLOIndexedIndirect(t *testing.T) {
 		t.Errorf("Program counter should be incremented")
 	}
 }
+
+func TestAllOpcodesAreImplemented(t *testing.T) {
+	for opcode := 0; opcode < 256; opcode++ {
+		if _, ok := lookupInstruction[byte(opcode)]; !ok {
+			t.Errorf("Opcode 0x%02X is not implemented", opcode)
+		}
+	}
+}
+
+func TestReadModifyWriteCombinations(t *testing.T) {
+	tests := []struct {
+		instruction      string
+		accumulator      byte
+		carry            bool
+		value            byte
+		expectedValue    byte
+		expectedAcc      byte
+		expectedCarry    bool
+		expectedZero     bool
+		expectedNegative bool
+	}{
+		{"SLOZeroPage", 0x01, false, 0x41, 0x82, 0x83, false, false, true},
+		{"SLOZeroPage", 0x00, false, 0x80, 0x00, 0x00, true, true, false},
+		{"RLAZeroPage", 0xFF, true, 0x80, 0x01, 0x01, true, false, false},
+		{"SREZeroPage", 0x01, false, 0x03, 0x01, 0x00, true, true, false},
+		{"RRAZeroPage", 0x10, true, 0x02, 0x81, 0x91, false, false, true},
+		{"RRAZeroPage", 0x80, false, 0x01, 0x00, 0x81, false, false, true},
+		{"DCPZeroPage", 0x41, false, 0x42, 0x41, 0x41, true, true, false},
+		{"DCPZeroPage", 0x40, false, 0x42, 0x41, 0x40, false, false, true},
+		{"ISCZeroPage", 0x50, true, 0x0F, 0x10, 0x40, true, false, false},
+		{"ISCZeroPage", 0x00, true, 0x00, 0x01, 0xFF, false, false, true},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		expectedPC := cpu.programCounter + 2
+		cpu.accumulator = test.accumulator
+		cpu.statusRegister.carryFlag = test.carry
+		cpu.ram[1] = 0x10
+		cpu.ram[0x10] = test.value
+
+		cpu.execute(InstructionAsHex(test.instruction))
+
+		if cpu.ram[0x10] != test.expectedValue {
+			t.Errorf("%s should write 0x%02X, got 0x%02X", test.instruction, test.expectedValue, cpu.ram[0x10])
+		}
+
+		if cpu.accumulator != test.expectedAcc {
+			t.Errorf("%s should set the accumulator to 0x%02X, got 0x%02X", test.instruction, test.expectedAcc, cpu.accumulator)
+		}
+
+		if cpu.statusRegister.carryFlag != test.expectedCarry {
+			t.Errorf("%s should set the carry flag to %t", test.instruction, test.expectedCarry)
+		}
+
+		if cpu.statusRegister.zeroFlag != test.expectedZero {
+			t.Errorf("%s should set the zero flag to %t", test.instruction, test.expectedZero)
+		}
+
+		if cpu.statusRegister.negativeFlag != test.expectedNegative {
+			t.Errorf("%s should set the negative flag to %t", test.instruction, test.expectedNegative)
+		}
+
+		if cpu.programCounter != expectedPC {
+			t.Errorf("%s should increment the program counter by 2", test.instruction)
+		}
+	}
+}
+
+func TestReadModifyWriteAddressingModes(t *testing.T) {
+	tests := []struct {
+		instruction string
+		operands    []byte
+		length      uint16
+	}{
+		// X = Y = 1, the pointer at $0037 is $1337 and the one at $0050 is
+		// $1336.
+		{"DCPIndexedIndirect", []byte{0x36}, 2},
+		{"DCPZeroPage", []byte{0x40}, 2},
+		{"DCPAbsolute", []byte{0x37, 0x13}, 3},
+		{"DCPIndirectIndexed", []byte{0x50}, 2},
+		{"DCPZeroPageX", []byte{0x3F}, 2},
+		{"DCPAbsoluteY", []byte{0x36, 0x13}, 3},
+		{"DCPAbsoluteX", []byte{0x36, 0x13}, 3},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		cpu.xRegister = 0x01
+		cpu.yRegister = 0x01
+		copy(cpu.ram[1:], test.operands)
+		copy(cpu.ram[0x37:], []byte{0x37, 0x13})
+		copy(cpu.ram[0x50:], []byte{0x36, 0x13})
+		cpu.ram[0x40] = 0x42
+		cpu.ram[0x1337] = 0x42
+
+		cpu.execute(InstructionAsHex(test.instruction))
+
+		if cpu.ram[0x1337] != 0x41 && cpu.ram[0x40] != 0x41 {
+			t.Errorf("%s should decrement the memory location", test.instruction)
+		}
+
+		if cpu.programCounter != test.length {
+			t.Errorf("%s should increment the program counter by %d", test.instruction, test.length)
+		}
+	}
+}
+
+func TestLAX(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[1] = 0x10
+	cpu.ram[0x10] = 0x80
+
+	cpu.execute(InstructionAsHex("LAXZeroPage"))
+
+	if cpu.accumulator != 0x80 || cpu.xRegister != 0x80 {
+		t.Errorf("Accumulator and X register should be loaded with 0x80")
+	}
+
+	if !cpu.statusRegister.negativeFlag {
+		t.Errorf("Negative flag should be set")
+	}
+}
+
+func TestSAX(t *testing.T) {
+	cpu := NewCPU()
+	cpu.accumulator = 0xF0
+	cpu.xRegister = 0x3C
+	cpu.ram[1] = 0x10
+
+	cpu.execute(InstructionAsHex("SAXZeroPage"))
+
+	if cpu.ram[0x10] != 0x30 {
+		t.Errorf("Memory should be 0x30, got 0x%02X", cpu.ram[0x10])
+	}
+
+	if cpu.statusRegister.zeroFlag || cpu.statusRegister.negativeFlag {
+		t.Errorf("Flags should not be affected")
+	}
+}
+
+func TestImmediateCombinations(t *testing.T) {
+	tests := []struct {
+		name             string
+		instruction      string
+		accumulator      byte
+		x                byte
+		value            byte
+		carry            bool
+		decimal          bool
+		expectedAcc      byte
+		expectedX        byte
+		expectedCarry    bool
+		expectedOverflow bool
+		expectedNegative bool
+	}{
+		{"ANC sets carry from bit 7", "ANCImmediate", 0xFF, 0x00, 0x80, false, false, 0x80, 0x00, true, false, true},
+		{"ANC clears carry", "ANCImmediate", 0xFF, 0x00, 0x7F, true, false, 0x7F, 0x00, false, false, false},
+		{"ALR", "ALRImmediate", 0xFF, 0x00, 0x03, false, false, 0x01, 0x00, true, false, false},
+		{"ARR with carry", "ARRImmediate", 0xFF, 0x00, 0xFF, true, false, 0xFF, 0x00, true, false, true},
+		{"ARR sets overflow", "ARRImmediate", 0x40, 0x00, 0xFF, false, false, 0x20, 0x00, false, true, false},
+		{"ARR in decimal mode", "ARRImmediate", 0xFF, 0x00, 0xFF, false, true, 0xD5, 0x00, true, false, false},
+		{"ANE", "ANEImmediate", 0x00, 0xFF, 0xFF, false, false, 0xEE, 0xFF, false, false, true},
+		{"LXA", "LXAImmediate", 0x00, 0x00, 0x0F, false, false, 0x0E, 0x0E, false, false, false},
+		{"SBX", "SBXImmediate", 0xF0, 0x3F, 0x10, false, false, 0xF0, 0x20, true, false, false},
+		{"SBX with borrow", "SBXImmediate", 0xF0, 0x3F, 0x31, true, false, 0xF0, 0xFF, false, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := NewCPU()
+			expectedPC := cpu.programCounter + 2
+			cpu.accumulator = test.accumulator
+			cpu.xRegister = test.x
+			cpu.statusRegister.carryFlag = test.carry
+			cpu.statusRegister.decimalModeFlag = test.decimal
+			cpu.ram[1] = test.value
+
+			cpu.execute(InstructionAsHex(test.instruction))
+
+			if cpu.accumulator != test.expectedAcc {
+				t.Errorf("Accumulator should be 0x%02X, got 0x%02X", test.expectedAcc, cpu.accumulator)
+			}
+
+			if cpu.xRegister != test.expectedX {
+				t.Errorf("X register should be 0x%02X, got 0x%02X", test.expectedX, cpu.xRegister)
+			}
+
+			if cpu.statusRegister.carryFlag != test.expectedCarry {
+				t.Errorf("Carry flag should be %t", test.expectedCarry)
+			}
+
+			if cpu.statusRegister.overflowFlag != test.expectedOverflow {
+				t.Errorf("Overflow flag should be %t", test.expectedOverflow)
+			}
+
+			if cpu.statusRegister.negativeFlag != test.expectedNegative {
+				t.Errorf("Negative flag should be %t", test.expectedNegative)
+			}
+
+			if cpu.programCounter != expectedPC {
+				t.Errorf("Program counter should be incremented by 2")
+			}
+		})
+	}
+}
+
+func TestMagicConstants(t *testing.T) {
+	cpu := NewCPU(WithANEMagic(0xFF), WithLXAMagic(0x00))
+	cpu.xRegister = 0xFF
+	cpu.ram[1] = 0xFF
+	cpu.ram[3] = 0xFF
+
+	cpu.execute(InstructionAsHex("ANEImmediate"))
+
+	if cpu.accumulator != 0xFF {
+		t.Errorf("ANE should use the configured magic constant, got 0x%02X", cpu.accumulator)
+	}
+
+	cpu.accumulator = 0x00
+	cpu.execute(InstructionAsHex("LXAImmediate"))
+
+	if cpu.accumulator != 0x00 || cpu.xRegister != 0x00 {
+		t.Errorf("LXA should use the configured magic constant, got 0x%02X", cpu.accumulator)
+	}
+}
+
+func TestStoreAndHighByte(t *testing.T) {
+	tests := []struct {
+		name            string
+		instruction     string
+		accumulator     byte
+		x               byte
+		y               byte
+		expectedAddress uint16
+		expectedValue   byte
+	}{
+		{"SHA", "SHAAbsoluteY", 0xFF, 0xFF, 0x01, 0x12FF, 0x13},
+		{"SHA across a page", "SHAAbsoluteY", 0x0F, 0x0F, 0x02, 0x0300, 0x03},
+		{"SHA (zp),Y", "SHAIndirectIndexed", 0xFF, 0x0F, 0x01, 0x12FF, 0x03},
+		{"SHX", "SHXAbsoluteY", 0x00, 0xFF, 0x01, 0x12FF, 0x13},
+		{"SHX across a page", "SHXAbsoluteY", 0x00, 0x0F, 0x02, 0x0300, 0x03},
+		{"SHY", "SHYAbsoluteX", 0x00, 0x01, 0xFF, 0x12FF, 0x13},
+		{"TAS", "TASAbsoluteY", 0xF3, 0x3F, 0x01, 0x12FF, 0x13},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.accumulator = test.accumulator
+			cpu.xRegister = test.x
+			cpu.yRegister = test.y
+			// The absolute address and the zero page pointer are both $12FE.
+			cpu.ram[1] = 0xFE
+			cpu.ram[2] = 0x12
+			cpu.ram[0xFE] = 0xFE
+			cpu.ram[0xFF] = 0x12
+
+			cpu.execute(InstructionAsHex(test.instruction))
+
+			if cpu.ram[test.expectedAddress] != test.expectedValue {
+				t.Errorf("Memory at 0x%04X should be 0x%02X, got 0x%02X", test.expectedAddress, test.expectedValue, cpu.ram[test.expectedAddress])
+			}
+		})
+	}
+
+	t.Run("TAS sets the stack pointer", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.accumulator = 0xF3
+		cpu.xRegister = 0x3F
+
+		cpu.execute(InstructionAsHex("TASAbsoluteY"))
+
+		if cpu.stackPointer != 0x33 {
+			t.Errorf("Stack pointer should be 0x33, got 0x%02X", cpu.stackPointer)
+		}
+	})
+}
+
+func TestLAS(t *testing.T) {
+	cpu := NewCPU()
+	cpu.stackPointer = 0xF0
+	cpu.ram[1] = 0x37
+	cpu.ram[2] = 0x13
+	cpu.ram[0x1337] = 0x3C
+
+	cpu.execute(InstructionAsHex("LASAbsoluteY"))
+
+	if cpu.accumulator != 0x30 || cpu.xRegister != 0x30 || cpu.stackPointer != 0x30 {
+		t.Errorf("Accumulator, X register and stack pointer should be 0x30")
+	}
+}
+
+func TestUndocumentedNOPs(t *testing.T) {
+	tests := []struct {
+		opcode byte
+		length uint16
+	}{
+		{0x1A, 1}, {0x3A, 1}, {0x5A, 1}, {0x7A, 1}, {0xDA, 1}, {0xFA, 1},
+		{0x80, 2}, {0x82, 2}, {0x89, 2}, {0xC2, 2}, {0xE2, 2},
+		{0x04, 2}, {0x44, 2}, {0x64, 2},
+		{0x14, 2}, {0x34, 2}, {0x54, 2}, {0x74, 2}, {0xD4, 2}, {0xF4, 2},
+		{0x0C, 3},
+		{0x1C, 3}, {0x3C, 3}, {0x5C, 3}, {0x7C, 3}, {0xDC, 3}, {0xFC, 3},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		cpu.ram[1] = 0xFF
+		cpu.ram[2] = 0xFF
+		cpu.ram[0xFF] = 0x42
+		expected := cpu.statusRegister
+
+		cpu.execute(test.opcode)
+
+		if cpu.programCounter != test.length {
+			t.Errorf("NOP 0x%02X should increment the program counter by %d, got %d", test.opcode, test.length, cpu.programCounter)
+		}
+
+		if cpu.statusRegister != expected || cpu.accumulator != 0 || cpu.xRegister != 0 || cpu.yRegister != 0 {
+			t.Errorf("NOP 0x%02X should not change any register", test.opcode)
+		}
+	}
+}
+
+func TestUndocumentedSBC(t *testing.T) {
+	cpu := NewCPU()
+	cpu.accumulator = 0x50
+	cpu.statusRegister.carryFlag = true
+	cpu.ram[1] = 0x10
+
+	cpu.execute(0xEB)
+
+	if cpu.accumulator != 0x40 {
+		t.Errorf("Accumulator should be 0x40, got 0x%02X", cpu.accumulator)
+	}
+}
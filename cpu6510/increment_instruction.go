@@ -0,0 +1,91 @@
+package cpu6510
+
+// INC - INCrement memory. INC increases the value held at the given address
+// by one, and "wraps over" when the numerical limits of a byte are exceeded.
+func inc(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	value := c.readMemory(address)
+
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
+	value++
+
+	raiseStatusRegisterFlags(c, value)
+
+	c.writeMemory(address, value)
+}
+
+// INCZeroPage - INCrement memory. INC increases the value held in the memory
+// location specified by the single byte address by one.
+func INCZeroPage(c *CPU) {
+	inc(c, c.addressZeroPage)
+}
+
+// INCZeroPageX - INCrement memory. INC increases the value held in the memory
+// location specified by the single byte address plus the X index register by
+// one.
+func INCZeroPageX(c *CPU) {
+	inc(c, c.addressZeroPageX)
+}
+
+// INCAbsolute - INCrement memory. INC increases the value held in the memory
+// location specified by the two byte address by one.
+func INCAbsolute(c *CPU) {
+	inc(c, c.addressAbsolute)
+}
+
+// INCAbsoluteX - INCrement memory. INC increases the value held in the memory
+// location specified by the two byte address plus the X index register by
+// one.
+func INCAbsoluteX(c *CPU) {
+	inc(c, c.addressAbsoluteX)
+}
+
+// DEC - DECrement memory. DEC decreases the value held at the given address
+// by one, and "wraps over" when the numerical limits of a byte are exceeded.
+func dec(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	value := c.readMemory(address)
+
+	// The CPU writes the unmodified value back while it modifies it.
+	c.writeMemory(address, value)
+
+	value--
+
+	raiseStatusRegisterFlags(c, value)
+
+	c.writeMemory(address, value)
+}
+
+// DECZeroPage - DECrement memory. DEC decreases the value held in the memory
+// location specified by the single byte address by one.
+func DECZeroPage(c *CPU) {
+	dec(c, c.addressZeroPage)
+}
+
+// DECZeroPageX - DECrement memory. DEC decreases the value held in the memory
+// location specified by the single byte address plus the X index register by
+// one.
+func DECZeroPageX(c *CPU) {
+	dec(c, c.addressZeroPageX)
+}
+
+// DECAbsolute - DECrement memory. DEC decreases the value held in the memory
+// location specified by the two byte address by one.
+func DECAbsolute(c *CPU) {
+	dec(c, c.addressAbsolute)
+}
+
+// DECAbsoluteX - DECrement memory. DEC decreases the value held in the memory
+// location specified by the two byte address plus the X index register by
+// one.
+func DECAbsoluteX(c *CPU) {
+	dec(c, c.addressAbsoluteX)
+}
@@ -0,0 +1,74 @@
+package cpu6510
+
+import "testing"
+
+func TestIncrementAndDecrementMemory(t *testing.T) {
+	setups := []struct {
+		mode       string
+		setup      func(cpu *CPU, value byte)
+		address    uint16
+		expectedPC uint16
+	}{
+		{"ZeroPage", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = value
+		}, 0x0013, 2},
+		{"ZeroPageX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = value
+		}, 0x0014, 2},
+		{"Absolute", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1337] = value
+		}, 0x1337, 3},
+		{"AbsoluteX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 0x1338, 3},
+	}
+
+	tests := []struct {
+		instruction  string
+		value        byte
+		expected     byte
+		zeroFlag     bool
+		negativeFlag bool
+	}{
+		{"INC", 0x41, 0x42, false, false},
+		{"INC", 0xFF, 0x00, true, false},
+		{"INC", 0x7F, 0x80, false, true},
+		{"DEC", 0x43, 0x42, false, false},
+		{"DEC", 0x01, 0x00, true, false},
+		{"DEC", 0x00, 0xFF, false, true},
+	}
+
+	for _, setup := range setups {
+		for _, test := range tests {
+			instruction := test.instruction + setup.mode
+			cpu := NewCPU()
+			setup.setup(cpu, test.value)
+
+			cpu.execute(InstructionAsHex(instruction))
+
+			if cpu.ram[setup.address] != test.expected {
+				t.Errorf("Memory should be 0x%02X, got 0x%02X for instruction: %s", test.expected, cpu.ram[setup.address], instruction)
+			}
+
+			if cpu.statusRegister.zeroFlag != test.zeroFlag {
+				t.Errorf("Zero flag should be %t, got %t for instruction: %s", test.zeroFlag, cpu.statusRegister.zeroFlag, instruction)
+			}
+
+			if cpu.statusRegister.negativeFlag != test.negativeFlag {
+				t.Errorf("Negative flag should be %t, got %t for instruction: %s", test.negativeFlag, cpu.statusRegister.negativeFlag, instruction)
+			}
+
+			if cpu.programCounter != setup.expectedPC {
+				t.Errorf("Program counter should be 0x%04X, got 0x%04X for instruction: %s", setup.expectedPC, cpu.programCounter, instruction)
+			}
+		}
+	}
+}
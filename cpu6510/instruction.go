@@ -9,107 +9,258 @@ var lookupInstruction = map[byte]InstructionFunc{
 	0x01: ORAIndexedIndirect,
 	0x02: JAM,
 	0x03: SLOIndexedIndirect,
+	0x04: NOPZeroPage,
 	0x05: ORAZeroPage,
 	0x06: ASLZeroPage,
+	0x07: SLOZeroPage,
 	0x08: PHP,
 	0x09: ORAImmediate,
+	0x0B: ANCImmediate,
+	0x0C: NOPAbsolute,
+	0x0F: SLOAbsolute,
 	0x10: BPL,
 	0x12: JAM,
 	0x0A: ASLAccumulator,
 	0x0D: ORAAbsolute,
 	0x0E: ASLAbsolute,
 	0x11: ORAIndirectIndexed,
+	0x13: SLOIndirectIndexed,
+	0x14: NOPZeroPageX,
 	0x15: ORAZeroPageX,
 	0x16: ASLZeroPageX,
+	0x17: SLOZeroPageX,
 	0x18: CLC,
 	0x19: ORAAbsoluteY,
+	0x1A: NOP,
+	0x1B: SLOAbsoluteY,
+	0x1C: NOPAbsoluteX,
 	0x1D: ORAAbsoluteX,
 	0x1E: ASLAbsoluteX,
+	0x1F: SLOAbsoluteX,
+	0x20: JSR,
 	0x21: ANDIndexedIndirect,
 	0x22: JAM,
+	0x23: RLAIndexedIndirect,
 	0x24: BITZeroPage,
 	0x25: ANDZeroPage,
 	0x26: ROLZeroPage,
+	0x27: RLAZeroPage,
 	0x28: PLP,
 	0x29: ANDImmediate,
 	0x2A: ROLAccumulator,
+	0x2B: ANCImmediate,
 	0x2C: BITAbsolute,
 	0x2D: ANDAbsolute,
 	0x2E: ROLAbsolute,
+	0x2F: RLAAbsolute,
 	0x30: BMI,
 	0x31: ANDIndirectIndexed,
 	0x32: JAM,
+	0x33: RLAIndirectIndexed,
+	0x34: NOPZeroPageX,
 	0x35: ANDZeroPageX,
 	0x36: ROLZeroPageX,
+	0x37: RLAZeroPageX,
 	0x38: SEC,
 	0x39: ANDAbsoluteY,
+	0x3A: NOP,
+	0x3B: RLAAbsoluteY,
+	0x3C: NOPAbsoluteX,
 	0x3E: ROLAbsoluteX,
 	0x3D: ANDAbsoluteX,
+	0x3F: RLAAbsoluteX,
+	0x40: RTI,
 	0x41: EORIndexedIndirect,
 	0x42: JAM,
+	0x43: SREIndexedIndirect,
+	0x44: NOPZeroPage,
 	0x45: EORZeroPage,
 	0x46: LSRZeroPage,
+	0x47: SREZeroPage,
 	0x48: PHA,
 	0x49: EORImmediate,
 	0x4A: LSRAccumulator,
+	0x4B: ALRImmediate,
+	0x4C: JMPAbsolute,
 	0x4D: EORAbsolute,
 	0x4E: LSRAbsolute,
+	0x4F: SREAbsolute,
 	0x50: BVC,
 	0x51: EORIndirectIndexed,
 	0x52: JAM,
+	0x53: SREIndirectIndexed,
+	0x54: NOPZeroPageX,
 	0x55: EORZeroPageX,
 	0x56: LSRZeroPageX,
+	0x57: SREZeroPageX,
 	0x58: CLI,
 	0x59: EORAbsoluteY,
+	0x5A: NOP,
+	0x5B: SREAbsoluteY,
+	0x5C: NOPAbsoluteX,
 	0x5D: EORAbsoluteX,
 	0x5E: LSRAbsoluteX,
+	0x5F: SREAbsoluteX,
 	0x60: RTS,
+	0x61: ADCIndexedIndirect,
 	0x62: JAM,
+	0x63: RRAIndexedIndirect,
+	0x64: NOPZeroPage,
+	0x65: ADCZeroPage,
 	0x66: RORZeroPage,
+	0x67: RRAZeroPage,
 	0x68: PLA,
+	0x69: ADCImmediate,
 	0x6A: RORAccumulator,
+	0x6B: ARRImmediate,
+	0x6C: JMPIndirect,
+	0x6D: ADCAbsolute,
 	0x6E: RORAbsolute,
+	0x6F: RRAAbsolute,
 	0x70: BVS,
+	0x71: ADCIndirectIndexed,
 	0x72: JAM,
+	0x73: RRAIndirectIndexed,
+	0x74: NOPZeroPageX,
+	0x75: ADCZeroPageX,
 	0x76: RORZeroPageX,
+	0x77: RRAZeroPageX,
 	0x78: SEI,
+	0x79: ADCAbsoluteY,
+	0x7A: NOP,
+	0x7B: RRAAbsoluteY,
+	0x7C: NOPAbsoluteX,
+	0x7D: ADCAbsoluteX,
 	0x7E: RORAbsoluteX,
+	0x7F: RRAAbsoluteX,
+	0x80: NOPImmediate,
+	0x81: STAIndexedIndirect,
+	0x82: NOPImmediate,
+	0x83: SAXIndexedIndirect,
+	0x84: STYZeroPage,
+	0x85: STAZeroPage,
+	0x86: STXZeroPage,
+	0x87: SAXZeroPage,
 	0x88: DEY,
+	0x89: NOPImmediate,
 	0x8A: TXA,
+	0x8B: ANEImmediate,
+	0x8C: STYAbsolute,
+	0x8D: STAAbsolute,
+	0x8E: STXAbsolute,
+	0x8F: SAXAbsolute,
 	0x90: BCC,
+	0x91: STAIndirectIndexed,
 	0x92: JAM,
+	0x93: SHAIndirectIndexed,
+	0x94: STYZeroPageX,
+	0x95: STAZeroPageX,
+	0x96: STXZeroPageY,
+	0x97: SAXZeroPageY,
 	0x98: TYA,
+	0x99: STAAbsoluteY,
 	0x9A: TXS,
+	0x9B: TASAbsoluteY,
+	0x9C: SHYAbsoluteX,
+	0x9D: STAAbsoluteX,
+	0x9E: SHXAbsoluteY,
+	0x9F: SHAAbsoluteY,
+	0xA0: LDYImmediate,
+	0xA1: LDAIndexedIndirect,
+	0xA2: LDXImmediate,
+	0xA3: LAXIndexedIndirect,
+	0xA4: LDYZeroPage,
+	0xA5: LDAZeroPage,
+	0xA6: LDXZeroPage,
+	0xA7: LAXZeroPage,
 	0xA8: TAY,
+	0xA9: LDAImmediate,
 	0xAA: TAX,
+	0xAB: LXAImmediate,
+	0xAC: LDYAbsolute,
+	0xAD: LDAAbsolute,
+	0xAE: LDXAbsolute,
+	0xAF: LAXAbsolute,
 	0xB0: BCS,
+	0xB1: LDAIndirectIndexed,
 	0xB2: JAM,
+	0xB3: LAXIndirectIndexed,
+	0xB4: LDYZeroPageX,
+	0xB5: LDAZeroPageX,
+	0xB6: LDXZeroPageY,
+	0xB7: LAXZeroPageY,
+	0xB9: LDAAbsoluteY,
 	0xBA: TSX,
 	0xB8: CLV,
+	0xBB: LASAbsoluteY,
+	0xBC: LDYAbsoluteX,
+	0xBD: LDAAbsoluteX,
+	0xBE: LDXAbsoluteY,
+	0xBF: LAXAbsoluteY,
 	0xC0: CPYImmediate,
 	0xC1: CMPIndexedIndirect,
+	0xC2: NOPImmediate,
+	0xC3: DCPIndexedIndirect,
 	0xC4: CPYZeroPage,
 	0xC5: CMPZeroPage,
+	0xC6: DECZeroPage,
+	0xC7: DCPZeroPage,
 	0xC8: INY,
 	0xC9: CMPImmediate,
 	0xCA: DEX,
+	0xCB: SBXImmediate,
 	0xCC: CPYAbsolute,
 	0xCD: CMPAbsolute,
+	0xCE: DECAbsolute,
+	0xCF: DCPAbsolute,
 	0xD0: BNE,
 	0xD1: CMPIndirectIndexed,
 	0xD2: JAM,
+	0xD3: DCPIndirectIndexed,
+	0xD4: NOPZeroPageX,
 	0xD5: CMPZeroPageX,
+	0xD6: DECZeroPageX,
+	0xD7: DCPZeroPageX,
 	0xD8: CLD,
 	0xD9: CMPAbsoluteY,
+	0xDA: NOP,
+	0xDB: DCPAbsoluteY,
+	0xDC: NOPAbsoluteX,
 	0xDD: CMPAbsoluteX,
+	0xDE: DECAbsoluteX,
+	0xDF: DCPAbsoluteX,
 	0xE0: CPXImmediate,
+	0xE1: SBCIndexedIndirect,
+	0xE2: NOPImmediate,
+	0xE3: ISCIndexedIndirect,
 	0xE4: CPXZeroPage,
+	0xE5: SBCZeroPage,
+	0xE6: INCZeroPage,
+	0xE7: ISCZeroPage,
+	0xE9: SBCImmediate,
 	0xEA: NOP,
+	0xEB: SBCImmediate,
 	0xEC: CPXAbsolute,
 	0xE8: INX,
+	0xED: SBCAbsolute,
+	0xEE: INCAbsolute,
+	0xEF: ISCAbsolute,
 	0xF0: BEQ,
+	0xF1: SBCIndirectIndexed,
 	0xF2: JAM,
+	0xF3: ISCIndirectIndexed,
+	0xF4: NOPZeroPageX,
+	0xF5: SBCZeroPageX,
+	0xF6: INCZeroPageX,
+	0xF7: ISCZeroPageX,
 	0xF8: SED,
+	0xF9: SBCAbsoluteY,
+	0xFA: NOP,
+	0xFB: ISCAbsoluteY,
+	0xFC: NOPAbsoluteX,
+	0xFD: SBCAbsoluteX,
+	0xFE: INCAbsoluteX,
+	0xFF: ISCAbsoluteX,
 }
 
 // TODO: Perhaps move this as a helper function
@@ -129,96 +280,230 @@ var instructions = map[string]byte{
 	// 0x12 0x22 0x32 0x42 0x52 0x62 0x72 0x92 0xB2 0xD2 0xF2 are also JAM opcodes
 	"JAM":                0x02,
 	"SLOIndexedIndirect": 0x03,
-	"ORAZeroPage":        0x05,
-	"ASLZeroPage":        0x06,
-	"PHP":                0x08,
-	"ORAImmediate":       0x09,
+	// 0x44 0x64 are also NOPZeroPage opcodes
+	"NOPZeroPage":  0x04,
+	"ORAZeroPage":  0x05,
+	"ASLZeroPage":  0x06,
+	"SLOZeroPage":  0x07,
+	"PHP":          0x08,
+	"ORAImmediate": 0x09,
+	// 0x2B is also an ANCImmediate opcode
+	"ANCImmediate":       0x0B,
+	"NOPAbsolute":        0x0C,
+	"SLOAbsolute":        0x0F,
 	"BPL":                0x10,
 	"ASLAccumulator":     0x0A,
 	"ORAAbsolute":        0x0D,
 	"ASLAbsolute":        0x0E,
 	"ORAIndirectIndexed": 0x11,
-	"ORAZeroPageX":       0x15,
-	"ASLZeroPageX":       0x16,
-	"CLC":                0x18,
-	"ORAAbsoluteY":       0x19,
+	"SLOIndirectIndexed": 0x13,
+	// 0x34 0x54 0x74 0xD4 0xF4 are also NOPZeroPageX opcodes
+	"NOPZeroPageX": 0x14,
+	"ORAZeroPageX": 0x15,
+	"ASLZeroPageX": 0x16,
+	"SLOZeroPageX": 0x17,
+	"CLC":          0x18,
+	"ORAAbsoluteY": 0x19,
+	"SLOAbsoluteY": 0x1B,
+	// 0x3C 0x5C 0x7C 0xDC 0xFC are also NOPAbsoluteX opcodes
+	"NOPAbsoluteX":       0x1C,
 	"ORAAbsoluteX":       0x1D,
 	"ASLAbsoluteX":       0x1E,
+	"SLOAbsoluteX":       0x1F,
+	"JSR":                0x20,
 	"ANDIndexedIndirect": 0x21,
+	"RLAIndexedIndirect": 0x23,
 	"BITZeroPage":        0x24,
 	"ANDZeroPage":        0x25,
 	"ROLZeroPage":        0x26,
+	"RLAZeroPage":        0x27,
 	"PLP":                0x28,
 	"ANDImmediate":       0x29,
 	"ROLAccumulator":     0x2A,
 	"BITAbsolute":        0x2C,
 	"ROLAbsolute":        0x2E,
 	"ANDAbsolute":        0x2D,
+	"RLAAbsolute":        0x2F,
 	"BMI":                0x30,
 	"ANDIndirectIndexed": 0x31,
+	"RLAIndirectIndexed": 0x33,
 	"ANDZeroPageX":       0x35,
 	"ROLZeroPageX":       0x36,
+	"RLAZeroPageX":       0x37,
 	"SEC":                0x38,
 	"ANDAbsoluteY":       0x39,
+	"RLAAbsoluteY":       0x3B,
 	"ANDAbsoluteX":       0x3D,
 	"ROLAbsoluteX":       0x3E,
+	"RLAAbsoluteX":       0x3F,
+	"RTI":                0x40,
 	"EORIndexedIndirect": 0x41,
+	"SREIndexedIndirect": 0x43,
 	"EORZeroPage":        0x45,
 	"LSRZeroPage":        0x46,
+	"SREZeroPage":        0x47,
 	"PHA":                0x48,
 	"EORImmediate":       0x49,
 	"LSRAccumulator":     0x4A,
+	"ALRImmediate":       0x4B,
+	"JMPAbsolute":        0x4C,
 	"EORAbsolute":        0x4D,
 	"LSRAbsolute":        0x4E,
+	"SREAbsolute":        0x4F,
 	"BVC":                0x50,
 	"EORIndirectIndexed": 0x51,
+	"SREIndirectIndexed": 0x53,
 	"EORZeroPageX":       0x55,
 	"LSRZeroPageX":       0x56,
+	"SREZeroPageX":       0x57,
 	"CLI":                0x58,
 	"EORAbsoluteY":       0x59,
+	"SREAbsoluteY":       0x5B,
 	"EORAbsoluteX":       0x5D,
 	"LSRAbsoluteX":       0x5E,
+	"SREAbsoluteX":       0x5F,
 	"RTS":                0x60,
+	"ADCIndexedIndirect": 0x61,
+	"RRAIndexedIndirect": 0x63,
+	"ADCZeroPage":        0x65,
 	"RORZeroPage":        0x66,
+	"RRAZeroPage":        0x67,
 	"PLA":                0x68,
+	"ADCImmediate":       0x69,
 	"RORAccumulator":     0x6A,
+	"ARRImmediate":       0x6B,
+	"JMPIndirect":        0x6C,
+	"ADCAbsolute":        0x6D,
 	"RORAbsolute":        0x6E,
+	"RRAAbsolute":        0x6F,
 	"BVS":                0x70,
+	"ADCIndirectIndexed": 0x71,
+	"RRAIndirectIndexed": 0x73,
+	"ADCZeroPageX":       0x75,
 	"RORZeroPageX":       0x76,
+	"RRAZeroPageX":       0x77,
 	"SEI":                0x78,
+	"ADCAbsoluteY":       0x79,
+	"RRAAbsoluteY":       0x7B,
+	"ADCAbsoluteX":       0x7D,
 	"RORAbsoluteX":       0x7E,
+	"RRAAbsoluteX":       0x7F,
+	// 0x82 0x89 0xC2 0xE2 are also NOPImmediate opcodes
+	"NOPImmediate":       0x80,
+	"STAIndexedIndirect": 0x81,
+	"SAXIndexedIndirect": 0x83,
+	"STYZeroPage":        0x84,
+	"STAZeroPage":        0x85,
+	"STXZeroPage":        0x86,
+	"SAXZeroPage":        0x87,
 	"DEY":                0x88,
 	"TXA":                0x8A,
+	"ANEImmediate":       0x8B,
+	"STYAbsolute":        0x8C,
+	"STAAbsolute":        0x8D,
+	"STXAbsolute":        0x8E,
+	"SAXAbsolute":        0x8F,
 	"BCC":                0x90,
+	"STAIndirectIndexed": 0x91,
+	"SHAIndirectIndexed": 0x93,
+	"STYZeroPageX":       0x94,
+	"STAZeroPageX":       0x95,
+	"STXZeroPageY":       0x96,
+	"SAXZeroPageY":       0x97,
 	"TYA":                0x98,
+	"STAAbsoluteY":       0x99,
 	"TXS":                0x9A,
+	"TASAbsoluteY":       0x9B,
+	"SHYAbsoluteX":       0x9C,
+	"STAAbsoluteX":       0x9D,
+	"SHXAbsoluteY":       0x9E,
+	"SHAAbsoluteY":       0x9F,
+	"LDYImmediate":       0xA0,
+	"LDAIndexedIndirect": 0xA1,
+	"LDXImmediate":       0xA2,
+	"LAXIndexedIndirect": 0xA3,
+	"LDYZeroPage":        0xA4,
+	"LDAZeroPage":        0xA5,
+	"LDXZeroPage":        0xA6,
+	"LAXZeroPage":        0xA7,
 	"TAY":                0xA8,
+	"LDAImmediate":       0xA9,
 	"TAX":                0xAA,
+	"LXAImmediate":       0xAB,
+	"LDYAbsolute":        0xAC,
+	"LDAAbsolute":        0xAD,
+	"LDXAbsolute":        0xAE,
+	"LAXAbsolute":        0xAF,
 	"BCS":                0xB0,
+	"LDAIndirectIndexed": 0xB1,
+	"LAXIndirectIndexed": 0xB3,
+	"LDYZeroPageX":       0xB4,
+	"LDAZeroPageX":       0xB5,
+	"LDXZeroPageY":       0xB6,
+	"LAXZeroPageY":       0xB7,
+	"LDAAbsoluteY":       0xB9,
 	"TSX":                0xBA,
 	"CLV":                0xB8,
+	"LASAbsoluteY":       0xBB,
+	"LDYAbsoluteX":       0xBC,
+	"LDAAbsoluteX":       0xBD,
+	"LDXAbsoluteY":       0xBE,
+	"LAXAbsoluteY":       0xBF,
 	"CPYImmediate":       0xC0,
 	"CMPIndexedIndirect": 0xC1,
+	"DCPIndexedIndirect": 0xC3,
 	"CPYZeroPage":        0xC4,
 	"CMPZeroPage":        0xC5,
+	"DECZeroPage":        0xC6,
+	"DCPZeroPage":        0xC7,
 	"INY":                0xC8,
 	"CMPImmediate":       0xC9,
 	"DEX":                0xCA,
+	"SBXImmediate":       0xCB,
 	"CPYAbsolute":        0xCC,
 	"CMPAbsolute":        0xCD,
+	"DECAbsolute":        0xCE,
+	"DCPAbsolute":        0xCF,
 	"BNE":                0xD0,
 	"CMPIndirectIndexed": 0xD1,
+	"DCPIndirectIndexed": 0xD3,
 	"CMPZeroPageX":       0xD5,
+	"DECZeroPageX":       0xD6,
+	"DCPZeroPageX":       0xD7,
 	"CLD":                0xD8,
 	"CMPAbsoluteY":       0xD9,
+	"DCPAbsoluteY":       0xDB,
 	"CMPAbsoluteX":       0xDD,
+	"DECAbsoluteX":       0xDE,
+	"DCPAbsoluteX":       0xDF,
 	"CPXImmediate":       0xE0,
+	"SBCIndexedIndirect": 0xE1,
+	"ISCIndexedIndirect": 0xE3,
 	"CPXZeroPage":        0xE4,
+	"SBCZeroPage":        0xE5,
+	"INCZeroPage":        0xE6,
+	"ISCZeroPage":        0xE7,
+	// 0xEB is also an SBCImmediate opcode
+	"SBCImmediate": 0xE9,
+	// 0x1A 0x3A 0x5A 0x7A 0xDA 0xFA are also NOP opcodes
 	"NOP":                0xEA,
 	"CPXAbsolute":        0xEC,
 	"INX":                0xE8,
+	"SBCAbsolute":        0xED,
+	"INCAbsolute":        0xEE,
+	"ISCAbsolute":        0xEF,
 	"BEQ":                0xF0,
+	"SBCIndirectIndexed": 0xF1,
+	"ISCIndirectIndexed": 0xF3,
+	"SBCZeroPageX":       0xF5,
+	"INCZeroPageX":       0xF6,
+	"ISCZeroPageX":       0xF7,
 	"SED":                0xF8,
+	"SBCAbsoluteY":       0xF9,
+	"ISCAbsoluteY":       0xFB,
+	"SBCAbsoluteX":       0xFD,
+	"INCAbsoluteX":       0xFE,
+	"ISCAbsoluteX":       0xFF,
 }
 
 // ConvertTwoBytesToAddress - converts two bytes into a single address.
@@ -236,73 +521,149 @@ func setCarryFlag(value byte) bool {
 // register based on the value passed in.
 func raiseStatusRegisterFlags(c *CPU, value byte) {
 	c.statusRegister.zeroFlag = value == 0
+	c.statusRegister.negativeFlag = value&0x80 == 0x80
+}
 
-	if value&0x80 == 0x80 {
-		c.statusRegister.negativeFlag = true
-	}
+// implied - steps past the opcode of a single byte instruction. The CPU always
+// fetches the byte after the opcode, and throws it away when the instruction
+// has no operand.
+func implied(c *CPU) {
+	c.programCounter++
+	c.dummyRead(c.programCounter)
 }
 
 // BRK - BReaKpoint. BRK is intended for use as a debugging tool which
 // a programmer may place at specific points in a program, to check the state
 // of processor flags at these points in the code.
+//
+// BRK pushes the address two bytes after the opcode and the status register
+// with the B flag set onto the stack, and jumps through the IRQ vector at
+// $FFFE, just like an IRQ does.
 func BRK(c *CPU) {
-	c.statusRegister.breakCommandFlag = true
-	c.statusRegister.interruptDisableFlag = true
+	c.programCounter++
+
+	// The byte after the opcode is read and skipped, so BRK increments the
+	// program counter by 2 instead of 1.
+	c.dummyRead(c.programCounter)
+	c.programCounter++
 
-	// BRK increments the program counter by 2 instead of 1
-	c.programCounter += 2
+	c.enterInterrupt(irqVector, true)
 }
 
 // PHP - PusH Processor status flags. Pushes the current value of the
-// processor status register onto the stack.
+// processor status register onto the stack. The B flag and the unused flag
+// are always set in the pushed value.
 func PHP(c *CPU) {
-	c.pushOnStack(c.statusRegister.asByte())
-	c.programCounter++
+	implied(c)
+
+	c.pushOnStack(c.statusRegister.asByte() | 0x30)
 }
 
 // CLC - CLear Carry
 func CLC(c *CPU) {
 	c.statusRegister.carryFlag = false
+	implied(c)
+}
+
+// JSR - Jump to SubRoutine. pushes the address of the last byte of the JSR
+// instruction onto the stack, high byte first, and sets the program counter
+// to the target address. RTS adds one to the pulled address to continue with
+// the next instruction.
+func JSR(c *CPU) {
 	c.programCounter++
+
+	// The low byte of the target is fetched before the return address is
+	// pushed, and the high byte after.
+	lowByte := c.readMemory(c.programCounter)
+	c.programCounter++
+
+	// The CPU reads the stack while it stores the low byte internally.
+	c.dummyRead(stackBase + uint16(c.stackPointer))
+
+	c.pushOnStack(byte(c.programCounter >> 8))
+	c.pushOnStack(byte(c.programCounter))
+
+	highByte := c.readMemory(c.programCounter)
+
+	c.programCounter = ConvertTwoBytesToAddress(highByte, lowByte)
 }
 
 // PLP - PuLl Processor status register flags. Pulls the current value from
-// the stack and places it in the processor status register.
+// the stack and places it in the processor status register. The B flag in the
+// pulled value is ignored, and the unused flag stays set.
 func PLP(c *CPU) {
-	value := c.popFromStack()
+	implied(c)
 
-	c.statusRegister = newStatusRegister(value)
+	// The CPU reads the stack while it increments the stack pointer.
+	c.dummyRead(stackBase + uint16(c.stackPointer))
 
-	c.programCounter++
+	value := c.popFromStack()
+
+	c.statusRegister = loadStatusRegister(value)
 }
 
 // SEC - SEt Carry
 func SEC(c *CPU) {
 	c.statusRegister.carryFlag = true
-	c.programCounter++
+	implied(c)
+}
+
+// RTI - ReTurn from Interrupt. pulls the processor status register and then
+// the program counter from the stack. Unlike RTS the pulled address is used
+// as is, since an interrupt pushes the address of the next instruction.
+func RTI(c *CPU) {
+	implied(c)
+
+	// The CPU reads the stack while it increments the stack pointer.
+	c.dummyRead(stackBase + uint16(c.stackPointer))
+
+	c.statusRegister = loadStatusRegister(c.popFromStack())
+
+	lowByte := c.popFromStack()
+	highByte := c.popFromStack()
+
+	c.programCounter = ConvertTwoBytesToAddress(highByte, lowByte)
 }
 
 // PHA - PusH Accumulator. pushes the current value in the accumulator onto
 // the stack.
 func PHA(c *CPU) {
+	implied(c)
+
 	c.pushOnStack(c.accumulator)
+}
+
+// JMPAbsolute - JuMP. sets the program counter to the address specified by
+// the next two bytes in memory.
+func JMPAbsolute(c *CPU) {
 	c.programCounter++
+
+	c.programCounter = c.readAddressFromMemory()
 }
 
 // CLI - CLear Interrupt disable flag
 func CLI(c *CPU) {
 	c.statusRegister.interruptDisableFlag = false
-	c.programCounter++
+	implied(c)
 }
 
 // RTS - ReTurn from Subroutine. pulls the program counter from the stack and
 // places it in the program counter.
 func RTS(c *CPU) {
+	implied(c)
+
+	// The CPU reads the stack while it increments the stack pointer.
+	c.dummyRead(stackBase + uint16(c.stackPointer))
+
 	lowByte := c.popFromStack()
 	highByte := c.popFromStack()
 
 	programCounterAddress := ConvertTwoBytesToAddress(highByte, lowByte)
 
+	// And it reads the pulled address while it increments the program
+	// counter past the last byte of the JSR instruction.
+	c.dummyRead(programCounterAddress)
+
 	c.programCounter = programCounterAddress
 	c.programCounter++
 }
@@ -310,18 +671,36 @@ func RTS(c *CPU) {
 // PLA - PuLl Accumulator. pulls the current value from the stack and places
 // it in the accumulator.
 func PLA(c *CPU) {
+	implied(c)
+
+	// The CPU reads the stack while it increments the stack pointer.
+	c.dummyRead(stackBase + uint16(c.stackPointer))
+
 	c.accumulator = c.popFromStack()
 
 	raiseStatusRegisterFlags(c, c.accumulator)
+}
 
+// JMPIndirect - JuMP. sets the program counter to the address stored at the
+// location specified by the next two bytes in memory. The NMOS 6510 does not
+// carry into the high byte when fetching the pointer, so JMP ($xxFF) reads
+// the high byte of the target from $xx00 instead of the next page.
+func JMPIndirect(c *CPU) {
 	c.programCounter++
+
+	pointer := c.readAddressFromMemory()
+
+	lowByte := c.readMemory(pointer)
+	highByte := c.readMemory(pointer&0xFF00 | uint16(byte(pointer)+1))
+
+	c.programCounter = ConvertTwoBytesToAddress(highByte, lowByte)
 }
 
 // SEI - SEt Interrupt disable flag, preventing the CPU from responding to
 // IRQ interrupts.
 func SEI(c *CPU) {
 	c.statusRegister.interruptDisableFlag = true
-	c.programCounter++
+	implied(c)
 }
 
 // DEY - DEcrement Y register. decreases the numerical value held in the Y
@@ -334,7 +713,7 @@ func DEY(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.yRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // TXA - Transfer X to A. copies the current value in the X index register to
@@ -344,7 +723,7 @@ func TXA(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.accumulator)
 
-	c.programCounter++
+	implied(c)
 }
 
 // TYA - Transfer Y to A. copies the current value in the Y index register to
@@ -354,14 +733,14 @@ func TYA(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.accumulator)
 
-	c.programCounter++
+	implied(c)
 }
 
 // TXS - Transfer X to Stack pointer. copies the current value in the X index
 // register to the stack pointer.
 func TXS(c *CPU) {
 	c.stackPointer = c.xRegister
-	c.programCounter++
+	implied(c)
 }
 
 // TAY - Transfer A to Y. copies the current value in the accumulator to the
@@ -371,7 +750,7 @@ func TAY(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.yRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // TAX - Transfer A to X. copies the current value in the accumulator to the
@@ -381,7 +760,7 @@ func TAX(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.xRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // TSX - Transfer Stack pointer to X. copies the current value in the stack
@@ -391,13 +770,13 @@ func TSX(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.xRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // CLV - CLear oVerflow flag
 func CLV(c *CPU) {
 	c.statusRegister.overflowFlag = false
-	c.programCounter++
+	implied(c)
 }
 
 // INY - INcrement Y register. increases the numerical value held in the Y
@@ -410,7 +789,7 @@ func INY(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.yRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // DEX - DEcrement X register. decreases the numerical value held in the X
@@ -423,18 +802,18 @@ func DEX(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.xRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // CLD - CLear Decimal flag
 func CLD(c *CPU) {
 	c.statusRegister.decimalModeFlag = false
-	c.programCounter++
+	implied(c)
 }
 
 // NOP - No OPeration.
 func NOP(c *CPU) {
-	c.programCounter++
+	implied(c)
 }
 
 // INX - INcrement X register. increases the numerical value held in the X
@@ -447,11 +826,11 @@ func INX(c *CPU) {
 
 	raiseStatusRegisterFlags(c, c.xRegister)
 
-	c.programCounter++
+	implied(c)
 }
 
 // SED - SEt Decimal flag
 func SED(c *CPU) {
 	c.statusRegister.decimalModeFlag = true
-	c.programCounter++
+	implied(c)
 }
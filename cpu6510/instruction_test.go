@@ -27,22 +27,101 @@ func TestConvertTwoBytesToAddress(t *testing.T) {
 	}
 }
 
+func TestDocumentedOpcodesAreImplemented(t *testing.T) {
+	documented := []byte{
+		0x00, 0x01, 0x05, 0x06, 0x08, 0x09, 0x0A, 0x0D, 0x0E,
+		0x10, 0x11, 0x15, 0x16, 0x18, 0x19, 0x1D, 0x1E,
+		0x20, 0x21, 0x24, 0x25, 0x26, 0x28, 0x29, 0x2A, 0x2C, 0x2D, 0x2E,
+		0x30, 0x31, 0x35, 0x36, 0x38, 0x39, 0x3D, 0x3E,
+		0x40, 0x41, 0x45, 0x46, 0x48, 0x49, 0x4A, 0x4C, 0x4D, 0x4E,
+		0x50, 0x51, 0x55, 0x56, 0x58, 0x59, 0x5D, 0x5E,
+		0x60, 0x61, 0x65, 0x66, 0x68, 0x69, 0x6A, 0x6C, 0x6D, 0x6E,
+		0x70, 0x71, 0x75, 0x76, 0x78, 0x79, 0x7D, 0x7E,
+		0x81, 0x84, 0x85, 0x86, 0x88, 0x8A, 0x8C, 0x8D, 0x8E,
+		0x90, 0x91, 0x94, 0x95, 0x96, 0x98, 0x99, 0x9A, 0x9D,
+		0xA0, 0xA1, 0xA2, 0xA4, 0xA5, 0xA6, 0xA8, 0xA9, 0xAA, 0xAC, 0xAD, 0xAE,
+		0xB0, 0xB1, 0xB4, 0xB5, 0xB6, 0xB8, 0xB9, 0xBA, 0xBC, 0xBD, 0xBE,
+		0xC0, 0xC1, 0xC4, 0xC5, 0xC6, 0xC8, 0xC9, 0xCA, 0xCC, 0xCD, 0xCE,
+		0xD0, 0xD1, 0xD5, 0xD6, 0xD8, 0xD9, 0xDD, 0xDE,
+		0xE0, 0xE1, 0xE4, 0xE5, 0xE6, 0xE8, 0xE9, 0xEA, 0xEC, 0xED, 0xEE,
+		0xF0, 0xF1, 0xF5, 0xF6, 0xF8, 0xF9, 0xFD, 0xFE,
+	}
+
+	if len(documented) != 151 {
+		t.Fatalf("There are 151 documented opcodes, got %d", len(documented))
+	}
+
+	for _, opcode := range documented {
+		if _, ok := lookupInstruction[opcode]; !ok {
+			t.Errorf("Documented opcode 0x%02X is not implemented", opcode)
+		}
+	}
+}
+
 func TestBRK(t *testing.T) {
 	cpu := NewCPU()
-	expectedPC := cpu.programCounter + 2
+	cpu.programCounter = 0x0200
+	cpu.statusRegister.carryFlag = true
+	cpu.ram[0x0200] = InstructionAsHex("BRK")
+	cpu.ram[0xFFFE] = 0x37
+	cpu.ram[0xFFFF] = 0x13
 
-	cpu.execute(InstructionAsHex("BRK"))
+	cpu.execute(cpu.next())
 
 	if !cpu.statusRegister.interruptDisableFlag {
 		t.Errorf("Interrupt disable flag should be set")
 	}
 
-	if !cpu.statusRegister.breakCommandFlag {
-		t.Errorf("Break status flag should be set")
+	if cpu.statusRegister.breakCommandFlag {
+		t.Errorf("Break status flag should only be set on the stack")
 	}
 
-	if cpu.programCounter != expectedPC {
-		t.Errorf("Program counter should be incremented by 2")
+	if cpu.ram[0x01FF] != 0x02 || cpu.ram[0x01FE] != 0x02 {
+		t.Errorf("Return address should be pushed onto the stack, got 0x%02X%02X", cpu.ram[0x01FF], cpu.ram[0x01FE])
+	}
+
+	if cpu.ram[0x01FD] != 0x31 {
+		t.Errorf("Status register should be pushed with the B flag set, got 0x%02X", cpu.ram[0x01FD])
+	}
+
+	if cpu.stackPointer != 0xFC {
+		t.Errorf("Stack pointer should be decremented by 3")
+	}
+
+	if cpu.programCounter != 0x1337 {
+		t.Errorf("Program counter should be read from the IRQ vector, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.Cycles() != 7 {
+		t.Errorf("BRK should take 7 cycles, got %d", cpu.Cycles())
+	}
+}
+
+func TestBRKAndRTI(t *testing.T) {
+	cpu := NewCPU()
+	cpu.programCounter = 0x0200
+
+	// BRK, signature byte, INX
+	copy(cpu.ram[0x0200:], []byte{0x00, 0xFF, 0xE8})
+	// RTI
+	cpu.ram[0x1337] = 0x40
+	cpu.ram[0xFFFE] = 0x37
+	cpu.ram[0xFFFF] = 0x13
+
+	cpu.execute(cpu.next())
+	cpu.execute(cpu.next())
+	cpu.execute(cpu.next())
+
+	if cpu.xRegister != 0x01 {
+		t.Errorf("Instruction after the signature byte should be executed")
+	}
+
+	if cpu.statusRegister.interruptDisableFlag {
+		t.Errorf("Interrupt disable flag should be restored")
+	}
+
+	if cpu.stackPointer != 0xFF {
+		t.Errorf("Stack pointer should be restored")
 	}
 }
 
@@ -61,6 +140,130 @@ func TestRTS(t *testing.T) {
 	})
 }
 
+func TestJSR(t *testing.T) {
+	cpu := NewCPU()
+	cpu.programCounter = 0xC000
+	cpu.ram[0xC001] = 0x37
+	cpu.ram[0xC002] = 0x13
+
+	cpu.execute(InstructionAsHex("JSR"))
+
+	if cpu.programCounter != 0x1337 {
+		t.Errorf("Program counter should be set to 0x1337, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.ram[0x01FF] != 0xC0 || cpu.ram[0x01FE] != 0x02 {
+		t.Errorf("Address of the last byte of JSR should be pushed onto the stack, got 0x%02X%02X", cpu.ram[0x01FF], cpu.ram[0x01FE])
+	}
+
+	if cpu.stackPointer != 0xFD {
+		t.Errorf("Stack pointer should be decremented by 2")
+	}
+}
+
+func TestJSRAndRTS(t *testing.T) {
+	cpu := NewCPU()
+	cpu.programCounter = 0xC000
+	cpu.ram[0xC001] = 0x37
+	cpu.ram[0xC002] = 0x13
+
+	cpu.execute(InstructionAsHex("JSR"))
+	cpu.execute(InstructionAsHex("RTS"))
+
+	if cpu.programCounter != 0xC003 {
+		t.Errorf("Program counter should return to the instruction after JSR, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.stackPointer != 0xFF {
+		t.Errorf("Stack pointer should be restored")
+	}
+}
+
+func TestRTI(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0x01FD] = 0b11010011
+	cpu.ram[0x01FE] = 0x37
+	cpu.ram[0x01FF] = 0x13
+	cpu.stackPointer = 0xFC
+
+	cpu.execute(InstructionAsHex("RTI"))
+
+	if cpu.programCounter != 0x1337 {
+		t.Errorf("Program counter should be set to the address on the stack, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.statusRegister != newStatusRegister(0b11100011) {
+		t.Errorf("Status register should be restored from the stack, without the B flag and with bit 5 set, got 0x%02X", cpu.Status())
+	}
+
+	if cpu.stackPointer != 0xFF {
+		t.Errorf("Stack pointer should be incremented by 3")
+	}
+}
+
+func TestJMPAbsolute(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[1] = 0x37
+	cpu.ram[2] = 0x13
+
+	cpu.execute(InstructionAsHex("JMPAbsolute"))
+
+	if cpu.programCounter != 0x1337 {
+		t.Errorf("Program counter should be set to 0x1337, got 0x%04X", cpu.programCounter)
+	}
+}
+
+func TestJMPIndirect(t *testing.T) {
+	t.Run("Jump to the address stored at the pointer", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.ram[1] = 0x00
+		cpu.ram[2] = 0x20
+		cpu.ram[0x2000] = 0x37
+		cpu.ram[0x2001] = 0x13
+
+		cpu.execute(InstructionAsHex("JMPIndirect"))
+
+		if cpu.programCounter != 0x1337 {
+			t.Errorf("Program counter should be set to 0x1337, got 0x%04X", cpu.programCounter)
+		}
+	})
+
+	t.Run("Pointer at the end of a page wraps within the page", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.ram[1] = 0xFF
+		cpu.ram[2] = 0x20
+		cpu.ram[0x20FF] = 0x37
+		cpu.ram[0x2000] = 0x13
+		cpu.ram[0x2100] = 0x42
+
+		cpu.execute(InstructionAsHex("JMPIndirect"))
+
+		if cpu.programCounter != 0x1337 {
+			t.Errorf("Program counter should be set to 0x1337, got 0x%04X", cpu.programCounter)
+		}
+	})
+}
+
+func TestRunSubroutine(t *testing.T) {
+	cpu := NewCPU()
+
+	// JSR $0010, INX, BRK
+	copy(cpu.ram[0x0000:], []byte{0x20, 0x10, 0x00, 0xE8, 0x00})
+	// LDX #$41, RTS
+	copy(cpu.ram[0x0010:], []byte{0xA2, 0x41, 0x60})
+
+	cpu.Run()
+
+	if cpu.xRegister != 0x42 {
+		t.Errorf("X register should be 0x42, got 0x%02X", cpu.xRegister)
+	}
+
+	// BRK pushes the return address and the status register.
+	if cpu.stackPointer != 0xFC {
+		t.Errorf("Stack pointer should be restored after the subroutine")
+	}
+}
+
 func TestNOP(t *testing.T) {
 	cpu := NewCPU()
 	expectedPC := cpu.programCounter + 1
@@ -0,0 +1,137 @@
+package cpu6510
+
+// The addresses of the interrupt vectors. Each vector holds the two byte
+// address, low byte first, that the CPU jumps to when the interrupt occurs.
+const (
+	nmiVector   uint16 = 0xFFFA
+	resetVector uint16 = 0xFFFC
+	irqVector   uint16 = 0xFFFE
+)
+
+// The number of clock cycles it takes the CPU to enter an interrupt.
+const interruptCycles = 7
+
+// AssertIRQ pulls the IRQ line low. The IRQ line is level-triggered, so the
+// CPU keeps taking the interrupt between instructions for as long as the line
+// is asserted and the interrupt disable flag is clear.
+func (c *CPU) AssertIRQ() {
+	c.irqLine = true
+}
+
+// ReleaseIRQ releases the IRQ line again.
+func (c *CPU) ReleaseIRQ() {
+	c.irqLine = false
+}
+
+// TriggerNMI signals a non-maskable interrupt. The NMI line is
+// edge-triggered, so the CPU takes the interrupt once, after the current
+// instruction, regardless of the interrupt disable flag.
+func (c *CPU) TriggerNMI() {
+	c.nmiPending = true
+}
+
+// Reset performs the reset sequence of the CPU, which it runs when the RESET
+// line is released. The sequence works like an interrupt, but the stack
+// accesses are reads, so the stack pointer is decremented by three without
+// anything being pushed. The program counter is loaded from the reset vector
+// at $FFFC/$FFFD. Reset is also the only way to leave a jammed state. An
+// instruction that Tick has started but not completed is abandoned first.
+func (c *CPU) Reset() {
+	c.Close()
+
+	c.isJammed = false
+	c.nmiPending = false
+
+	if c.tracer != nil {
+		c.traceInterrupt(resetVector)
+	}
+
+	if c.ioPort != nil {
+		c.ioPort.reset()
+	}
+
+	c.dummyRead(c.programCounter)
+	c.dummyRead(c.programCounter)
+
+	for i := 0; i < 3; i++ {
+		c.dummyRead(stackBase + uint16(c.stackPointer))
+		c.stackPointer--
+	}
+
+	c.statusRegister.interruptDisableFlag = true
+
+	lowByte := c.readMemory(resetVector)
+	highByte := c.readMemory(resetVector + 1)
+
+	c.programCounter = ConvertTwoBytesToAddress(highByte, lowByte)
+
+	c.cycles += interruptCycles
+}
+
+// serviceInterrupt enters the pending interrupt, if any, and returns true if
+// it did.
+func (c *CPU) serviceInterrupt() bool {
+	vector, ok := c.pendingInterrupt()
+	if ok {
+		c.takeInterrupt(vector)
+	}
+
+	return ok
+}
+
+// pendingInterrupt returns the vector of the interrupt the CPU has to take
+// before the next instruction, if any. NMI takes priority over IRQ, and is no
+// longer pending once it has been returned.
+func (c *CPU) pendingInterrupt() (uint16, bool) {
+	if c.isJammed {
+		return 0, false
+	}
+
+	switch {
+	case c.nmiPending:
+		c.nmiPending = false
+		return nmiVector, true
+	case c.irqLine && !c.statusRegister.interruptDisableFlag:
+		return irqVector, true
+	}
+
+	return 0, false
+}
+
+// takeInterrupt enters the interrupt through the vector.
+func (c *CPU) takeInterrupt(vector uint16) {
+	if c.tracer != nil {
+		c.traceInterrupt(vector)
+	}
+
+	// The CPU fetches the next opcode and then reads it once more, but throws
+	// both away and pushes the address of the opcode instead.
+	c.dummyRead(c.programCounter)
+	c.dummyRead(c.programCounter)
+
+	c.enterInterrupt(vector, false)
+
+	c.cycles += interruptCycles
+}
+
+// enterInterrupt pushes the program counter and the status register onto the
+// stack, sets the interrupt disable flag and jumps through the vector. The B
+// flag only exists on the stack, where it tells BRK apart from IRQ and NMI, so
+// BRK pushes P|$30 and the interrupts push P|$20.
+func (c *CPU) enterInterrupt(vector uint16, isBreak bool) {
+	c.pushOnStack(byte(c.programCounter >> 8))
+	c.pushOnStack(byte(c.programCounter))
+
+	status := c.statusRegister.asByte() | 0x20
+	if isBreak {
+		status |= 0x10
+	}
+	c.pushOnStack(status)
+
+	c.statusRegister.interruptDisableFlag = true
+
+	lowByte := c.readMemory(vector)
+	highByte := c.readMemory(vector + 1)
+
+	c.programCounter = ConvertTwoBytesToAddress(highByte, lowByte)
+}
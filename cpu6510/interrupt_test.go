@@ -0,0 +1,253 @@
+package cpu6510
+
+import "testing"
+
+// newInterruptCPU returns a CPU about to execute INX at $0200, with the IRQ
+// vector pointing at $1337 and the NMI vector pointing at $4242.
+func newInterruptCPU() *CPU {
+	cpu := NewCPU()
+	cpu.programCounter = 0x0200
+	cpu.ram[0x0200] = InstructionAsHex("INX")
+	cpu.ram[0xFFFA] = 0x42
+	cpu.ram[0xFFFB] = 0x42
+	cpu.ram[0xFFFE] = 0x37
+	cpu.ram[0xFFFF] = 0x13
+
+	return cpu
+}
+
+func TestIRQ(t *testing.T) {
+	t.Run("Take the interrupt when the interrupt disable flag is clear", func(t *testing.T) {
+		cpu := newInterruptCPU()
+		cpu.statusRegister.carryFlag = true
+
+		cpu.AssertIRQ()
+
+		if !cpu.serviceInterrupt() {
+			t.Fatalf("IRQ should be taken")
+		}
+
+		if cpu.programCounter != 0x1337 {
+			t.Errorf("Program counter should be read from the IRQ vector, got 0x%04X", cpu.programCounter)
+		}
+
+		if cpu.ram[0x01FF] != 0x02 || cpu.ram[0x01FE] != 0x00 {
+			t.Errorf("Address of the interrupted instruction should be pushed, got 0x%02X%02X", cpu.ram[0x01FF], cpu.ram[0x01FE])
+		}
+
+		if cpu.ram[0x01FD] != 0x21 {
+			t.Errorf("Status register should be pushed with the B flag cleared, got 0x%02X", cpu.ram[0x01FD])
+		}
+
+		if !cpu.statusRegister.interruptDisableFlag {
+			t.Errorf("Interrupt disable flag should be set")
+		}
+
+		if cpu.Cycles() != 7 {
+			t.Errorf("Interrupt should take 7 cycles, got %d", cpu.Cycles())
+		}
+	})
+
+	t.Run("Ignore the interrupt when the interrupt disable flag is set", func(t *testing.T) {
+		cpu := newInterruptCPU()
+		cpu.statusRegister.interruptDisableFlag = true
+
+		cpu.AssertIRQ()
+
+		if cpu.serviceInterrupt() {
+			t.Errorf("IRQ should be masked")
+		}
+	})
+
+	t.Run("Ignore the interrupt when the line is released", func(t *testing.T) {
+		cpu := newInterruptCPU()
+
+		cpu.AssertIRQ()
+		cpu.ReleaseIRQ()
+
+		if cpu.serviceInterrupt() {
+			t.Errorf("IRQ should not be taken")
+		}
+	})
+
+	t.Run("Take the interrupt again while the line is asserted", func(t *testing.T) {
+		cpu := newInterruptCPU()
+		// CLI, RTI
+		copy(cpu.ram[0x1337:], []byte{0x58, 0x40})
+
+		cpu.AssertIRQ()
+		cpu.serviceInterrupt()
+		cpu.execute(cpu.next())
+
+		if !cpu.serviceInterrupt() {
+			t.Errorf("IRQ should be taken again")
+		}
+	})
+
+	t.Run("Return to the interrupted instruction", func(t *testing.T) {
+		cpu := newInterruptCPU()
+		cpu.ram[0x1337] = InstructionAsHex("RTI")
+
+		cpu.AssertIRQ()
+		cpu.serviceInterrupt()
+		cpu.ReleaseIRQ()
+		cpu.execute(cpu.next())
+
+		if cpu.programCounter != 0x0200 {
+			t.Errorf("Program counter should be 0x0200, got 0x%04X", cpu.programCounter)
+		}
+
+		if cpu.statusRegister.interruptDisableFlag {
+			t.Errorf("Interrupt disable flag should be restored")
+		}
+
+		if cpu.stackPointer != 0xFF {
+			t.Errorf("Stack pointer should be restored")
+		}
+	})
+}
+
+func TestNMI(t *testing.T) {
+	t.Run("Take the interrupt when the interrupt disable flag is set", func(t *testing.T) {
+		cpu := newInterruptCPU()
+		cpu.statusRegister.interruptDisableFlag = true
+
+		cpu.TriggerNMI()
+
+		if !cpu.serviceInterrupt() {
+			t.Fatalf("NMI should be taken")
+		}
+
+		if cpu.programCounter != 0x4242 {
+			t.Errorf("Program counter should be read from the NMI vector, got 0x%04X", cpu.programCounter)
+		}
+
+		if cpu.ram[0x01FD] != 0x24 {
+			t.Errorf("Status register should be pushed with the B flag cleared, got 0x%02X", cpu.ram[0x01FD])
+		}
+	})
+
+	t.Run("Take the interrupt only once", func(t *testing.T) {
+		cpu := newInterruptCPU()
+
+		cpu.TriggerNMI()
+		cpu.serviceInterrupt()
+
+		if cpu.serviceInterrupt() {
+			t.Errorf("NMI should only be taken once")
+		}
+	})
+
+	t.Run("Take the interrupt before IRQ", func(t *testing.T) {
+		cpu := newInterruptCPU()
+
+		cpu.AssertIRQ()
+		cpu.TriggerNMI()
+		cpu.serviceInterrupt()
+
+		if cpu.programCounter != 0x4242 {
+			t.Errorf("NMI should be taken first, got 0x%04X", cpu.programCounter)
+		}
+	})
+}
+
+func TestRunTakesInterrupt(t *testing.T) {
+	cpu := newInterruptCPU()
+	// INX, BRK
+	cpu.ram[0x1337] = InstructionAsHex("INX")
+	cpu.ram[0x1338] = InstructionAsHex("BRK")
+
+	cpu.TriggerNMI()
+	cpu.ram[0x4242] = InstructionAsHex("RTI")
+
+	cpu.AssertIRQ()
+	cpu.Run()
+
+	// The NMI handler returns before the INX at $0200, and the IRQ is taken
+	// before INX as well, so only the INX in the IRQ handler is executed.
+	if cpu.xRegister != 0x01 {
+		t.Errorf("X register should be 0x01, got 0x%02X", cpu.xRegister)
+	}
+}
+
+func TestTickInterrupt(t *testing.T) {
+	cpu := newInterruptCPU()
+	cpu.ram[0x01FF] = 0x11
+	cpu.ram[0x01FE] = 0x11
+	cpu.ram[0x01FD] = 0x11
+
+	cpu.AssertIRQ()
+	cycles := tickInstruction(cpu)
+
+	expected := []BusCycle{
+		read(0x0200, 0xE8), read(0x0200, 0xE8), write(0x01FF, 0x02),
+		write(0x01FE, 0x00), write(0x01FD, 0x20), read(0xFFFE, 0x37),
+		read(0xFFFF, 0x13),
+	}
+
+	if len(cycles) != len(expected) {
+		t.Fatalf("Interrupt should take %d cycles, got %d: %v", len(expected), len(cycles), cycles)
+	}
+
+	for i, cycle := range cycles {
+		if cycle != expected[i] {
+			t.Errorf("Cycle %d should be %+v, got %+v", i+1, expected[i], cycle)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	cpu := NewCPU()
+	cpu.programCounter = 0x1234
+	cpu.accumulator = 0x42
+	cpu.ram[0x01FF] = 0x11
+	cpu.ram[0xFFFC] = 0xE2
+	cpu.ram[0xFFFD] = 0xFC
+
+	cpu.Reset()
+
+	if cpu.programCounter != 0xFCE2 {
+		t.Errorf("Program counter should be read from the reset vector, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.stackPointer != 0xFC {
+		t.Errorf("Stack pointer should be decremented by 3, got 0x%02X", cpu.stackPointer)
+	}
+
+	if cpu.ram[0x01FF] != 0x11 {
+		t.Errorf("Reset should not write to the stack")
+	}
+
+	if !cpu.statusRegister.interruptDisableFlag {
+		t.Errorf("Interrupt disable flag should be set")
+	}
+
+	if cpu.accumulator != 0x42 {
+		t.Errorf("Accumulator should be left as is")
+	}
+
+	if cpu.Cycles() != 7 {
+		t.Errorf("Reset should take 7 cycles, got %d", cpu.Cycles())
+	}
+}
+
+func TestResetWhenJammed(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0x0000] = InstructionAsHex("JAM")
+	// LDA #$42, BRK
+	copy(cpu.ram[0x0200:], []byte{0xA9, 0x42, 0x00})
+	cpu.ram[0xFFFC] = 0x00
+	cpu.ram[0xFFFD] = 0x02
+
+	cpu.Run()
+	cpu.Reset()
+	cpu.Run()
+
+	if cpu.isJammed {
+		t.Errorf("CPU should not be jammed after reset")
+	}
+
+	if cpu.accumulator != 0x42 {
+		t.Errorf("Program at the reset vector should be executed")
+	}
+}
@@ -0,0 +1,192 @@
+package cpu6510
+
+// The addresses of the registers of the I/O port.
+const (
+	ioPortDirectionAddress uint16 = 0x0000
+	ioPortDataAddress      uint16 = 0x0001
+)
+
+// The pins of the I/O port, as they are connected in the C64.
+const (
+	loramPin         byte = 1 << 0
+	hiramPin         byte = 1 << 1
+	charenPin        byte = 1 << 2
+	cassetteWritePin byte = 1 << 3
+	cassetteSensePin byte = 1 << 4
+	cassetteMotorPin byte = 1 << 5
+)
+
+// The pins that are pulled up by resistors on the C64 board, and read as 1
+// when they are inputs.
+const ioPortPullUps = loramPin | hiramPin | charenPin | cassetteSensePin
+
+// The 6510 only has pins for bits 0-5. Bits 6 and 7 of the data register are
+// not connected to anything, so when they are inputs they read the charge left
+// on the floating input, which fades away after this many cycles.
+const ioPortFallOffCycles = 350000
+
+// IOPort is the 6-bit I/O port built into the 6510, at $0000 (the data
+// direction register) and $0001 (the data register). A bit set in the data
+// direction register makes the pin an output driven by the data register, a
+// bit cleared makes it an input.
+//
+// On the C64 the port selects the memory configuration through LORAM, HIRAM
+// and CHAREN, and controls the cassette write and motor lines, and reads the
+// cassette sense line. Writes to $0000 and $0001 reach the memory underneath
+// as well, all other addresses are passed on to the bus.
+type IOPort struct {
+	bus Bus
+	// Returns the current clock cycle, for the charge on bits 6 and 7.
+	cycles func() uint64
+	// The data direction register at $0000.
+	direction byte
+	// The data register at $0001.
+	data byte
+	// The level last driven on each pin, which the cassette write line holds
+	// when it is turned into an input.
+	driven byte
+	// True while a button is pressed on the cassette recorder, which pulls
+	// the cassette sense line low.
+	cassetteSense bool
+	// The charge left on bits 6 and 7, and the cycle when it has faded away.
+	charge      byte
+	chargeUntil [8]uint64
+}
+
+// newIOPort creates an I/O port in front of the given bus. All pins are
+// inputs, like after a reset.
+func newIOPort(bus Bus, cycles func() uint64) *IOPort {
+	return &IOPort{
+		bus:    bus,
+		cycles: cycles,
+	}
+}
+
+// reset turns all pins into inputs, like the RESET line does.
+func (p *IOPort) reset() {
+	p.setDirection(0x00)
+}
+
+// Read returns the byte at the given address.
+func (p *IOPort) Read(address uint16) byte {
+	switch address {
+	case ioPortDirectionAddress:
+		return p.direction
+	case ioPortDataAddress:
+		return p.pins() | p.unconnectedBits()
+	}
+
+	return p.bus.Read(address)
+}
+
+// Write writes the byte to the given address.
+func (p *IOPort) Write(address uint16, value byte) {
+	switch address {
+	case ioPortDirectionAddress:
+		p.setDirection(value)
+	case ioPortDataAddress:
+		p.setData(value)
+	}
+
+	p.bus.Write(address, value)
+}
+
+// setDirection sets the data direction register. A bit that is turned from an
+// output into an input keeps the charge of the level it was driven with for a
+// while.
+func (p *IOPort) setDirection(value byte) {
+	p.chargeUnconnectedBits(p.direction&^value, p.data)
+	p.direction = value
+	p.driven = p.driven&^p.direction | p.data&p.direction
+}
+
+// setData sets the data register.
+func (p *IOPort) setData(value byte) {
+	p.chargeUnconnectedBits(p.direction, value)
+	p.data = value
+	p.driven = p.driven&^p.direction | p.data&p.direction
+}
+
+// chargeUnconnectedBits charges the floating bits 6 and 7 in mask with the
+// levels in value.
+func (p *IOPort) chargeUnconnectedBits(mask byte, value byte) {
+	for bit := 6; bit < 8; bit++ {
+		pin := byte(1) << bit
+
+		if mask&pin != 0 {
+			p.charge = p.charge&^pin | value&pin
+			p.chargeUntil[bit] = p.cycles() + ioPortFallOffCycles
+		}
+	}
+}
+
+// unconnectedBits returns bits 6 and 7 of the data register as they are read.
+func (p *IOPort) unconnectedBits() byte {
+	for bit := 6; bit < 8; bit++ {
+		pin := byte(1) << bit
+
+		if p.charge&pin != 0 && p.cycles() > p.chargeUntil[bit] {
+			p.charge &^= pin
+		}
+	}
+
+	return (p.data&p.direction | p.charge&^p.direction) & 0xC0
+}
+
+// pins returns the levels on the pins P0-P5. An output pin has the level of
+// the data register. An input pin is pulled up, except for the cassette sense
+// line when a button is pressed, the cassette write line which holds the level
+// it was last driven with, and the cassette motor line which is pulled down
+// by the motor control circuit.
+func (p *IOPort) pins() byte {
+	inputs := ^p.direction & 0x3F
+
+	levels := p.data & p.direction & 0x3F
+	levels |= inputs & ioPortPullUps
+	levels |= inputs & p.driven & cassetteWritePin
+
+	if p.cassetteSense {
+		levels &^= inputs & cassetteSensePin
+	}
+
+	return levels
+}
+
+// Output returns the levels on the pins P0-P5 of the port.
+func (p *IOPort) Output() byte {
+	return p.pins()
+}
+
+// LORAM returns the level of the LORAM line (P0), which selects the BASIC ROM.
+func (p *IOPort) LORAM() bool {
+	return p.pins()&loramPin != 0
+}
+
+// HIRAM returns the level of the HIRAM line (P1), which selects the KERNAL
+// ROM.
+func (p *IOPort) HIRAM() bool {
+	return p.pins()&hiramPin != 0
+}
+
+// CHAREN returns the level of the CHAREN line (P2), which selects between the
+// I/O area and the character ROM.
+func (p *IOPort) CHAREN() bool {
+	return p.pins()&charenPin != 0
+}
+
+// CassetteWrite returns the level of the cassette write line (P3).
+func (p *IOPort) CassetteWrite() bool {
+	return p.pins()&cassetteWritePin != 0
+}
+
+// CassetteMotor returns true when the cassette motor is turned on, which it
+// is while the motor line (P5) is low.
+func (p *IOPort) CassetteMotor() bool {
+	return p.pins()&cassetteMotorPin == 0
+}
+
+// SetCassetteSense sets the state of the cassette sense line (P4), which is
+// pulled low while a button is pressed on the cassette recorder.
+func (p *IOPort) SetCassetteSense(buttonPressed bool) {
+	p.cassetteSense = buttonPressed
+}
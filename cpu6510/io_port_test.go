@@ -0,0 +1,205 @@
+package cpu6510
+
+import "testing"
+
+func newIOPortCPU() (*CPU, *RAM) {
+	ram := &RAM{}
+
+	return NewCPUWithBus(ram), ram
+}
+
+func TestIOPortAfterReset(t *testing.T) {
+	cpu, _ := newIOPortCPU()
+	port := cpu.IOPort()
+
+	if port.Read(0x0000) != 0x00 {
+		t.Errorf("All pins should be inputs")
+	}
+
+	if !port.LORAM() || !port.HIRAM() || !port.CHAREN() {
+		t.Errorf("LORAM, HIRAM and CHAREN should be pulled up")
+	}
+
+	if !port.CassetteMotor() {
+		t.Errorf("Cassette motor should be turned on when the motor line is an input")
+	}
+
+	if port.Read(0x0001)&0x3F != 0x17 {
+		t.Errorf("Port should read 0x17, got 0x%02X", port.Read(0x0001)&0x3F)
+	}
+}
+
+func TestIOPortOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction byte
+		data      byte
+		expected  byte
+	}{
+		{"KERNAL default", 0x2F, 0x37, 0x37},
+		{"All RAM", 0x2F, 0x30, 0x30},
+		{"Inputs are pulled up", 0x00, 0x00, 0x17},
+		{"Only outputs are driven", 0x01, 0x00, 0x16},
+		{"Motor turned on", 0x2F, 0x17, 0x17},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu, _ := newIOPortCPU()
+			port := cpu.IOPort()
+
+			cpu.writeMemory(0x0000, test.direction)
+			cpu.writeMemory(0x0001, test.data)
+
+			if port.Output() != test.expected {
+				t.Errorf("Port output should be 0x%02X, got 0x%02X", test.expected, port.Output())
+			}
+
+			if cpu.readMemory(0x0001)&0x3F != test.expected {
+				t.Errorf("Port should read 0x%02X, got 0x%02X", test.expected, cpu.readMemory(0x0001)&0x3F)
+			}
+		})
+	}
+}
+
+func TestIOPortBankingLines(t *testing.T) {
+	cpu, _ := newIOPortCPU()
+	port := cpu.IOPort()
+
+	cpu.writeMemory(0x0000, 0x07)
+	cpu.writeMemory(0x0001, 0x05)
+
+	if !port.LORAM() {
+		t.Errorf("LORAM should be high")
+	}
+
+	if port.HIRAM() {
+		t.Errorf("HIRAM should be low")
+	}
+
+	if !port.CHAREN() {
+		t.Errorf("CHAREN should be high")
+	}
+}
+
+func TestIOPortCassetteLines(t *testing.T) {
+	t.Run("Sense line is low while a button is pressed", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.IOPort().SetCassetteSense(true)
+
+		if cpu.readMemory(0x0001)&0x10 != 0 {
+			t.Errorf("Cassette sense should read 0")
+		}
+	})
+
+	t.Run("Motor is turned off by driving the motor line high", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.writeMemory(0x0000, 0x20)
+		cpu.writeMemory(0x0001, 0x20)
+
+		if cpu.IOPort().CassetteMotor() {
+			t.Errorf("Cassette motor should be turned off")
+		}
+	})
+
+	t.Run("Write line holds its level as an input", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.writeMemory(0x0000, 0x08)
+		cpu.writeMemory(0x0001, 0x08)
+		cpu.writeMemory(0x0000, 0x00)
+
+		if !cpu.IOPort().CassetteWrite() {
+			t.Errorf("Cassette write line should still be high")
+		}
+	})
+}
+
+func TestIOPortUnconnectedBits(t *testing.T) {
+	t.Run("Read the data register when they are outputs", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.writeMemory(0x0000, 0xC0)
+		cpu.writeMemory(0x0001, 0x80)
+
+		if cpu.readMemory(0x0001)&0xC0 != 0x80 {
+			t.Errorf("Bits 6 and 7 should read 0x80, got 0x%02X", cpu.readMemory(0x0001)&0xC0)
+		}
+	})
+
+	t.Run("Keep the charge for a while as inputs", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.writeMemory(0x0000, 0xC0)
+		cpu.writeMemory(0x0001, 0xC0)
+		cpu.writeMemory(0x0000, 0x00)
+		cpu.cycles += ioPortFallOffCycles
+
+		if cpu.readMemory(0x0001)&0xC0 != 0xC0 {
+			t.Errorf("Bits 6 and 7 should still read 0xC0, got 0x%02X", cpu.readMemory(0x0001)&0xC0)
+		}
+
+		cpu.cycles++
+
+		if cpu.readMemory(0x0001)&0xC0 != 0x00 {
+			t.Errorf("Bits 6 and 7 should have faded to 0, got 0x%02X", cpu.readMemory(0x0001)&0xC0)
+		}
+	})
+
+	t.Run("Do not charge inputs when the data register is written", func(t *testing.T) {
+		cpu, _ := newIOPortCPU()
+
+		cpu.writeMemory(0x0001, 0xC0)
+
+		if cpu.readMemory(0x0001)&0xC0 != 0x00 {
+			t.Errorf("Bits 6 and 7 should read 0, got 0x%02X", cpu.readMemory(0x0001)&0xC0)
+		}
+	})
+}
+
+func TestIOPortWritesReachMemory(t *testing.T) {
+	cpu, ram := newIOPortCPU()
+	ram[0x0000] = 0x11
+
+	cpu.writeMemory(0x0001, 0x42)
+
+	if ram[0x0001] != 0x42 {
+		t.Errorf("Memory underneath the port should be written")
+	}
+
+	if cpu.readMemory(0x0000) != 0x00 {
+		t.Errorf("Data direction register should be read, not the memory underneath")
+	}
+}
+
+func TestIOPortReset(t *testing.T) {
+	cpu, _ := newIOPortCPU()
+
+	cpu.writeMemory(0x0000, 0x2F)
+	cpu.writeMemory(0x0001, 0x30)
+	cpu.Reset()
+
+	if cpu.readMemory(0x0000) != 0x00 {
+		t.Errorf("All pins should be inputs after reset")
+	}
+
+	if cpu.IOPort().Output() != 0x17 {
+		t.Errorf("Port output should be 0x17, got 0x%02X", cpu.IOPort().Output())
+	}
+}
+
+func TestIOPortNotUsedWithFlatRAM(t *testing.T) {
+	cpu := NewCPU()
+
+	cpu.writeMemory(0x0001, 0xFF)
+
+	if cpu.IOPort() != nil {
+		t.Errorf("CPU with flat RAM should not have an I/O port")
+	}
+
+	if cpu.readMemory(0x0001) != 0xFF {
+		t.Errorf("Address 0x0001 should be plain RAM")
+	}
+}
@@ -0,0 +1,142 @@
+package cpu6510
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The BASIC token of the SYS statement.
+const sysToken byte = 0x9E
+
+// Range is a range of addresses, from Start to End inclusive.
+type Range struct {
+	Start uint16
+	End   uint16
+}
+
+// LoadOption sets up the CPU after a program has been loaded.
+type LoadOption func(c *CPU, data []byte, loaded Range) error
+
+// SetPCToLoadAddress sets the program counter to the load address, for
+// programs written in machine code.
+func SetPCToLoadAddress() LoadOption {
+	return func(c *CPU, data []byte, loaded Range) error {
+		c.programCounter = loaded.Start
+
+		return nil
+	}
+}
+
+// SetPCToSYS sets the program counter to the address of the SYS statement in
+// the first line of a BASIC stub at the start of the program, like
+// 10 SYS 2061.
+func SetPCToSYS() LoadOption {
+	return func(c *CPU, data []byte, loaded Range) error {
+		address, err := sysAddress(data)
+		if err != nil {
+			return err
+		}
+
+		c.programCounter = address
+
+		return nil
+	}
+}
+
+// LoadAt copies the data into memory at the given address, without using any
+// clock cycles, and returns the range it was loaded into. When the CPU is
+// connected to a bus, the data is written past the I/O port to the bus.
+func (c *CPU) LoadAt(address uint16, data []byte, options ...LoadOption) (Range, error) {
+	if len(data) == 0 {
+		return Range{}, errors.New("no data to load")
+	}
+
+	if int(address)+len(data) > memorySize {
+		return Range{}, fmt.Errorf("%d bytes do not fit in memory at $%04X", len(data), address)
+	}
+
+	bus := c.bus
+	if c.ioPort != nil {
+		bus = c.ioPort.bus
+	}
+
+	for i, value := range data {
+		bus.Write(address+uint16(i), value)
+	}
+
+	loaded := Range{Start: address, End: address + uint16(len(data)-1)}
+
+	for _, option := range options {
+		if err := option(c, data, loaded); err != nil {
+			return loaded, err
+		}
+	}
+
+	return loaded, nil
+}
+
+// LoadPRG loads a PRG file, which starts with the two byte load address, low
+// byte first, followed by the data, and returns the range it was loaded into.
+func (c *CPU) LoadPRG(r io.Reader, options ...LoadOption) (Range, error) {
+	prg, err := io.ReadAll(r)
+	if err != nil {
+		return Range{}, err
+	}
+
+	if len(prg) < 2 {
+		return Range{}, errors.New("PRG file is missing the load address")
+	}
+
+	address := ConvertTwoBytesToAddress(prg[1], prg[0])
+
+	return c.LoadAt(address, prg[2:], options...)
+}
+
+// sysAddress returns the address of the SYS statement in the first line of
+// the tokenised BASIC program. A line starts with the two byte address of the
+// next line and the two byte line number, and ends with a zero byte.
+func sysAddress(program []byte) (uint16, error) {
+	if len(program) < 4 {
+		return 0, errors.New("program has no BASIC line")
+	}
+
+	line := program[4:]
+	for i, token := range line {
+		if token == 0x00 {
+			break
+		}
+
+		if token != sysToken {
+			continue
+		}
+
+		var address int
+		digits := 0
+
+		for _, char := range line[i+1:] {
+			if char == ' ' || char == '(' && digits == 0 {
+				continue
+			}
+
+			if char < '0' || char > '9' {
+				break
+			}
+
+			address = address*10 + int(char-'0')
+			digits++
+
+			if address > 0xFFFF {
+				return 0, errors.New("SYS address is out of range")
+			}
+		}
+
+		if digits == 0 {
+			return 0, errors.New("SYS statement has no address")
+		}
+
+		return uint16(address), nil
+	}
+
+	return 0, errors.New("first BASIC line has no SYS statement")
+}
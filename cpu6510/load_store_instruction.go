@@ -0,0 +1,244 @@
+package cpu6510
+
+// LDA - LoaD Accumulator. LDA loads the given value into the accumulator.
+func lda(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	c.accumulator = getValue()
+
+	raiseStatusRegisterFlags(c, c.accumulator)
+}
+
+// LDAImmediate - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAImmediate(c *CPU) {
+	lda(c, c.getValueByImmediateAddressingMode)
+}
+
+// LDAZeroPage - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAZeroPage(c *CPU) {
+	lda(c, c.getValueByZeroPageAddressingMode)
+}
+
+// LDAZeroPageX - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAZeroPageX(c *CPU) {
+	lda(c, c.getValueByZeroPageXAddressingMode)
+}
+
+// LDAAbsolute - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAAbsolute(c *CPU) {
+	lda(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// LDAAbsoluteX - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAAbsoluteX(c *CPU) {
+	lda(c, c.getValueByAbsoluteXAddressingMode)
+}
+
+// LDAAbsoluteY - LoaD Accumulator. LDA loads the value in memory into the
+// accumulator.
+func LDAAbsoluteY(c *CPU) {
+	lda(c, c.getValueByAbsoluteYAddressingMode)
+}
+
+// LDAIndexedIndirect - LoaD Accumulator. LDA loads the value in memory into
+// the accumulator.
+func LDAIndexedIndirect(c *CPU) {
+	lda(c, c.getValueByIndexedIndirectAddressingMode)
+}
+
+// LDAIndirectIndexed - LoaD Accumulator. LDA loads the value in memory into
+// the accumulator.
+func LDAIndirectIndexed(c *CPU) {
+	lda(c, c.getValueByIndirectIndexedAddressingMode)
+}
+
+// LDX - LoaD X register. LDX loads the given value into the X index register.
+func ldx(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	c.xRegister = getValue()
+
+	raiseStatusRegisterFlags(c, c.xRegister)
+}
+
+// LDXImmediate - LoaD X register. LDX loads the value in memory into the X
+// index register.
+func LDXImmediate(c *CPU) {
+	ldx(c, c.getValueByImmediateAddressingMode)
+}
+
+// LDXZeroPage - LoaD X register. LDX loads the value in memory into the X
+// index register.
+func LDXZeroPage(c *CPU) {
+	ldx(c, c.getValueByZeroPageAddressingMode)
+}
+
+// LDXZeroPageY - LoaD X register. LDX loads the value in memory into the X
+// index register.
+func LDXZeroPageY(c *CPU) {
+	ldx(c, c.getValueByZeroPageYAddressingMode)
+}
+
+// LDXAbsolute - LoaD X register. LDX loads the value in memory into the X
+// index register.
+func LDXAbsolute(c *CPU) {
+	ldx(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// LDXAbsoluteY - LoaD X register. LDX loads the value in memory into the X
+// index register.
+func LDXAbsoluteY(c *CPU) {
+	ldx(c, c.getValueByAbsoluteYAddressingMode)
+}
+
+// LDY - LoaD Y register. LDY loads the given value into the Y index register.
+func ldy(c *CPU, getValue func() byte) {
+	c.programCounter++
+
+	c.yRegister = getValue()
+
+	raiseStatusRegisterFlags(c, c.yRegister)
+}
+
+// LDYImmediate - LoaD Y register. LDY loads the value in memory into the Y
+// index register.
+func LDYImmediate(c *CPU) {
+	ldy(c, c.getValueByImmediateAddressingMode)
+}
+
+// LDYZeroPage - LoaD Y register. LDY loads the value in memory into the Y
+// index register.
+func LDYZeroPage(c *CPU) {
+	ldy(c, c.getValueByZeroPageAddressingMode)
+}
+
+// LDYZeroPageX - LoaD Y register. LDY loads the value in memory into the Y
+// index register.
+func LDYZeroPageX(c *CPU) {
+	ldy(c, c.getValueByZeroPageXAddressingMode)
+}
+
+// LDYAbsolute - LoaD Y register. LDY loads the value in memory into the Y
+// index register.
+func LDYAbsolute(c *CPU) {
+	ldy(c, c.getValueByAbsoluteAddressingMode)
+}
+
+// LDYAbsoluteX - LoaD Y register. LDY loads the value in memory into the Y
+// index register.
+func LDYAbsoluteX(c *CPU) {
+	ldy(c, c.getValueByAbsoluteXAddressingMode)
+}
+
+// STA - STore Accumulator. STA stores the value in the accumulator at the
+// given address. No flags are affected.
+func sta(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	c.writeMemory(address, c.accumulator)
+}
+
+// STAZeroPage - STore Accumulator. STA stores the value in the accumulator
+// in memory.
+func STAZeroPage(c *CPU) {
+	sta(c, c.addressZeroPage)
+}
+
+// STAZeroPageX - STore Accumulator. STA stores the value in the accumulator
+// in memory.
+func STAZeroPageX(c *CPU) {
+	sta(c, c.addressZeroPageX)
+}
+
+// STAAbsolute - STore Accumulator. STA stores the value in the accumulator
+// in memory.
+func STAAbsolute(c *CPU) {
+	sta(c, c.addressAbsolute)
+}
+
+// STAAbsoluteX - STore Accumulator. STA stores the value in the accumulator
+// in memory.
+func STAAbsoluteX(c *CPU) {
+	sta(c, c.addressAbsoluteX)
+}
+
+// STAAbsoluteY - STore Accumulator. STA stores the value in the accumulator
+// in memory.
+func STAAbsoluteY(c *CPU) {
+	sta(c, c.addressAbsoluteY)
+}
+
+// STAIndexedIndirect - STore Accumulator. STA stores the value in the
+// accumulator in memory.
+func STAIndexedIndirect(c *CPU) {
+	sta(c, c.addressIndexedIndirect)
+}
+
+// STAIndirectIndexed - STore Accumulator. STA stores the value in the
+// accumulator in memory.
+func STAIndirectIndexed(c *CPU) {
+	sta(c, c.addressIndirectIndexed)
+}
+
+// STX - STore X register. STX stores the value in the X index register at
+// the given address. No flags are affected.
+func stx(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	c.writeMemory(address, c.xRegister)
+}
+
+// STXZeroPage - STore X register. STX stores the value in the X index
+// register in memory.
+func STXZeroPage(c *CPU) {
+	stx(c, c.addressZeroPage)
+}
+
+// STXZeroPageY - STore X register. STX stores the value in the X index
+// register in memory.
+func STXZeroPageY(c *CPU) {
+	stx(c, c.addressZeroPageY)
+}
+
+// STXAbsolute - STore X register. STX stores the value in the X index
+// register in memory.
+func STXAbsolute(c *CPU) {
+	stx(c, c.addressAbsolute)
+}
+
+// STY - STore Y register. STY stores the value in the Y index register at
+// the given address. No flags are affected.
+func sty(c *CPU, getAddress func() uint16) {
+	c.programCounter++
+
+	address := getAddress()
+
+	c.writeMemory(address, c.yRegister)
+}
+
+// STYZeroPage - STore Y register. STY stores the value in the Y index
+// register in memory.
+func STYZeroPage(c *CPU) {
+	sty(c, c.addressZeroPage)
+}
+
+// STYZeroPageX - STore Y register. STY stores the value in the Y index
+// register in memory.
+func STYZeroPageX(c *CPU) {
+	sty(c, c.addressZeroPageX)
+}
+
+// STYAbsolute - STore Y register. STY stores the value in the Y index
+// register in memory.
+func STYAbsolute(c *CPU) {
+	sty(c, c.addressAbsolute)
+}
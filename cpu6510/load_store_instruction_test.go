@@ -0,0 +1,333 @@
+package cpu6510
+
+import "testing"
+
+// register returns the value of the register that the load/store
+// instruction works with.
+func register(cpu *CPU, instruction string) byte {
+	switch instruction[:3] {
+	case "LDX", "STX":
+		return cpu.xRegister
+	case "LDY", "STY":
+		return cpu.yRegister
+	default:
+		return cpu.accumulator
+	}
+}
+
+func TestLoadInstructions(t *testing.T) {
+	tests := []struct {
+		instruction string
+		setup       func(cpu *CPU, value byte)
+		expectedPC  uint16
+	}{
+		{"LDAImmediate", func(cpu *CPU, value byte) {
+			cpu.ram[1] = value
+		}, 2},
+		{"LDAZeroPage", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = value
+		}, 2},
+		{"LDAZeroPageX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = value
+		}, 2},
+		{"LDAAbsolute", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1337] = value
+		}, 3},
+		{"LDAAbsoluteX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+		{"LDAAbsoluteY", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+		{"LDAIndexedIndirect", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = 0x37
+			cpu.ram[0x15] = 0x13
+			cpu.ram[0x1337] = value
+		}, 2},
+		{"LDAIndirectIndexed", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = 0x37
+			cpu.ram[0x14] = 0x13
+			cpu.ram[0x1338] = value
+		}, 2},
+		{"LDXImmediate", func(cpu *CPU, value byte) {
+			cpu.ram[1] = value
+		}, 2},
+		{"LDXZeroPage", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = value
+		}, 2},
+		{"LDXZeroPageY", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = value
+		}, 2},
+		{"LDXAbsolute", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1337] = value
+		}, 3},
+		{"LDXAbsoluteY", func(cpu *CPU, value byte) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+		{"LDYImmediate", func(cpu *CPU, value byte) {
+			cpu.ram[1] = value
+		}, 2},
+		{"LDYZeroPage", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = value
+		}, 2},
+		{"LDYZeroPageX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = value
+		}, 2},
+		{"LDYAbsolute", func(cpu *CPU, value byte) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1337] = value
+		}, 3},
+		{"LDYAbsoluteX", func(cpu *CPU, value byte) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+			cpu.ram[0x1338] = value
+		}, 3},
+	}
+
+	values := []struct {
+		value        byte
+		zeroFlag     bool
+		negativeFlag bool
+	}{
+		{0x42, false, false},
+		{0x00, true, false},
+		{0x80, false, true},
+	}
+
+	for _, test := range tests {
+		for _, v := range values {
+			cpu := NewCPU()
+			test.setup(cpu, v.value)
+
+			cpu.execute(InstructionAsHex(test.instruction))
+
+			if register(cpu, test.instruction) != v.value {
+				t.Errorf("Register should be 0x%02X, got 0x%02X for instruction: %s", v.value, register(cpu, test.instruction), test.instruction)
+			}
+
+			if cpu.statusRegister.zeroFlag != v.zeroFlag {
+				t.Errorf("Zero flag should be %t, got %t for instruction: %s", v.zeroFlag, cpu.statusRegister.zeroFlag, test.instruction)
+			}
+
+			if cpu.statusRegister.negativeFlag != v.negativeFlag {
+				t.Errorf("Negative flag should be %t, got %t for instruction: %s", v.negativeFlag, cpu.statusRegister.negativeFlag, test.instruction)
+			}
+
+			if cpu.programCounter != test.expectedPC {
+				t.Errorf("Program counter should be 0x%04X, got 0x%04X for instruction: %s", test.expectedPC, cpu.programCounter, test.instruction)
+			}
+		}
+	}
+}
+
+func TestLoadClearsNegativeFlag(t *testing.T) {
+	cpu := NewCPU()
+	cpu.statusRegister.negativeFlag = true
+	cpu.ram[1] = 0x01
+
+	cpu.execute(InstructionAsHex("LDAImmediate"))
+
+	if cpu.statusRegister.negativeFlag {
+		t.Errorf("Negative flag should be cleared")
+	}
+}
+
+func TestLoadZeroPageIndexedWrapsAround(t *testing.T) {
+	t.Run("Zero page X", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.xRegister = 0xFF
+		cpu.ram[1] = 0x80
+		cpu.ram[0x7F] = 0x42
+
+		cpu.execute(InstructionAsHex("LDAZeroPageX"))
+
+		if cpu.accumulator != 0x42 {
+			t.Errorf("Accumulator should be loaded from $007F, got 0x%02X", cpu.accumulator)
+		}
+	})
+
+	t.Run("Zero page Y", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.yRegister = 0xFF
+		cpu.ram[1] = 0x80
+		cpu.ram[0x7F] = 0x42
+
+		cpu.execute(InstructionAsHex("LDXZeroPageY"))
+
+		if cpu.xRegister != 0x42 {
+			t.Errorf("X register should be loaded from $007F, got 0x%02X", cpu.xRegister)
+		}
+	})
+}
+
+func TestLoadIndirectPointerWrapsAroundZeroPage(t *testing.T) {
+	t.Run("Indexed indirect", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.xRegister = 0x01
+		cpu.ram[0] = 0x13
+		cpu.ram[1] = 0xFE
+		cpu.ram[0xFF] = 0x37
+		cpu.ram[0x1337] = 0x42
+
+		cpu.execute(InstructionAsHex("LDAIndexedIndirect"))
+
+		if cpu.accumulator != 0x42 {
+			t.Errorf("Pointer high byte should be read from $0000, got 0x%02X", cpu.accumulator)
+		}
+	})
+
+	t.Run("Indirect indexed", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.yRegister = 0x01
+		cpu.ram[0] = 0x13
+		cpu.ram[1] = 0xFF
+		cpu.ram[0xFF] = 0x37
+		cpu.ram[0x1338] = 0x42
+
+		cpu.execute(InstructionAsHex("LDAIndirectIndexed"))
+
+		if cpu.accumulator != 0x42 {
+			t.Errorf("Pointer high byte should be read from $0000, got 0x%02X", cpu.accumulator)
+		}
+	})
+}
+
+func TestStoreInstructions(t *testing.T) {
+	tests := []struct {
+		instruction string
+		setup       func(cpu *CPU)
+		address     uint16
+		expectedPC  uint16
+	}{
+		{"STAZeroPage", func(cpu *CPU) {
+			cpu.ram[1] = 0x13
+		}, 0x0013, 2},
+		{"STAZeroPageX", func(cpu *CPU) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+		}, 0x0014, 2},
+		{"STAAbsolute", func(cpu *CPU) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+		}, 0x1337, 3},
+		{"STAAbsoluteX", func(cpu *CPU) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+		}, 0x1338, 3},
+		{"STAAbsoluteY", func(cpu *CPU) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+		}, 0x1338, 3},
+		{"STAIndexedIndirect", func(cpu *CPU) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x14] = 0x37
+			cpu.ram[0x15] = 0x13
+		}, 0x1337, 2},
+		{"STAIndirectIndexed", func(cpu *CPU) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x13
+			cpu.ram[0x13] = 0x37
+			cpu.ram[0x14] = 0x13
+		}, 0x1338, 2},
+		{"STXZeroPage", func(cpu *CPU) {
+			cpu.ram[1] = 0x13
+		}, 0x0013, 2},
+		{"STXZeroPageY", func(cpu *CPU) {
+			cpu.yRegister = 0x01
+			cpu.ram[1] = 0x13
+		}, 0x0014, 2},
+		{"STXAbsolute", func(cpu *CPU) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+		}, 0x1337, 3},
+		{"STYZeroPage", func(cpu *CPU) {
+			cpu.ram[1] = 0x13
+		}, 0x0013, 2},
+		{"STYZeroPageX", func(cpu *CPU) {
+			cpu.xRegister = 0x01
+			cpu.ram[1] = 0x13
+		}, 0x0014, 2},
+		{"STYAbsolute", func(cpu *CPU) {
+			cpu.ram[1] = 0x37
+			cpu.ram[2] = 0x13
+		}, 0x1337, 3},
+	}
+
+	for _, test := range tests {
+		cpu := NewCPU()
+		cpu.accumulator = 0x80
+		cpu.xRegister = 0x80
+		cpu.yRegister = 0x80
+		test.setup(cpu)
+		expected := register(cpu, test.instruction)
+
+		cpu.execute(InstructionAsHex(test.instruction))
+
+		if cpu.ram[test.address] != expected {
+			t.Errorf("Memory at 0x%04X should be 0x%02X, got 0x%02X for instruction: %s", test.address, expected, cpu.ram[test.address], test.instruction)
+		}
+
+		if cpu.statusRegister.negativeFlag || cpu.statusRegister.zeroFlag {
+			t.Errorf("Flags should not be affected for instruction: %s", test.instruction)
+		}
+
+		if cpu.programCounter != test.expectedPC {
+			t.Errorf("Program counter should be 0x%04X, got 0x%04X for instruction: %s", test.expectedPC, cpu.programCounter, test.instruction)
+		}
+	}
+}
+
+func TestRunLoadAndStoreProgram(t *testing.T) {
+	cpu := NewCPU()
+
+	// LDA #$42, LDX #$01, STA $1337,X, LDY $1338, BRK
+	program := []byte{
+		0xA9, 0x42,
+		0xA2, 0x01,
+		0x9D, 0x37, 0x13,
+		0xAC, 0x38, 0x13,
+		0x00,
+	}
+	copy(cpu.ram[:], program)
+
+	cpu.Run()
+
+	if cpu.ram[0x1338] != 0x42 {
+		t.Errorf("Memory at 0x1338 should be 0x42, got 0x%02X", cpu.ram[0x1338])
+	}
+
+	if cpu.yRegister != 0x42 {
+		t.Errorf("Y register should be 0x42, got 0x%02X", cpu.yRegister)
+	}
+}
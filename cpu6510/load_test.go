@@ -0,0 +1,163 @@
+package cpu6510
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The BASIC stub 10 SYS 2062 at $0801, followed by INX at $080E.
+var basicStub = []byte{
+	0x0C, 0x08, 0x0A, 0x00, sysToken, ' ', '2', '0', '6', '2', 0x00, 0x00, 0x00,
+	0xE8,
+}
+
+func TestLoadAt(t *testing.T) {
+	t.Run("Copy the data into memory", func(t *testing.T) {
+		cpu := NewCPU()
+
+		loaded, err := cpu.LoadAt(0xC000, []byte{0xA9, 0x42, 0x00})
+
+		if err != nil || loaded != (Range{Start: 0xC000, End: 0xC002}) {
+			t.Errorf("Data should be loaded into $C000-$C002, got %+v and %v", loaded, err)
+		}
+
+		if cpu.ram[0xC000] != 0xA9 || cpu.ram[0xC001] != 0x42 || cpu.ram[0xC002] != 0x00 {
+			t.Errorf("Memory should hold the data")
+		}
+
+		if cpu.cycles != 0 || cpu.programCounter != 0x0000 {
+			t.Errorf("Loading should not use cycles or move the program counter")
+		}
+	})
+
+	t.Run("Set the program counter to the load address", func(t *testing.T) {
+		cpu := NewCPU()
+
+		cpu.LoadAt(0xC000, []byte{0xE8}, SetPCToLoadAddress())
+
+		if cpu.programCounter != 0xC000 {
+			t.Errorf("Program counter should be $C000, got $%04X", cpu.programCounter)
+		}
+	})
+
+	t.Run("Fill memory up to the end", func(t *testing.T) {
+		cpu := NewCPU()
+
+		loaded, err := cpu.LoadAt(0xFFFE, []byte{0x01, 0x02})
+
+		if err != nil || loaded.End != 0xFFFF {
+			t.Errorf("Data should be loaded up to $FFFF, got %+v and %v", loaded, err)
+		}
+	})
+
+	t.Run("Reject data past the end of memory", func(t *testing.T) {
+		cpu := NewCPU()
+
+		if _, err := cpu.LoadAt(0xFFFF, []byte{0x01, 0x02}); err == nil {
+			t.Errorf("LoadAt should fail when the data does not fit")
+		}
+	})
+
+	t.Run("Reject empty data", func(t *testing.T) {
+		cpu := NewCPU()
+
+		if _, err := cpu.LoadAt(0xC000, nil); err == nil {
+			t.Errorf("LoadAt should fail without data")
+		}
+	})
+
+	t.Run("Write past the I/O port", func(t *testing.T) {
+		ram := &RAM{}
+		cpu := NewCPUWithBus(ram)
+
+		cpu.LoadAt(0x0000, []byte{0x2F, 0x30})
+
+		if ram[0x0000] != 0x2F || ram[0x0001] != 0x30 {
+			t.Errorf("Data should be written to the bus")
+		}
+
+		if cpu.IOPort().Read(0x0000) != 0x00 {
+			t.Errorf("I/O port should not be written")
+		}
+	})
+}
+
+func TestLoadPRG(t *testing.T) {
+	t.Run("Load at the address in the header", func(t *testing.T) {
+		cpu := NewCPU()
+		prg := append([]byte{0x01, 0x08}, basicStub...)
+
+		loaded, err := cpu.LoadPRG(bytes.NewReader(prg))
+
+		if err != nil || loaded != (Range{Start: 0x0801, End: 0x080E}) {
+			t.Errorf("PRG should be loaded into $0801-$080E, got %+v and %v", loaded, err)
+		}
+
+		if cpu.ram[0x0805] != sysToken || cpu.ram[0x080E] != 0xE8 {
+			t.Errorf("Memory should hold the program")
+		}
+	})
+
+	t.Run("Start at the SYS address", func(t *testing.T) {
+		cpu := NewCPU()
+		prg := append([]byte{0x01, 0x08}, basicStub...)
+
+		cpu.LoadPRG(bytes.NewReader(prg), SetPCToSYS())
+		cpu.Step()
+
+		if cpu.xRegister != 0x01 || cpu.programCounter != 0x080F {
+			t.Errorf("CPU should execute the INX at $080E, got PC $%04X", cpu.programCounter)
+		}
+	})
+
+	t.Run("Reject a file without a load address", func(t *testing.T) {
+		cpu := NewCPU()
+
+		if _, err := cpu.LoadPRG(bytes.NewReader([]byte{0x01})); err == nil {
+			t.Errorf("LoadPRG should fail without a load address")
+		}
+	})
+}
+
+func TestSYSAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected uint16
+		valid    bool
+	}{
+		{"Without a space", "\x9e2061", 2061, true},
+		{"With spaces", "\x9e 49152", 49152, true},
+		{"In parentheses", "\x9e(2064)", 2064, true},
+		{"After another statement", "\x8f:\x9e4096", 4096, true},
+		{"Followed by a colon", "\x9e2061:\x80", 2061, true},
+		{"Without an address", "\x9e", 0, false},
+		{"Out of range", "\x9e65536", 0, false},
+		{"Without SYS", "\x99\"HELLO\"", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program := append([]byte{0x00, 0x00, 0x0A, 0x00}, test.line...)
+			program = append(program, 0x00)
+
+			address, err := sysAddress(program)
+
+			if (err == nil) != test.valid {
+				t.Errorf("sysAddress should be valid: %v, got %v", test.valid, err)
+			}
+
+			if address != test.expected {
+				t.Errorf("SYS address should be %d, got %d", test.expected, address)
+			}
+		})
+	}
+
+	t.Run("Only look at the first line", func(t *testing.T) {
+		program := []byte{0x06, 0x08, 0x0A, 0x00, 0x80, 0x00, 0x00, 0x00, 0x14, 0x00, sysToken, '1', 0x00}
+
+		if _, err := sysAddress(program); err == nil {
+			t.Errorf("sysAddress should not look past the first line")
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package cpu6510
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The test suite of Wolfgang Lorenz is a chain of PRG files, each testing an
+// instruction, like adca.prg, that prints its progress through the KERNAL and
+// loads the next program when it has passed, or waits for a key when it has
+// failed. The files go in testdata/lorenz.
+const lorenzDirectory = "testdata/lorenz"
+
+// The KERNAL entry points the test suite uses, which are emulated by traps.
+const (
+	lorenzCHROUT = 0xFFD2
+	lorenzGETIN  = 0xFFE4
+	lorenzLOAD   = 0xE16F
+	// The test programs return to BASIC through these addresses when they
+	// are run on their own.
+	lorenzReady     = 0xA474
+	lorenzWarmStart = 0x8000
+)
+
+// The number of cycles a test program gets before it is considered stuck,
+// unless it gets stuck in a loop on a single instruction before that.
+const lorenzCycles = 500_000_000
+
+// The test programs that need more of the C64 than flat RAM and a CPU, and
+// cannot pass here, with the reason.
+var lorenzExpectedFailures = map[string]string{
+	"cia1pb6":   "needs CIA 1",
+	"cia1pb7":   "needs CIA 1",
+	"cia1ta":    "needs CIA 1",
+	"cia1tab":   "needs CIA 1",
+	"cia1tb":    "needs CIA 1",
+	"cia1tb123": "needs CIA 1",
+	"cia2pb6":   "needs CIA 2",
+	"cia2pb7":   "needs CIA 2",
+	"cia2ta":    "needs CIA 2",
+	"cia2tb":    "needs CIA 2",
+	"cia2tb123": "needs CIA 2",
+	"cntdef":    "needs the CIAs",
+	"cnto2":     "needs the CIAs",
+	"flipos":    "needs the CIAs",
+	"icr01":     "needs the CIAs",
+	"imr":       "needs the CIAs",
+	"irq":       "needs the CIA timer interrupts",
+	"loadth":    "needs the CIAs",
+	"nmi":       "needs the CIA timer interrupts",
+	"oneshot":   "needs the CIAs",
+	"cputiming": "needs the CIA timers to measure cycles",
+	"cpuport":   "needs the I/O port",
+	"mmu":       "needs the memory map of the C64",
+	"mmufetch":  "needs the memory map of the C64",
+}
+
+// The IRQ handler of the KERNAL at $FF48, which calls the BRK vector at $0316
+// or the IRQ vector at $0314.
+var lorenzIRQHandler = []byte{
+	0x48, 0x8A, 0x48, 0x98, 0x48, 0xBA, 0xBD, 0x04, 0x01,
+	0x29, 0x10, 0xF0, 0x03, 0x6C, 0x16, 0x03, 0x6C, 0x14, 0x03,
+}
+
+// The ways a test program ends.
+var (
+	errLorenzPassed = errors.New("passed")
+	errLorenzFailed = errors.New("failed")
+)
+
+// petscii returns the PETSCII character as it is shown in the lower case
+// character set, which the test suite uses.
+func petscii(char byte) string {
+	switch {
+	case char == 0x0D:
+		return "\n"
+	case char >= 0x41 && char <= 0x5A:
+		return string(rune(char + 0x20))
+	case char >= 0xC1 && char <= 0xDA:
+		return string(rune(char - 0x80))
+	case char >= 0x20 && char < 0x60:
+		return string(rune(char))
+	}
+
+	return ""
+}
+
+// newLorenzCPU loads the test program, and sets up the parts of the KERNAL
+// and the memory map it depends on. Its output is written to out.
+func newLorenzCPU(prg []byte, out *strings.Builder) (*CPU, error) {
+	cpu := NewCPU()
+
+	if _, err := cpu.LoadPRG(bytes.NewReader(prg), SetPCToSYS()); err != nil {
+		if _, err := cpu.LoadPRG(bytes.NewReader(prg), SetPCToLoadAddress()); err != nil {
+			return nil, err
+		}
+	}
+
+	cpu.ram[0x0002] = 0x00
+	// The BASIC warm start vector, and the return address on the stack, both
+	// lead to $8000.
+	cpu.ram[0xA002] = 0x00
+	cpu.ram[0xA003] = 0x80
+	cpu.ram[0x01FE] = 0xFF
+	cpu.ram[0x01FF] = 0x7F
+	cpu.stackPointer = 0xFD
+	cpu.statusRegister = newStatusRegister(0x24)
+
+	copy(cpu.ram[0xFF48:], lorenzIRQHandler)
+	cpu.ram[0xFFFE] = 0x48
+	cpu.ram[0xFFFF] = 0xFF
+
+	cpu.SetTrap(lorenzCHROUT, func(c *CPU) error {
+		out.WriteString(petscii(c.A()))
+		c.ram[0x030C] = 0x00
+		c.ReturnFromSubroutine()
+
+		return nil
+	})
+	cpu.SetTrap(lorenzGETIN, func(c *CPU) error {
+		return errLorenzFailed
+	})
+	for _, address := range []uint16{lorenzLOAD, lorenzReady, lorenzWarmStart} {
+		cpu.SetTrap(address, func(c *CPU) error {
+			return errLorenzPassed
+		})
+	}
+
+	return cpu, nil
+}
+
+// runLorenzTest runs the test program until it passes or fails.
+func runLorenzTest(prg []byte) (string, error) {
+	var out strings.Builder
+
+	cpu, err := newLorenzCPU(prg, &out)
+	if err != nil {
+		return "", err
+	}
+
+	for cpu.cycles < lorenzCycles {
+		pc := cpu.programCounter
+
+		if _, err := cpu.Step(); err != nil {
+			return out.String(), err
+		}
+
+		if cpu.programCounter == pc {
+			return out.String(), fmt.Errorf("stuck at $%04X", pc)
+		}
+	}
+
+	return out.String(), fmt.Errorf("no result after %d cycles", lorenzCycles)
+}
+
+func TestLorenz(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping Lorenz test suite in short mode")
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(lorenzDirectory, "*.prg"))
+	if len(paths) == 0 {
+		t.Skipf("no test programs in %s, see testdata/README.md", lorenzDirectory)
+	}
+	sort.Strings(paths)
+
+	var mu sync.Mutex
+	var failed []string
+
+	t.Run("Programs", func(t *testing.T) {
+		for _, path := range paths {
+			name := strings.TrimSpace(strings.TrimSuffix(filepath.Base(path), ".prg"))
+
+			// The first and the last program of the chain do not test
+			// anything.
+			if name == "start" || name == "finish" {
+				continue
+			}
+
+			t.Run(name, func(t *testing.T) {
+				if reason, ok := lorenzExpectedFailures[name]; ok {
+					t.Skipf("%s cannot pass on flat RAM: %s", name, reason)
+				}
+
+				t.Parallel()
+
+				prg, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("read error: %v", err)
+				}
+
+				out, err := runLorenzTest(prg)
+				if errors.Is(err, errLorenzPassed) {
+					return
+				}
+
+				t.Errorf("%s should pass, got %v with output:\n%s", name, err, out)
+
+				mu.Lock()
+				failed = append(failed, name)
+				mu.Unlock()
+			})
+		}
+	})
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		t.Errorf("%d of the test programs failed: %s", len(failed), strings.Join(failed, " "))
+	}
+}
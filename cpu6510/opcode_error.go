@@ -0,0 +1,108 @@
+package cpu6510
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrJammed is returned by Step when the CPU is jammed. Only Reset can bring
+// it back to life.
+var ErrJammed = errors.New("cpu6510: CPU is jammed")
+
+// JAMError is the error of one of the JAM opcodes, which lock up the chip
+// until it is reset. Under the default policy Step returns it and the CPU
+// jams, and it then matches ErrJammed with errors.Is.
+type JAMError struct {
+	// The JAM opcode that was fetched.
+	Opcode byte
+	// The address the opcode was fetched from.
+	Address uint16
+	// The clock cycle the instruction started on.
+	Cycle uint64
+}
+
+func (e *JAMError) Error() string {
+	return fmt.Sprintf("cpu6510: JAM opcode $%02X at $%04X (cycle %d)", e.Opcode, e.Address, e.Cycle)
+}
+
+// Unwrap returns ErrJammed.
+func (e *JAMError) Unwrap() error {
+	return ErrJammed
+}
+
+// JAMPolicy decides how the CPU reacts to a JAM opcode. Every other opcode of
+// the NMOS 6510 has some behaviour, so the JAM opcodes are the only ones that
+// an embedding application may want to handle differently.
+type JAMPolicy int
+
+const (
+	// HaltOnJAM jams the CPU, like the real chip, and returns a JAMError.
+	// This is the default.
+	HaltOnJAM JAMPolicy = iota
+	// SkipJAM treats the opcode as a single byte NOP, taking two cycles, and
+	// carries on without an error.
+	SkipJAM
+	// HookJAM calls the hook set by WithJAMHook.
+	HookJAM
+	// PanicOnJAM panics with the JAMError.
+	PanicOnJAM
+)
+
+// JAMHook is called for a JAM opcode when the policy is HookJAM. The program
+// counter has already been moved past the opcode. The error it returns is
+// returned by Step, and nil lets the CPU carry on.
+type JAMHook func(c *CPU, err *JAMError) error
+
+// WithJAMPolicy sets how the CPU reacts to a JAM opcode.
+func WithJAMPolicy(policy JAMPolicy) Option {
+	return func(c *CPU) {
+		c.jamPolicy = policy
+	}
+}
+
+// WithJAMHook sets the hook that is called for a JAM opcode, and selects the
+// HookJAM policy.
+func WithJAMHook(hook JAMHook) Option {
+	return func(c *CPU) {
+		c.jamPolicy = HookJAM
+		c.jamHook = hook
+	}
+}
+
+// isJAMOpcode returns true for the twelve JAM opcodes, $x2 in the columns
+// where there is no immediate instruction.
+func isJAMOpcode(opcode byte) bool {
+	return opcode&0x0F == 0x02 && (opcode < 0x80 || opcode&0x10 != 0)
+}
+
+// jam handles the JAM opcode according to the policy of the CPU. The program
+// counter still points at the opcode.
+func (c *CPU) jam(opcode byte) error {
+	err := &JAMError{
+		Opcode:  opcode,
+		Address: c.programCounter,
+		Cycle:   c.cycles,
+	}
+
+	switch c.jamPolicy {
+	case SkipJAM:
+		NOP(c)
+		c.cycles += 2
+
+		return nil
+	case HookJAM:
+		c.programCounter++
+
+		if c.jamHook == nil {
+			return err
+		}
+
+		return c.jamHook(c, err)
+	case PanicOnJAM:
+		panic(err)
+	}
+
+	JAM(c)
+
+	return err
+}
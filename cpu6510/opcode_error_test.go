@@ -0,0 +1,166 @@
+package cpu6510
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsJAMOpcode(t *testing.T) {
+	jams := map[byte]bool{
+		0x02: true, 0x12: true, 0x22: true, 0x32: true, 0x42: true, 0x52: true,
+		0x62: true, 0x72: true, 0x92: true, 0xB2: true, 0xD2: true, 0xF2: true,
+	}
+
+	for opcode := 0; opcode < 256; opcode++ {
+		if isJAMOpcode(byte(opcode)) != jams[byte(opcode)] {
+			t.Errorf("isJAMOpcode(0x%02X) should be %v", opcode, jams[byte(opcode)])
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0x0000] = InstructionAsHex("LDAImmediate")
+	cpu.ram[0x0001] = 0x42
+	cpu.ram[0x0002] = InstructionAsHex("INX")
+
+	cycles, err := cpu.Step()
+
+	if err != nil {
+		t.Errorf("Step should not return an error, got %v", err)
+	}
+
+	if cycles != 2 || cpu.accumulator != 0x42 || cpu.programCounter != 0x0002 {
+		t.Errorf("Step should execute LDA in 2 cycles, got %d cycles", cycles)
+	}
+
+	cpu.TriggerNMI()
+	cpu.ram[nmiVector] = 0x00
+	cpu.ram[nmiVector+1] = 0x10
+
+	cycles, _ = cpu.Step()
+
+	if cycles != interruptCycles || cpu.programCounter != 0x1000 {
+		t.Errorf("Step should enter the NMI in %d cycles, got %d cycles", interruptCycles, cycles)
+	}
+}
+
+func TestJAMPolicies(t *testing.T) {
+	newJAMCPU := func(options ...Option) *CPU {
+		cpu := NewCPU(options...)
+		cpu.programCounter = 0x0200
+		cpu.cycles = 100
+		cpu.ram[0x0200] = 0x12
+		cpu.ram[0x0201] = InstructionAsHex("INX")
+
+		return cpu
+	}
+
+	expected := JAMError{Opcode: 0x12, Address: 0x0200, Cycle: 100}
+
+	t.Run("Halt by default", func(t *testing.T) {
+		cpu := newJAMCPU()
+
+		_, err := cpu.Step()
+
+		var jamErr *JAMError
+		if !errors.As(err, &jamErr) || *jamErr != expected {
+			t.Errorf("Step should return %+v, got %v", expected, err)
+		}
+
+		if !errors.Is(err, ErrJammed) {
+			t.Errorf("JAMError should match ErrJammed")
+		}
+
+		if !cpu.isJammed || cpu.programCounter != 0x0200 {
+			t.Errorf("CPU should be jammed at the opcode")
+		}
+
+		if _, err := cpu.Step(); err != ErrJammed || cpu.xRegister != 0 {
+			t.Errorf("CPU should stay jammed, got %v", err)
+		}
+	})
+
+	t.Run("Skip as a NOP", func(t *testing.T) {
+		cpu := newJAMCPU(WithJAMPolicy(SkipJAM))
+
+		cycles, err := cpu.Step()
+
+		if err != nil || cycles != 2 || cpu.isJammed || cpu.programCounter != 0x0201 {
+			t.Errorf("Opcode should be skipped in 2 cycles, got %d cycles and %v", cycles, err)
+		}
+	})
+
+	t.Run("Call the hook", func(t *testing.T) {
+		var hooked JAMError
+		cpu := newJAMCPU(WithJAMHook(func(c *CPU, err *JAMError) error {
+			hooked = *err
+			c.xRegister = 0x37
+
+			return nil
+		}))
+
+		_, err := cpu.Step()
+
+		if err != nil || hooked != expected {
+			t.Errorf("Hook should be called with %+v, got %+v", expected, hooked)
+		}
+
+		if cpu.isJammed || cpu.xRegister != 0x37 || cpu.programCounter != 0x0201 {
+			t.Errorf("CPU should carry on after the opcode")
+		}
+	})
+
+	t.Run("Return the error of the hook", func(t *testing.T) {
+		stop := errors.New("stop")
+		cpu := newJAMCPU(WithJAMHook(func(c *CPU, err *JAMError) error {
+			return stop
+		}))
+
+		if _, err := cpu.Step(); err != stop {
+			t.Errorf("Step should return the error of the hook, got %v", err)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		cpu := newJAMCPU(WithJAMPolicy(PanicOnJAM))
+
+		defer func() {
+			err, ok := recover().(*JAMError)
+			if !ok || *err != expected {
+				t.Errorf("CPU should panic with %+v, got %v", expected, err)
+			}
+		}()
+
+		cpu.Step()
+	})
+}
+
+func TestRunReturnsJAMError(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0x0000] = InstructionAsHex("INX")
+	cpu.ram[0x0001] = 0x02
+
+	err := cpu.Run()
+
+	var jamErr *JAMError
+	if !errors.As(err, &jamErr) || jamErr.Address != 0x0001 {
+		t.Errorf("Run should stop with an error at 0x0001, got %v", err)
+	}
+
+	cpu = NewCPU(WithJAMPolicy(SkipJAM))
+	cpu.ram[0x0000] = 0x02
+	cpu.ram[0x0001] = InstructionAsHex("INX")
+
+	if err := cpu.Run(); err != nil || cpu.xRegister != 0x01 {
+		t.Errorf("Run should skip the opcode and stop at BRK, got %v", err)
+	}
+}
+
+func TestJAMErrorMessage(t *testing.T) {
+	err := &JAMError{Opcode: 0x02, Address: 0xC000, Cycle: 42}
+
+	if err.Error() != "cpu6510: JAM opcode $02 at $C000 (cycle 42)" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
@@ -0,0 +1,48 @@
+package cpu6510
+
+import "context"
+
+// RunCycles executes whole instructions until at least the given number of
+// clock cycles have passed, and returns the number of cycles it ran. The last
+// instruction may run past the budget, which the caller can carry over to the
+// next call. It stops early with the error of Step.
+func (c *CPU) RunCycles(cycles uint64) (uint64, error) {
+	start := c.cycles
+
+	for c.cycles-start < cycles {
+		if _, err := c.Step(); err != nil {
+			return c.cycles - start, err
+		}
+	}
+
+	return c.cycles - start, nil
+}
+
+// RunUntil executes instructions until the condition holds for the state of
+// the CPU, which is checked before each instruction. It stops early with the
+// error of Step.
+func (c *CPU) RunUntil(condition func(State) bool) error {
+	for !condition(c.State()) {
+		if _, err := c.Step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunContext executes instructions until the context is cancelled, and then
+// returns the error of the context. It stops early with the error of Step.
+func (c *CPU) RunContext(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if _, err := c.Step(); err != nil {
+			return err
+		}
+	}
+}
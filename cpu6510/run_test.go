@@ -0,0 +1,114 @@
+package cpu6510
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newLoopCPU returns a CPU that runs INX and JMP $0000 forever, 5 cycles per
+// iteration.
+func newLoopCPU() *CPU {
+	cpu := NewCPU()
+	cpu.ram[0x0000] = InstructionAsHex("INX")
+	cpu.ram[0x0001] = InstructionAsHex("JMPAbsolute")
+	cpu.ram[0x0002] = 0x00
+	cpu.ram[0x0003] = 0x00
+
+	return cpu
+}
+
+func TestRunCycles(t *testing.T) {
+	t.Run("Run whole instructions", func(t *testing.T) {
+		cpu := newLoopCPU()
+
+		cycles, err := cpu.RunCycles(10)
+
+		if err != nil || cycles != 10 || cpu.xRegister != 2 {
+			t.Errorf("CPU should run two iterations in 10 cycles, got %d cycles and X=%d", cycles, cpu.xRegister)
+		}
+	})
+
+	t.Run("Run past the budget to finish the instruction", func(t *testing.T) {
+		cpu := newLoopCPU()
+
+		cycles, _ := cpu.RunCycles(3)
+
+		if cycles != 5 || cpu.programCounter != 0x0000 {
+			t.Errorf("CPU should finish the JMP in 5 cycles, got %d cycles", cycles)
+		}
+	})
+
+	t.Run("Stop when the CPU jams", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.ram[0x0000] = InstructionAsHex("INX")
+		cpu.ram[0x0001] = InstructionAsHex("JAM")
+
+		cycles, err := cpu.RunCycles(100)
+
+		var jamErr *JAMError
+		if !errors.As(err, &jamErr) || cycles != 2 {
+			t.Errorf("RunCycles should stop at the JAM after 2 cycles, got %d cycles and %v", cycles, err)
+		}
+	})
+}
+
+func TestRunUntil(t *testing.T) {
+	t.Run("Stop when the condition holds", func(t *testing.T) {
+		cpu := newLoopCPU()
+
+		err := cpu.RunUntil(func(s State) bool { return s.X == 0x10 })
+
+		if err != nil || cpu.xRegister != 0x10 || cpu.programCounter != 0x0001 {
+			t.Errorf("CPU should stop right after X becomes 0x10, got X=%d and PC=%04X", cpu.xRegister, cpu.programCounter)
+		}
+	})
+
+	t.Run("Do nothing when the condition already holds", func(t *testing.T) {
+		cpu := newLoopCPU()
+
+		cpu.RunUntil(func(s State) bool { return s.PC == 0x0000 })
+
+		if cpu.cycles != 0 {
+			t.Errorf("CPU should not execute any instruction")
+		}
+	})
+
+	t.Run("Stop when the CPU is jammed", func(t *testing.T) {
+		cpu := NewCPU()
+		cpu.isJammed = true
+
+		if err := cpu.RunUntil(func(State) bool { return false }); err != ErrJammed {
+			t.Errorf("RunUntil should return ErrJammed, got %v", err)
+		}
+	})
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("Stop when cancelled", func(t *testing.T) {
+		cpu := newLoopCPU()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := cpu.RunContext(ctx)
+
+		if err != context.DeadlineExceeded {
+			t.Errorf("RunContext should return the error of the context, got %v", err)
+		}
+
+		if cpu.cycles == 0 {
+			t.Errorf("CPU should have run until the deadline")
+		}
+	})
+
+	t.Run("Do nothing when already cancelled", func(t *testing.T) {
+		cpu := newLoopCPU()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := cpu.RunContext(ctx); err != context.Canceled || cpu.cycles != 0 {
+			t.Errorf("RunContext should return at once, got %v", err)
+		}
+	})
+}
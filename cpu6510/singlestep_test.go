@@ -0,0 +1,230 @@
+package cpu6510
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The SingleStepTests for the 6502 (https://github.com/SingleStepTests/65x02)
+// are JSON files named after the opcode, like a9.json, each with thousands of
+// random initial states, the final state after a single instruction, and the
+// bus access made on every cycle. The files from the 6502/v1 directory go in
+// testdata/singlestep.
+const singleStepDirectory = "testdata/singlestep"
+
+// The number of failing vectors that are reported in detail for each opcode.
+const singleStepReportedFailures = 3
+
+// vectorState is the state of the CPU and the RAM before or after a test
+// vector, where the RAM only has the addresses the instruction touches.
+type vectorState struct {
+	PC  uint16      `json:"pc"`
+	S   byte        `json:"s"`
+	A   byte        `json:"a"`
+	X   byte        `json:"x"`
+	Y   byte        `json:"y"`
+	P   byte        `json:"p"`
+	RAM [][2]uint16 `json:"ram"`
+}
+
+// vectorCycle is a bus access of a test vector, stored as [address, value,
+// "read"] or [address, value, "write"].
+type vectorCycle BusCycle
+
+func (v *vectorCycle) UnmarshalJSON(data []byte) error {
+	var fields [3]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	address, ok1 := fields[0].(float64)
+	value, ok2 := fields[1].(float64)
+	kind, ok3 := fields[2].(string)
+	if !ok1 || !ok2 || !ok3 || (kind != "read" && kind != "write") {
+		return fmt.Errorf("invalid bus cycle %s", data)
+	}
+
+	*v = vectorCycle{Address: uint16(address), Value: byte(value), Write: kind == "write"}
+
+	return nil
+}
+
+func (v vectorCycle) String() string {
+	kind := "read"
+	if v.Write {
+		kind = "write"
+	}
+
+	return fmt.Sprintf("%s $%02X at $%04X", kind, v.Value, v.Address)
+}
+
+// testVector is a single test of the SingleStepTests.
+type testVector struct {
+	Name    string        `json:"name"`
+	Initial vectorState   `json:"initial"`
+	Final   vectorState   `json:"final"`
+	Cycles  []vectorCycle `json:"cycles"`
+}
+
+// recordingBus is flat RAM that records every bus access.
+type recordingBus struct {
+	ram    *RAM
+	cycles []vectorCycle
+}
+
+func (b *recordingBus) Read(address uint16) byte {
+	value := b.ram.Read(address)
+	b.cycles = append(b.cycles, vectorCycle{Address: address, Value: value})
+
+	return value
+}
+
+func (b *recordingBus) Write(address uint16, value byte) {
+	b.ram.Write(address, value)
+	b.cycles = append(b.cycles, vectorCycle{Address: address, Value: value, Write: true})
+}
+
+// runTestVector sets up the CPU with the initial state of the vector, executes
+// a single instruction, and returns the differences from the final state.
+func runTestVector(vector testVector) []string {
+	cpu := NewCPU()
+	bus := &recordingBus{ram: cpu.ram}
+	cpu.bus = bus
+
+	cpu.SetState(State{
+		A:  vector.Initial.A,
+		X:  vector.Initial.X,
+		Y:  vector.Initial.Y,
+		SP: vector.Initial.S,
+		PC: vector.Initial.PC,
+		P:  vector.Initial.P,
+	})
+	for _, entry := range vector.Initial.RAM {
+		cpu.ram[entry[0]] = byte(entry[1])
+	}
+
+	var differences []string
+
+	if _, err := cpu.Step(); err != nil {
+		differences = append(differences, err.Error())
+	}
+
+	// The vectors keep the B flag in P, but the CPU only has it in the copies
+	// of P pushed by PHP and BRK, and always reads the unused bit as set.
+	state := cpu.State()
+	registers := []struct {
+		name     string
+		got      uint16
+		expected uint16
+	}{
+		{"PC", state.PC, vector.Final.PC},
+		{"S", uint16(state.SP), uint16(vector.Final.S)},
+		{"A", uint16(state.A), uint16(vector.Final.A)},
+		{"X", uint16(state.X), uint16(vector.Final.X)},
+		{"Y", uint16(state.Y), uint16(vector.Final.Y)},
+		{"P", uint16(state.P), uint16(vector.Final.P&^0x10 | 0x20)},
+	}
+	for _, r := range registers {
+		if r.got != r.expected {
+			differences = append(differences, fmt.Sprintf("%s should be $%02X, got $%02X", r.name, r.expected, r.got))
+		}
+	}
+
+	for _, entry := range vector.Final.RAM {
+		if got := cpu.ram[entry[0]]; got != byte(entry[1]) {
+			differences = append(differences, fmt.Sprintf("RAM at $%04X should be $%02X, got $%02X", entry[0], entry[1], got))
+		}
+	}
+
+	if state.Cycles != uint64(len(vector.Cycles)) {
+		differences = append(differences, fmt.Sprintf("instruction should take %d cycles, got %d", len(vector.Cycles), state.Cycles))
+	}
+
+	for i := 0; i < max(len(bus.cycles), len(vector.Cycles)); i++ {
+		switch {
+		case i >= len(bus.cycles):
+			differences = append(differences, fmt.Sprintf("cycle %d should %s, got nothing", i, vector.Cycles[i]))
+		case i >= len(vector.Cycles):
+			differences = append(differences, fmt.Sprintf("cycle %d should not access the bus, got %s", i, bus.cycles[i]))
+		case bus.cycles[i] != vector.Cycles[i]:
+			differences = append(differences, fmt.Sprintf("cycle %d should %s, got %s", i, vector.Cycles[i], bus.cycles[i]))
+		}
+	}
+
+	return differences
+}
+
+// readTestVectors reads the test vectors from the file.
+func readTestVectors(path string) ([]testVector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var vectors []testVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return vectors, nil
+}
+
+func TestSingleStep(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping single step tests in short mode")
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(singleStepDirectory, "*.json"))
+	if len(paths) == 0 {
+		t.Skipf("no test vectors in %s, see testdata/README.md", singleStepDirectory)
+	}
+	sort.Strings(paths)
+
+	var summary []string
+
+	for _, path := range paths {
+		var opcode byte
+		if _, err := fmt.Sscanf(filepath.Base(path), "%02x.json", &opcode); err != nil {
+			continue
+		}
+
+		// The JAM opcodes lock up the CPU, so their bus activity goes on
+		// forever.
+		if isJAMOpcode(opcode) {
+			continue
+		}
+
+		t.Run(fmt.Sprintf("%02X", opcode), func(t *testing.T) {
+			vectors, err := readTestVectors(path)
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+
+			failed := 0
+			for _, vector := range vectors {
+				differences := runTestVector(vector)
+				if len(differences) == 0 {
+					continue
+				}
+
+				failed++
+				if failed <= singleStepReportedFailures {
+					t.Errorf("%s: %s", vector.Name, strings.Join(differences, "; "))
+				}
+			}
+
+			if failed > 0 {
+				summary = append(summary, fmt.Sprintf("$%02X %-4s %d of %d failed", opcode, opcodes[opcode].mnemonic, failed, len(vectors)))
+			}
+		})
+	}
+
+	if len(summary) > 0 {
+		t.Errorf("%d opcodes failed:\n%s", len(summary), strings.Join(summary, "\n"))
+	}
+}
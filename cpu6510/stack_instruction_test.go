@@ -126,13 +126,13 @@ func TestPHP(t *testing.T) {
 		}
 	})
 
-	t.Run("Push processor status register flags when all is cleared", func(t *testing.T) {
+	t.Run("Push processor status register flags with the B flag set when all is cleared", func(t *testing.T) {
 		cpu := NewCPU()
 		expectedPC := cpu.programCounter + 1
 
 		cpu.execute(InstructionAsHex("PHP"))
 
-		if cpu.ram[0x01FF] != 0x20 {
+		if cpu.ram[0x01FF] != 0x30 {
 			t.Errorf("Status register should be pushed onto the stack")
 		}
 
@@ -216,8 +216,12 @@ func TestPLP(t *testing.T) {
 			t.Errorf("Decimal mode flag should be set")
 		}
 
-		if !cpu.statusRegister.breakCommandFlag {
-			t.Errorf("Break command flag should be set")
+		if cpu.statusRegister.breakCommandFlag {
+			t.Errorf("Break command flag should not be pulled from the stack")
+		}
+
+		if cpu.Status() != 0xEF {
+			t.Errorf("Status register should be 0xEF, got 0x%02X", cpu.Status())
 		}
 
 		if !cpu.statusRegister.overflowFlag {
@@ -0,0 +1,92 @@
+package cpu6510
+
+// State is a snapshot of the registers of the CPU6510, which can be saved and
+// restored with State and SetState.
+type State struct {
+	// The accumulator.
+	A byte
+	// The X index register.
+	X byte
+	// The Y index register.
+	Y byte
+	// The stack pointer.
+	SP byte
+	// The program counter.
+	PC uint16
+	// The status register, NV-BDIZC from the highest bit to the lowest.
+	P byte
+	// The number of clock cycles the CPU has executed.
+	Cycles uint64
+	// True when the CPU is jammed.
+	Jammed bool
+}
+
+// State returns a snapshot of the registers of the CPU.
+func (c *CPU) State() State {
+	return State{
+		A:      c.accumulator,
+		X:      c.xRegister,
+		Y:      c.yRegister,
+		SP:     c.stackPointer,
+		PC:     c.programCounter,
+		P:      c.Status(),
+		Cycles: c.cycles,
+		Jammed: c.isJammed,
+	}
+}
+
+// SetState restores the registers of the CPU from the snapshot. When the CPU
+// is driven by Tick, SetState has to be called between instructions.
+func (c *CPU) SetState(state State) {
+	c.accumulator = state.A
+	c.xRegister = state.X
+	c.yRegister = state.Y
+	c.stackPointer = state.SP
+	c.programCounter = state.PC
+	c.SetStatus(state.P)
+	c.cycles = state.Cycles
+	c.isJammed = state.Jammed
+}
+
+// A returns the accumulator.
+func (c *CPU) A() byte {
+	return c.accumulator
+}
+
+// X returns the X index register.
+func (c *CPU) X() byte {
+	return c.xRegister
+}
+
+// Y returns the Y index register.
+func (c *CPU) Y() byte {
+	return c.yRegister
+}
+
+// SP returns the stack pointer.
+func (c *CPU) SP() byte {
+	return c.stackPointer
+}
+
+// PC returns the program counter.
+func (c *CPU) PC() uint16 {
+	return c.programCounter
+}
+
+// SetPC sets the program counter.
+func (c *CPU) SetPC(address uint16) {
+	c.programCounter = address
+}
+
+// Status returns the status register as a byte, NV-BDIZC from the highest bit
+// to the lowest.
+func (c *CPU) Status() byte {
+	return c.statusRegister.asByte()
+}
+
+// SetStatus sets the status register from a byte, NV-BDIZC from the highest
+// bit to the lowest. Like PLP, it clears the B flag and sets the unused bit,
+// which always read that way.
+func (c *CPU) SetStatus(value byte) {
+	c.statusRegister = loadStatusRegister(value)
+}
@@ -0,0 +1,88 @@
+package cpu6510
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	cpu := NewCPU()
+	cpu.accumulator = 0x01
+	cpu.xRegister = 0x02
+	cpu.yRegister = 0x03
+	cpu.stackPointer = 0xFD
+	cpu.programCounter = 0xC000
+	cpu.statusRegister.carryFlag = true
+	cpu.statusRegister.negativeFlag = true
+	cpu.cycles = 1234
+
+	expected := State{A: 0x01, X: 0x02, Y: 0x03, SP: 0xFD, PC: 0xC000, P: 0xA1, Cycles: 1234}
+
+	if cpu.State() != expected {
+		t.Errorf("State should be %+v, got %+v", expected, cpu.State())
+	}
+}
+
+func TestSetState(t *testing.T) {
+	cpu := NewCPU()
+	state := State{A: 0x10, X: 0x20, Y: 0x30, SP: 0xF0, PC: 0x1337, P: 0x6B, Cycles: 99, Jammed: true}
+
+	cpu.SetState(state)
+
+	if cpu.A() != 0x10 || cpu.X() != 0x20 || cpu.Y() != 0x30 || cpu.SP() != 0xF0 || cpu.PC() != 0x1337 {
+		t.Errorf("Registers should be restored, got %+v", cpu.State())
+	}
+
+	sr := cpu.statusRegister
+	if !sr.carryFlag || !sr.zeroFlag || sr.interruptDisableFlag || !sr.decimalModeFlag || !sr.overflowFlag || sr.negativeFlag {
+		t.Errorf("Flags should be restored from 0x6B, got 0x%02X", cpu.Status())
+	}
+
+	if cpu.Cycles() != 99 || !cpu.isJammed {
+		t.Errorf("Cycles and jammed state should be restored")
+	}
+
+	if cpu.State() != state {
+		t.Errorf("State should round trip, got %+v", cpu.State())
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for value := 0; value < 256; value++ {
+		cpu := NewCPU()
+
+		cpu.SetStatus(byte(value))
+
+		// The B flag is cleared, and the unused bit set.
+		expected := byte(value)&^0x10 | 0x20
+		if cpu.Status() != expected {
+			t.Errorf("Status should be 0x%02X, got 0x%02X", expected, cpu.Status())
+		}
+	}
+}
+
+func TestSetPC(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0xC000] = InstructionAsHex("INX")
+
+	cpu.SetPC(0xC000)
+	cpu.Step()
+
+	if cpu.X() != 0x01 || cpu.PC() != 0xC001 {
+		t.Errorf("CPU should execute from the new program counter")
+	}
+}
+
+func TestStateIsSerialisable(t *testing.T) {
+	state := State{A: 0xFF, SP: 0xFF, PC: 0xFCE2, P: 0x24, Cycles: 1 << 40}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("State should marshal, got %v", err)
+	}
+
+	var decoded State
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != state {
+		t.Errorf("State should round trip through JSON, got %+v", decoded)
+	}
+}
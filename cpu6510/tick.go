@@ -0,0 +1,185 @@
+package cpu6510
+
+// BusCycle describes the memory access the CPU made on a clock cycle. The
+// 6510 accesses the bus on every cycle, so a cycle is either a read or a
+// write.
+type BusCycle struct {
+	// The address on the address bus.
+	Address uint16
+	// The byte that was read or written.
+	Value byte
+	// True for a write cycle, false for a read cycle.
+	Write bool
+}
+
+// ticker runs an instruction one bus access at a time, on the goroutine that
+// calls Tick. Each Tick runs the instruction again from the registers it
+// started with, replaying the bus accesses of the earlier cycles from a log,
+// and makes the next access on the bus. The rest of the run is thrown away,
+// and the registers are left as they were at that point. An instruction only
+// depends on its registers and what it reads, so every run takes the same
+// path, and every access reaches the bus once, on its own cycle.
+type ticker struct {
+	// The bus accesses the instruction has made so far, one per cycle.
+	log []BusCycle
+	// The number of bus accesses of the current run so far.
+	pos int
+	// True once the current run has made its access on the bus.
+	accessed bool
+	// True when the current run has gone past its access on the bus, so the
+	// instruction has not completed yet.
+	more bool
+	// The registers when the instruction started, and where the current run
+	// got to before its next access after the one on the bus.
+	start   registers
+	partial registers
+	// The cycle counter when the instruction started.
+	startCycle uint64
+	// The interrupt that is entered instead of an instruction, if any.
+	vector    uint16
+	interrupt bool
+}
+
+// registers is a copy of the registers of the CPU.
+type registers struct {
+	accumulator    byte
+	xRegister      byte
+	yRegister      byte
+	stackPointer   byte
+	programCounter uint16
+	statusRegister StatusRegister
+	isJammed       bool
+}
+
+// registers returns a copy of the registers.
+func (c *CPU) registers() registers {
+	return registers{
+		accumulator:    c.accumulator,
+		xRegister:      c.xRegister,
+		yRegister:      c.yRegister,
+		stackPointer:   c.stackPointer,
+		programCounter: c.programCounter,
+		statusRegister: c.statusRegister,
+		isJammed:       c.isJammed,
+	}
+}
+
+// setRegisters restores the registers from the copy.
+func (c *CPU) setRegisters(r registers) {
+	c.accumulator = r.accumulator
+	c.xRegister = r.xRegister
+	c.yRegister = r.yRegister
+	c.stackPointer = r.stackPointer
+	c.programCounter = r.programCounter
+	c.statusRegister = r.statusRegister
+	c.isJammed = r.isJammed
+}
+
+// skip returns true, with the value to read, when the next bus access of the
+// instruction is not made on the bus. That is an access of an earlier cycle,
+// which is replayed from the log, or an access after the one of the current
+// cycle, which is thrown away.
+func (t *ticker) skip(c *CPU) (byte, bool) {
+	if t.pos < len(t.log) {
+		value := t.log[t.pos].Value
+		t.pos++
+
+		return value, true
+	}
+
+	if !t.accessed {
+		t.accessed = true
+
+		return 0, false
+	}
+
+	if !t.more {
+		t.more = true
+		t.partial = c.registers()
+	}
+
+	return 0, true
+}
+
+// record logs the bus access of the current cycle.
+func (t *ticker) record(cycle BusCycle) {
+	t.log = append(t.log, cycle)
+	t.pos++
+}
+
+// Tick advances the CPU by a single clock cycle and returns the memory access
+// the CPU made on that cycle, including the dummy reads and writes that the
+// whole instruction execution performs as well. Pending interrupts are taken
+// between instructions. An instruction that was started by Tick has to be
+// completed by Tick, or abandoned by Close, before Run is used again. The
+// error of a JAM opcode is returned by TickError once the instruction has
+// completed, and a panic of the PanicOnJAM policy ends the instruction.
+func (c *CPU) Tick() BusCycle {
+	if c.isJammed {
+		c.cycles++
+
+		return BusCycle{}
+	}
+
+	t := c.ticker
+	if t == nil {
+		t = &c.tickerState
+		t.log = t.log[:0]
+		t.start = c.registers()
+		t.startCycle = c.cycles
+		t.vector, t.interrupt = c.pendingInterrupt()
+		c.ticker = t
+		c.tickErr = nil
+	} else {
+		c.setRegisters(t.start)
+	}
+
+	// The instruction is traced when it starts, not every time it runs again.
+	tracer := c.tracer
+	if len(t.log) > 0 {
+		c.tracer = nil
+	}
+
+	t.pos = 0
+	t.accessed = false
+	t.more = false
+	c.cycles = t.startCycle
+
+	var err error
+
+	defer func() {
+		c.tracer = tracer
+
+		// The instruction adds its own cycle count when it completes, but
+		// while ticking the counter advances one cycle at a time instead.
+		c.cycles = t.startCycle + uint64(len(t.log))
+
+		if t.more {
+			c.setRegisters(t.partial)
+		} else {
+			c.ticker = nil
+			c.tickErr = err
+		}
+	}()
+
+	if t.interrupt {
+		c.takeInterrupt(t.vector)
+	} else {
+		err = c.execute(c.next())
+	}
+
+	return t.log[len(t.log)-1]
+}
+
+// TickError returns the error of the last instruction that Tick has
+// completed, like a *JAMError, or nil when there was none.
+func (c *CPU) TickError() error {
+	return c.tickErr
+}
+
+// Close abandons the instruction that Tick has started but not completed.
+// The instruction is left half done, so the state of the CPU is only good for
+// a Reset. Close does nothing when no instruction is in progress.
+func (c *CPU) Close() {
+	c.ticker = nil
+}
@@ -0,0 +1,500 @@
+package cpu6510
+
+import (
+	"errors"
+	"testing"
+)
+
+// tickInstruction ticks the CPU until the current instruction has completed,
+// and returns the bus access made on each cycle.
+func tickInstruction(cpu *CPU) []BusCycle {
+	var cycles []BusCycle
+
+	for {
+		cycles = append(cycles, cpu.Tick())
+
+		if cpu.ticker == nil {
+			return cycles
+		}
+	}
+}
+
+// read and write return the expected bus access of a single cycle.
+func read(address uint16, value byte) BusCycle {
+	return BusCycle{Address: address, Value: value}
+}
+
+func write(address uint16, value byte) BusCycle {
+	return BusCycle{Address: address, Value: value, Write: true}
+}
+
+func TestTickBusCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(cpu *CPU)
+		expected []BusCycle
+	}{
+		{"LDA #$42", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xA9, 0x42})
+		}, []BusCycle{
+			read(0x0200, 0xA9), read(0x0201, 0x42),
+		}},
+		{"CLC", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x18, 0xEA})
+		}, []BusCycle{
+			read(0x0200, 0x18), read(0x0201, 0xEA),
+		}},
+		{"ASL $1337", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x0E, 0x37, 0x13})
+			cpu.ram[0x1337] = 0x41
+		}, []BusCycle{
+			read(0x0200, 0x0E), read(0x0201, 0x37), read(0x0202, 0x13),
+			read(0x1337, 0x41), write(0x1337, 0x41), write(0x1337, 0x82),
+		}},
+		{"ROL $12FE,X across a page", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x3E, 0xFE, 0x12})
+			cpu.xRegister = 0x02
+			cpu.ram[0x1200] = 0x11
+			cpu.ram[0x1300] = 0x81
+		}, []BusCycle{
+			read(0x0200, 0x3E), read(0x0201, 0xFE), read(0x0202, 0x12),
+			read(0x1200, 0x11), read(0x1300, 0x81), write(0x1300, 0x81),
+			write(0x1300, 0x02),
+		}},
+		{"LDA $12FE,X", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xBD, 0xFE, 0x12})
+			cpu.xRegister = 0x01
+			cpu.ram[0x12FF] = 0x42
+		}, []BusCycle{
+			read(0x0200, 0xBD), read(0x0201, 0xFE), read(0x0202, 0x12),
+			read(0x12FF, 0x42),
+		}},
+		{"LDA $12FE,X across a page", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xBD, 0xFE, 0x12})
+			cpu.xRegister = 0x02
+			cpu.ram[0x1200] = 0x11
+			cpu.ram[0x1300] = 0x42
+		}, []BusCycle{
+			read(0x0200, 0xBD), read(0x0201, 0xFE), read(0x0202, 0x12),
+			read(0x1200, 0x11), read(0x1300, 0x42),
+		}},
+		{"STA $12FE,X", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x9D, 0xFE, 0x12})
+			cpu.accumulator = 0x42
+			cpu.xRegister = 0x01
+			cpu.ram[0x12FF] = 0x11
+		}, []BusCycle{
+			read(0x0200, 0x9D), read(0x0201, 0xFE), read(0x0202, 0x12),
+			read(0x12FF, 0x11), write(0x12FF, 0x42),
+		}},
+		{"LDA ($80),Y across a page", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xB1, 0x80})
+			cpu.yRegister = 0x02
+			cpu.ram[0x80] = 0xFE
+			cpu.ram[0x81] = 0x12
+			cpu.ram[0x1200] = 0x11
+			cpu.ram[0x1300] = 0x42
+		}, []BusCycle{
+			read(0x0200, 0xB1), read(0x0201, 0x80), read(0x0080, 0xFE),
+			read(0x0081, 0x12), read(0x1200, 0x11), read(0x1300, 0x42),
+		}},
+		{"STA ($80,X)", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x81, 0x80})
+			cpu.accumulator = 0x42
+			cpu.xRegister = 0x01
+			cpu.ram[0x80] = 0x11
+			cpu.ram[0x81] = 0x37
+			cpu.ram[0x82] = 0x13
+		}, []BusCycle{
+			read(0x0200, 0x81), read(0x0201, 0x80), read(0x0080, 0x11),
+			read(0x0081, 0x37), read(0x0082, 0x13), write(0x1337, 0x42),
+		}},
+		{"INC $80,X", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xF6, 0x80})
+			cpu.xRegister = 0x01
+			cpu.ram[0x80] = 0x11
+			cpu.ram[0x81] = 0x41
+		}, []BusCycle{
+			read(0x0200, 0xF6), read(0x0201, 0x80), read(0x0080, 0x11),
+			read(0x0081, 0x41), write(0x0081, 0x41), write(0x0081, 0x42),
+		}},
+		{"PHA", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x48, 0xEA})
+			cpu.accumulator = 0x42
+		}, []BusCycle{
+			read(0x0200, 0x48), read(0x0201, 0xEA), write(0x01FF, 0x42),
+		}},
+		{"PLA", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x68, 0xEA})
+			cpu.stackPointer = 0xFE
+			cpu.ram[0x01FE] = 0x11
+			cpu.ram[0x01FF] = 0x42
+		}, []BusCycle{
+			read(0x0200, 0x68), read(0x0201, 0xEA), read(0x01FE, 0x11),
+			read(0x01FF, 0x42),
+		}},
+		{"JSR $1337", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x20, 0x37, 0x13})
+			cpu.ram[0x01FF] = 0x11
+		}, []BusCycle{
+			read(0x0200, 0x20), read(0x0201, 0x37), read(0x01FF, 0x11),
+			write(0x01FF, 0x02), write(0x01FE, 0x02), read(0x0202, 0x13),
+		}},
+		{"RTS", func(cpu *CPU) {
+			cpu.programCounter = 0x1337
+			copy(cpu.ram[0x1337:], []byte{0x60, 0xEA})
+			cpu.stackPointer = 0xFD
+			cpu.ram[0x01FD] = 0x11
+			cpu.ram[0x01FE] = 0x02
+			cpu.ram[0x01FF] = 0x02
+			cpu.ram[0x0202] = 0x13
+		}, []BusCycle{
+			read(0x1337, 0x60), read(0x1338, 0xEA), read(0x01FD, 0x11),
+			read(0x01FE, 0x02), read(0x01FF, 0x02), read(0x0202, 0x13),
+		}},
+		{"JMP ($12FF)", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x6C, 0xFF, 0x12})
+			cpu.ram[0x12FF] = 0x37
+			cpu.ram[0x1200] = 0x13
+		}, []BusCycle{
+			read(0x0200, 0x6C), read(0x0201, 0xFF), read(0x0202, 0x12),
+			read(0x12FF, 0x37), read(0x1200, 0x13),
+		}},
+		{"BRK", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0x00, 0xFF})
+			cpu.ram[0xFFFE] = 0x37
+			cpu.ram[0xFFFF] = 0x13
+		}, []BusCycle{
+			read(0x0200, 0x00), read(0x0201, 0xFF), write(0x01FF, 0x02),
+			write(0x01FE, 0x02), write(0x01FD, 0x30), read(0xFFFE, 0x37),
+			read(0xFFFF, 0x13),
+		}},
+		{"BNE not taken", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xD0, 0x20})
+			cpu.statusRegister.zeroFlag = true
+		}, []BusCycle{
+			read(0x0200, 0xD0), read(0x0201, 0x20),
+		}},
+		{"BNE taken", func(cpu *CPU) {
+			copy(cpu.ram[0x0200:], []byte{0xD0, 0x20, 0xEA})
+		}, []BusCycle{
+			read(0x0200, 0xD0), read(0x0201, 0x20), read(0x0202, 0xEA),
+		}},
+		{"BNE taken across a page", func(cpu *CPU) {
+			cpu.programCounter = 0x02F0
+			copy(cpu.ram[0x02F0:], []byte{0xD0, 0x20, 0xEA})
+			cpu.ram[0x0212] = 0x11
+		}, []BusCycle{
+			read(0x02F0, 0xD0), read(0x02F1, 0x20), read(0x02F2, 0xEA),
+			read(0x0212, 0x11),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := NewCPU()
+			cpu.programCounter = 0x0200
+			test.setup(cpu)
+
+			cycles := tickInstruction(cpu)
+
+			if len(cycles) != len(test.expected) {
+				t.Fatalf("Instruction should take %d cycles, got %d: %v", len(test.expected), len(cycles), cycles)
+			}
+
+			for i, cycle := range cycles {
+				if cycle != test.expected[i] {
+					t.Errorf("Cycle %d should be %+v, got %+v", i+1, test.expected[i], cycle)
+				}
+			}
+
+			if cpu.Cycles() != uint64(len(test.expected)) {
+				t.Errorf("Cycle counter should be %d, got %d", len(test.expected), cpu.Cycles())
+			}
+		})
+	}
+}
+
+// copyCPU returns a copy of the CPU with a copy of its RAM.
+func copyCPU(cpu *CPU) *CPU {
+	ram := *cpu.ram
+
+	c := *cpu
+	c.ram = &ram
+	c.bus = &ram
+
+	return &c
+}
+
+// sameRegisters returns true if the registers of both CPUs are equal.
+func sameRegisters(a, b *CPU) bool {
+	return a.programCounter == b.programCounter &&
+		a.statusRegister == b.statusRegister &&
+		a.accumulator == b.accumulator &&
+		a.xRegister == b.xRegister &&
+		a.yRegister == b.yRegister &&
+		a.stackPointer == b.stackPointer &&
+		a.isJammed == b.isJammed
+}
+
+func TestTickMatchesExecute(t *testing.T) {
+	setups := []struct {
+		name  string
+		setup func(cpu *CPU)
+	}{
+		{"No page crossing, flags cleared", func(cpu *CPU) {
+			cpu.xRegister = 0x01
+			cpu.yRegister = 0x01
+		}},
+		{"Page crossing, flags set", func(cpu *CPU) {
+			cpu.xRegister = 0xFF
+			cpu.yRegister = 0xFF
+			cpu.statusRegister = newStatusRegister(0b11000011)
+		}},
+	}
+
+	for opcode := range expectedCycles {
+		if expectedCycles[opcode] == 0 {
+			continue
+		}
+
+		for _, setup := range setups {
+			executed := NewCPU()
+			for i := range executed.ram {
+				executed.ram[i] = byte(i*7 + i>>8)
+			}
+			executed.programCounter = 0x0280
+			executed.ram[0x0280] = byte(opcode)
+			executed.stackPointer = 0xF0
+			setup.setup(executed)
+			ticked := copyCPU(executed)
+
+			executed.execute(executed.next())
+			cycles := tickInstruction(ticked)
+
+			if uint64(len(cycles)) != executed.Cycles() {
+				t.Errorf("Opcode 0x%02X (%s) should take %d cycles when ticked, got %d", opcode, setup.name, executed.Cycles(), len(cycles))
+			}
+
+			if *ticked.ram != *executed.ram || !sameRegisters(ticked, executed) {
+				t.Errorf("Opcode 0x%02X (%s) should leave the same state when ticked", opcode, setup.name)
+			}
+		}
+	}
+}
+
+// newBenchmarkCPU returns a CPU that runs a loop of common instructions:
+//
+//	0200 LDX #$00
+//	0202 INX
+//	0203 STA $1000,X
+//	0206 ADC $80
+//	0208 ASL $81
+//	020A BNE $0202
+//	020C JMP $0200
+func newBenchmarkCPU() *CPU {
+	cpu := NewCPU()
+	cpu.programCounter = 0x0200
+	copy(cpu.ram[0x0200:], []byte{
+		0xA2, 0x00, 0xE8, 0x9D, 0x00, 0x10, 0x65, 0x80,
+		0x06, 0x81, 0xD0, 0xF6, 0x4C, 0x00, 0x02,
+	})
+
+	return cpu
+}
+
+// The benchmarks report the time per clock cycle, so that Tick and Step can
+// be compared.
+func BenchmarkTick(b *testing.B) {
+	cpu := newBenchmarkCPU()
+
+	for i := 0; i < b.N; i++ {
+		cpu.Tick()
+	}
+}
+
+func BenchmarkStep(b *testing.B) {
+	cpu := newBenchmarkCPU()
+
+	for cpu.Cycles() < uint64(b.N) {
+		cpu.Step()
+	}
+}
+
+func TestTickContinuesWithNextInstruction(t *testing.T) {
+	cpu := NewCPU()
+
+	// LDA #$41, ADC #$01, STA $1337
+	copy(cpu.ram[:], []byte{0xA9, 0x41, 0x69, 0x01, 0x8D, 0x37, 0x13})
+
+	for i := 0; i < 2+2+4; i++ {
+		cpu.Tick()
+	}
+
+	if cpu.ram[0x1337] != 0x42 {
+		t.Errorf("Memory at 0x1337 should be 0x42, got 0x%02X", cpu.ram[0x1337])
+	}
+
+	if cpu.programCounter != 0x0007 {
+		t.Errorf("Program counter should be 0x0007, got 0x%04X", cpu.programCounter)
+	}
+
+	if cpu.Cycles() != 8 {
+		t.Errorf("Cycle counter should be 8, got %d", cpu.Cycles())
+	}
+}
+
+func TestTickRegistersBetweenCycles(t *testing.T) {
+	cpu := NewCPU()
+	cpu.programCounter = 0x0200
+	// JSR $1337
+	copy(cpu.ram[0x0200:], []byte{0x20, 0x37, 0x13})
+
+	expected := []struct {
+		pc uint16
+		sp byte
+	}{
+		{0x0201, 0xFF}, {0x0202, 0xFF}, {0x0202, 0xFF}, {0x0202, 0xFE}, {0x0202, 0xFD}, {0x1337, 0xFD},
+	}
+
+	for i, registers := range expected {
+		cpu.Tick()
+
+		if cpu.programCounter != registers.pc || cpu.stackPointer != registers.sp {
+			t.Errorf("After cycle %d PC and SP should be $%04X and $%02X, got $%04X and $%02X", i+1, registers.pc, registers.sp, cpu.programCounter, cpu.stackPointer)
+		}
+	}
+}
+
+func TestTickReadsOnItsOwnCycle(t *testing.T) {
+	cpu := NewCPU()
+	// LDA $1337, STA $1338
+	copy(cpu.ram[:], []byte{0xAD, 0x37, 0x13, 0x8D, 0x38, 0x13})
+	cpu.ram[0x1337] = 0x11
+
+	for i := 0; i < 3; i++ {
+		cpu.Tick()
+	}
+	cpu.ram[0x1337] = 0x42
+	cpu.Tick()
+
+	if cpu.accumulator != 0x42 {
+		t.Errorf("LDA should read memory on its last cycle, got 0x%02X", cpu.accumulator)
+	}
+
+	for i := 0; i < 3; i++ {
+		cpu.Tick()
+	}
+
+	if cpu.ram[0x1338] != 0x00 {
+		t.Errorf("STA should not write memory before its last cycle")
+	}
+
+	cpu.Tick()
+
+	if cpu.ram[0x1338] != 0x42 {
+		t.Errorf("STA should write memory on its last cycle, got 0x%02X", cpu.ram[0x1338])
+	}
+}
+
+func TestTickWhenJammed(t *testing.T) {
+	cpu := NewCPU()
+	cpu.ram[0] = InstructionAsHex("JAM")
+
+	cpu.Tick()
+	cycle := cpu.Tick()
+
+	if !cpu.isJammed {
+		t.Errorf("CPU should be jammed")
+	}
+
+	if cycle != (BusCycle{}) {
+		t.Errorf("Jammed CPU should not access the bus, got %+v", cycle)
+	}
+
+	if cpu.Cycles() != 2 {
+		t.Errorf("Cycle counter should keep counting, got %d", cpu.Cycles())
+	}
+
+	if !errors.Is(cpu.TickError(), ErrJammed) {
+		t.Errorf("TickError should return the JAMError, got %v", cpu.TickError())
+	}
+}
+
+func TestTickJAMPolicy(t *testing.T) {
+	t.Run("Return the error of the hook", func(t *testing.T) {
+		hookErr := errors.New("hook")
+		cpu := NewCPU(WithJAMHook(func(c *CPU, err *JAMError) error {
+			return hookErr
+		}))
+		cpu.ram[0x0000] = 0x02
+
+		tickInstruction(cpu)
+
+		if !errors.Is(cpu.TickError(), hookErr) {
+			t.Errorf("TickError should return the error of the hook, got %v", cpu.TickError())
+		}
+
+		cpu.ram[0x0001] = InstructionAsHex("NOP")
+		tickInstruction(cpu)
+
+		if cpu.TickError() != nil {
+			t.Errorf("TickError should be cleared by the next instruction, got %v", cpu.TickError())
+		}
+	})
+
+	t.Run("Raise the panic on the caller", func(t *testing.T) {
+		cpu := NewCPU(WithJAMPolicy(PanicOnJAM))
+		cpu.ram[0x0000] = 0x02
+
+		defer func() {
+			var jamErr *JAMError
+			if err, ok := recover().(error); !ok || !errors.As(err, &jamErr) {
+				t.Errorf("Tick should panic with the JAMError")
+			}
+
+			if cpu.ticker != nil {
+				t.Errorf("Instruction should be over after the panic")
+			}
+		}()
+
+		cpu.Tick()
+	})
+}
+
+func TestTickClose(t *testing.T) {
+	cpu := NewCPU()
+	// ASL $1337
+	copy(cpu.ram[:], []byte{0x0E, 0x37, 0x13})
+
+	cpu.Tick()
+	cpu.Tick()
+	cpu.Close()
+
+	if cpu.ticker != nil {
+		t.Errorf("Close should abandon the instruction")
+	}
+
+	cpu.ram[0xFFFC] = 0x00
+	cpu.ram[0xFFFD] = 0x02
+	cpu.ram[0x0200] = InstructionAsHex("NOP")
+	cpu.Reset()
+
+	if _, err := cpu.Step(); err != nil || cpu.PC() != 0x0201 {
+		t.Errorf("CPU should run from the reset vector after Close, got %v at $%04X", err, cpu.PC())
+	}
+
+	cpu.Close()
+}
+
+func TestResetAbandonsTick(t *testing.T) {
+	cpu := NewCPU()
+	copy(cpu.ram[:], []byte{0x0E, 0x37, 0x13})
+
+	cpu.Tick()
+	cpu.Reset()
+
+	if cpu.ticker != nil {
+		t.Errorf("Reset should abandon the instruction")
+	}
+}
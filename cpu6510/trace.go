@@ -0,0 +1,74 @@
+package cpu6510
+
+import (
+	"fmt"
+	"io"
+)
+
+// WithTracer turns on tracing to the writer, see SetTracer.
+func WithTracer(w io.Writer) Option {
+	return func(c *CPU) {
+		c.tracer = w
+	}
+}
+
+// SetTracer writes a line to the writer for every instruction before it is
+// executed, in the format of the nestest reference trace without the PPU
+// column:
+//
+//	C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7
+//
+// Undocumented opcodes are marked with a * in front of the mnemonic. Entering
+// an interrupt, or the reset sequence, gets a line of its own, with IRQ, NMI
+// or RESET instead of the instruction and no instruction bytes. Errors from
+// the writer are ignored. A nil writer turns tracing off.
+func (c *CPU) SetTracer(w io.Writer) {
+	c.tracer = w
+}
+
+// trace writes the trace line of the instruction at the program counter.
+// The instruction bytes are read straight from the bus, so that they do not
+// use any clock cycles.
+func (c *CPU) trace() {
+	disassembly := Disassemble(c.bus.Read, c.programCounter)
+
+	io.WriteString(c.tracer, FormatTraceLine(disassembly, c.State()))
+}
+
+// traceInterrupt writes the trace line for entering the interrupt, or the
+// reset sequence, through the vector. It has the name of the interrupt where
+// the instruction would be, so that the jump to the handler shows up.
+func (c *CPU) traceInterrupt(vector uint16) {
+	name := "IRQ"
+	switch vector {
+	case nmiVector:
+		name = "NMI"
+	case resetVector:
+		name = "RESET"
+	}
+
+	disassembly := Disassembly{Address: c.programCounter, Text: name}
+
+	io.WriteString(c.tracer, FormatTraceLine(disassembly, c.State()))
+}
+
+// FormatTraceLine formats the trace line of the instruction, executed with the
+// CPU in the given state, including the trailing newline.
+func FormatTraceLine(disassembly Disassembly, state State) string {
+	code := ""
+	for i, value := range disassembly.Bytes {
+		if i > 0 {
+			code += " "
+		}
+		code += fmt.Sprintf("%02X", value)
+	}
+
+	marker := ' '
+	if disassembly.Undocumented {
+		marker = '*'
+	}
+
+	return fmt.Sprintf("%04X  %-8s %c%-32sA:%02X X:%02X Y:%02X P:%02X SP:%02X CYC:%d\n",
+		disassembly.Address, code, marker, disassembly.Text,
+		state.A, state.X, state.Y, state.P, state.SP, state.Cycles)
+}
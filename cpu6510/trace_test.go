@@ -0,0 +1,105 @@
+package cpu6510
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTraceLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		disassembly Disassembly
+		expected    string
+	}{
+		{
+			"Three bytes",
+			Disassembly{Address: 0xC000, Bytes: []byte{0x4C, 0xF5, 0xC5}, Text: "JMP $C5F5"},
+			"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7\n",
+		},
+		{
+			"Undocumented",
+			Disassembly{Address: 0xC6BD, Bytes: []byte{0x04, 0xA9}, Text: "NOP $A9", Undocumented: true},
+			"C6BD  04 A9    *NOP $A9                         A:00 X:00 Y:00 P:24 SP:FD CYC:7\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := State{SP: 0xFD, P: 0x24, Cycles: 7}
+
+			line := FormatTraceLine(test.disassembly, state)
+
+			if line != test.expected {
+				t.Errorf("Trace line should be\n%q, got\n%q", test.expected, line)
+			}
+		})
+	}
+}
+
+func TestTracer(t *testing.T) {
+	var trace strings.Builder
+	cpu := NewCPU(WithTracer(&trace))
+	cpu.ram[0x0000] = InstructionAsHex("LDAImmediate")
+	cpu.ram[0x0001] = 0x42
+	cpu.ram[0x0002] = InstructionAsHex("INX")
+
+	cpu.Step()
+	cpu.Step()
+
+	expected := "" +
+		"0000  A9 42     LDA #$42                        A:00 X:00 Y:00 P:20 SP:FF CYC:0\n" +
+		"0002  E8        INX                             A:42 X:00 Y:00 P:20 SP:FF CYC:2\n"
+
+	if trace.String() != expected {
+		t.Errorf("Trace should be\n%s, got\n%s", expected, trace.String())
+	}
+
+	if cpu.cycles != 4 {
+		t.Errorf("Tracing should not use any cycles, got %d", cpu.cycles)
+	}
+
+	trace.Reset()
+	cpu.SetTracer(nil)
+	cpu.Step()
+
+	if trace.Len() != 0 {
+		t.Errorf("Tracing should be turned off")
+	}
+}
+
+func TestTracerInterrupts(t *testing.T) {
+	var trace strings.Builder
+	cpu := NewCPU(WithTracer(&trace))
+	cpu.ram[resetVector] = 0x00
+	cpu.ram[resetVector+1] = 0x02
+	cpu.ram[nmiVector] = 0x00
+	cpu.ram[nmiVector+1] = 0x10
+	cpu.ram[0x0200] = InstructionAsHex("INX")
+	cpu.ram[0x1000] = InstructionAsHex("INY")
+
+	cpu.Reset()
+	cpu.TriggerNMI()
+	cpu.Step()
+	cpu.Step()
+
+	expected := "" +
+		"0000            RESET                           A:00 X:00 Y:00 P:20 SP:FF CYC:0\n" +
+		"0200            NMI                             A:00 X:00 Y:00 P:24 SP:FC CYC:7\n" +
+		"1000  C8        INY                             A:00 X:00 Y:00 P:24 SP:F9 CYC:14\n"
+
+	if trace.String() != expected {
+		t.Errorf("Trace should be\n%s, got\n%s", expected, trace.String())
+	}
+}
+
+func TestTracerWithTick(t *testing.T) {
+	var trace strings.Builder
+	cpu := NewCPU(WithTracer(&trace))
+	cpu.ram[0x0000] = InstructionAsHex("INX")
+
+	tickInstruction(cpu)
+
+	if !strings.HasPrefix(trace.String(), "0000  E8        INX") {
+		t.Errorf("Tick should trace the instruction, got %q", trace.String())
+	}
+}
@@ -0,0 +1,47 @@
+package cpu6510
+
+// Trap emulates the routine at the address it is set on, so that programs can
+// call into ROM routines, like those of the KERNAL, without the ROM being
+// there. It is called instead of fetching the opcode at the address, and
+// leaves the program counter where execution continues, which is usually
+// back in the caller through ReturnFromSubroutine. The error it returns is
+// returned by Step.
+type Trap func(c *CPU) error
+
+// SetTrap sets the trap for the address, replacing any trap already set on it.
+// A nil trap removes it. Traps are run by Step, Run and the functions built
+// on Step, but not by Tick.
+func (c *CPU) SetTrap(address uint16, trap Trap) {
+	if trap == nil {
+		delete(c.traps, address)
+		if len(c.traps) == 0 {
+			c.traps = nil
+		}
+
+		return
+	}
+
+	if c.traps == nil {
+		c.traps = map[uint16]Trap{}
+	}
+
+	c.traps[address] = trap
+}
+
+// ReturnFromSubroutine returns to the caller of the routine, by running an
+// RTS instruction without fetching it. It is meant for traps.
+func (c *CPU) ReturnFromSubroutine() {
+	RTS(c)
+	c.cycles += instructionCycles[0x60] // RTS
+}
+
+// runTrap runs the trap set on the program counter, if there is one, and
+// returns true when it did.
+func (c *CPU) runTrap() (bool, error) {
+	trap, ok := c.traps[c.programCounter]
+	if !ok {
+		return false, nil
+	}
+
+	return true, trap(c)
+}
@@ -0,0 +1,80 @@
+package cpu6510
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTrapCPU returns a CPU that calls the routine at $FFD2 from $0200:
+//
+//	0200 LDA #$41
+//	0202 JSR $FFD2
+//	0205 INX
+func newTrapCPU() *CPU {
+	cpu := NewCPU()
+	cpu.LoadAt(0x0200, []byte{0xA9, 0x41, 0x20, 0xD2, 0xFF, 0xE8}, SetPCToLoadAddress())
+
+	return cpu
+}
+
+func TestTrap(t *testing.T) {
+	t.Run("Run the trap instead of the routine", func(t *testing.T) {
+		cpu := newTrapCPU()
+
+		var printed []byte
+		cpu.SetTrap(0xFFD2, func(c *CPU) error {
+			printed = append(printed, c.A())
+			c.ReturnFromSubroutine()
+
+			return nil
+		})
+
+		cpu.Step()
+		cpu.Step()
+		cycles, err := cpu.Step()
+
+		if err != nil || string(printed) != "A" {
+			t.Errorf("Trap should print A, got %q and %v", printed, err)
+		}
+
+		if cycles != 6 || cpu.PC() != 0x0205 || cpu.SP() != 0xFF {
+			t.Errorf("Trap should return to $0205 in 6 cycles, got $%04X in %d cycles", cpu.PC(), cycles)
+		}
+
+		cpu.Step()
+
+		if cpu.X() != 1 {
+			t.Errorf("CPU should carry on after the trap")
+		}
+	})
+
+	t.Run("Return the error of the trap", func(t *testing.T) {
+		cpu := newTrapCPU()
+		stop := errors.New("stop")
+
+		cpu.SetTrap(0xFFD2, func(c *CPU) error {
+			return stop
+		})
+
+		if err := cpu.Run(); !errors.Is(err, stop) || cpu.PC() != 0xFFD2 {
+			t.Errorf("Run should stop with the error of the trap at $FFD2, got %v at $%04X", err, cpu.PC())
+		}
+	})
+
+	t.Run("Remove the trap", func(t *testing.T) {
+		cpu := newTrapCPU()
+
+		cpu.SetTrap(0xFFD2, func(c *CPU) error {
+			return errors.New("trap should not run")
+		})
+		cpu.SetTrap(0xFFD2, nil)
+
+		cpu.Step()
+		cpu.Step()
+		_, err := cpu.Step()
+
+		if err != nil || cpu.traps != nil || cpu.PC() != 0x0000 {
+			t.Errorf("CPU should execute the BRK at $FFD2 and jump through the IRQ vector, got %v at $%04X", err, cpu.PC())
+		}
+	})
+}
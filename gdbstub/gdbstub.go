@@ -0,0 +1,619 @@
+// Package gdbstub lets a debugger that speaks the GDB remote serial protocol
+// control a CPU6510 over TCP.
+//
+// GDB has no architecture for the 6502, so the stub implements the part of
+// the protocol that works without one. The registers are sent in the order
+// A, X, Y, SP, PC, P, where PC is two bytes, low byte first, and the others
+// are one byte each, which makes registers 0 to 5 for the p and P packets.
+// The supported packets are:
+//
+//	?                      reason the target stopped
+//	g, G                   read and write all registers
+//	p n, P n=v             read and write a single register
+//	m addr,len             read memory
+//	M addr,len:data        write memory
+//	Z0/Z1,addr,kind        set a breakpoint, z0/z1 removes it
+//	Z2/Z3/Z4,addr,len      set a write, read or access watchpoint, z2/z3/z4
+//	                       removes it
+//	s [addr]               step a single instruction
+//	c [addr]               continue until a breakpoint, a jam or ^C
+//	qSupported, qAttached  queries about the stub
+//	D, k                   detach and kill, both end the session
+//
+// Other packets get the empty reply, which tells the debugger they are not
+// supported. Breakpoints are set on the CPU, they do not patch memory.
+package gdbstub
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// The maximum size of a packet the stub accepts.
+const packetSize = 0x4000
+
+// The number of instructions the CPU runs between checks for ^C from the
+// debugger.
+const stepsBetweenInterruptChecks = 1000
+
+// The signals in the stop replies.
+const (
+	sigint  = 2
+	sigill  = 4
+	sigtrap = 5
+)
+
+// The byte the debugger sends to interrupt the running target.
+const interruptByte = 0x03
+
+// errDetached ends a session when the debugger detaches or kills the target.
+var errDetached = errors.New("debugger detached")
+
+// Server serves debuggers for a CPU, one at a time. While a debugger is
+// attached, the server is the only one that drives the CPU.
+type Server struct {
+	cpu *cpu6510.CPU
+}
+
+// NewServer creates a server for the CPU.
+func NewServer(cpu *cpu6510.CPU) *Server {
+	return &Server{cpu: cpu}
+}
+
+// ListenAndServe listens on the TCP address and serves debuggers that
+// connect to it.
+func (s *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on the listener and serves them one after the
+// other.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		s.ServeConn(conn)
+		conn.Close()
+	}
+}
+
+// ServeConn serves a debugger on the connection until it detaches or the
+// connection is closed, and removes the breakpoints it has set. The caller
+// closes the connection.
+func (s *Server) ServeConn(conn io.ReadWriter) error {
+	session := newSession(s.cpu, conn)
+	defer session.removeBreakpoints()
+
+	err := session.serve()
+	if errors.Is(err, errDetached) || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
+
+// breakpointKey identifies a breakpoint the debugger has set.
+type breakpointKey struct {
+	kind    byte
+	address uint16
+}
+
+// session is the conversation with a single debugger.
+type session struct {
+	cpu *cpu6510.CPU
+	out io.Writer
+	// The bytes the debugger sends, read by a goroutine of their own so that
+	// ^C can be noticed while the CPU runs. Closed at the end of the input.
+	in chan byte
+	// The bytes other than ^C that arrived while the CPU ran, like
+	// acknowledgements and the start of the next packet, which are read
+	// before the bytes in the channel.
+	pending []byte
+	// The breakpoints the debugger has set, by type and address, mapped to
+	// the breakpoint numbers of the CPU.
+	breakpoints map[breakpointKey]int
+	// The stop reply for the ? packet.
+	lastStop string
+}
+
+// newSession starts reading from the connection.
+func newSession(cpu *cpu6510.CPU, conn io.ReadWriter) *session {
+	s := &session{
+		cpu:         cpu,
+		out:         conn,
+		in:          make(chan byte, packetSize),
+		breakpoints: map[breakpointKey]int{},
+		lastStop:    fmt.Sprintf("S%02x", sigtrap),
+	}
+
+	go func() {
+		reader := bufio.NewReader(conn)
+		for {
+			b, err := reader.ReadByte()
+			if err != nil {
+				close(s.in)
+				return
+			}
+			s.in <- b
+		}
+	}()
+
+	return s
+}
+
+// serve answers packets until the debugger detaches or the input ends.
+func (s *session) serve() error {
+	for {
+		packet, err := s.readPacket()
+		if err != nil {
+			return err
+		}
+
+		reply, err := s.handle(packet)
+		if err != nil {
+			if errors.Is(err, errDetached) {
+				s.writePacket("OK")
+			}
+			return err
+		}
+
+		if err := s.writePacket(reply); err != nil {
+			return err
+		}
+	}
+}
+
+// next returns the next byte from the debugger, and false at the end of the
+// input.
+func (s *session) next() (byte, bool) {
+	if len(s.pending) > 0 {
+		b := s.pending[0]
+		s.pending = s.pending[1:]
+
+		return b, true
+	}
+
+	b, ok := <-s.in
+
+	return b, ok
+}
+
+// readPacket reads the next packet, $data#checksum, and acknowledges it. A
+// packet with a wrong checksum is asked for again.
+func (s *session) readPacket() (string, error) {
+	for {
+		b, ok := s.next()
+		if !ok {
+			return "", io.EOF
+		}
+
+		if b != '$' {
+			continue
+		}
+
+		var data []byte
+		for {
+			b, ok = s.next()
+			if !ok {
+				return "", io.EOF
+			}
+			if b == '#' {
+				break
+			}
+			if len(data) >= packetSize {
+				return "", errors.New("packet is too large")
+			}
+			data = append(data, b)
+		}
+
+		var sum [2]byte
+		for i := range sum {
+			if sum[i], ok = s.next(); !ok {
+				return "", io.EOF
+			}
+		}
+
+		expected, err := strconv.ParseUint(string(sum[:]), 16, 8)
+		if err != nil || byte(expected) != checksum(data) {
+			if _, err := io.WriteString(s.out, "-"); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		if _, err := io.WriteString(s.out, "+"); err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+}
+
+// writePacket writes the data as a packet. The acknowledgements of the
+// debugger are skipped by readPacket.
+func (s *session) writePacket(data string) error {
+	_, err := fmt.Fprintf(s.out, "$%s#%02x", data, checksum([]byte(data)))
+
+	return err
+}
+
+// checksum returns the sum of the bytes modulo 256.
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+
+	return sum
+}
+
+// handle answers the packet.
+func (s *session) handle(packet string) (string, error) {
+	if packet == "" {
+		return "", nil
+	}
+
+	args := packet[1:]
+
+	switch packet[0] {
+	case '?':
+		return s.lastStop, nil
+	case 'g':
+		return hex.EncodeToString(registers(s.cpu.State())), nil
+	case 'G':
+		return s.writeRegisters(args), nil
+	case 'p':
+		return s.readRegister(args), nil
+	case 'P':
+		return s.writeRegister(args), nil
+	case 'm':
+		return s.readMemory(args), nil
+	case 'M':
+		return s.writeMemory(args), nil
+	case 'Z':
+		return s.setBreakpoint(args), nil
+	case 'z':
+		return s.removeBreakpoint(args), nil
+	case 's':
+		return s.resume(args, true)
+	case 'c':
+		return s.resume(args, false)
+	case 'D', 'k':
+		return "", errDetached
+	case 'H':
+		return "OK", nil
+	case 'q':
+		return query(args), nil
+	}
+
+	return "", nil
+}
+
+// query answers the q packets.
+func query(args string) string {
+	switch {
+	case strings.HasPrefix(args, "Supported"):
+		return fmt.Sprintf("PacketSize=%x", packetSize)
+	case args == "Attached":
+		return "1"
+	}
+
+	return ""
+}
+
+// errorReply returns the reply for a malformed packet.
+func errorReply() string {
+	return "E01"
+}
+
+// The sizes in bytes of the registers A, X, Y, SP, PC and P.
+var registerSizes = []int{1, 1, 1, 1, 2, 1}
+
+// registers returns the registers in the order the debugger expects them.
+func registers(state cpu6510.State) []byte {
+	return []byte{state.A, state.X, state.Y, state.SP, byte(state.PC), byte(state.PC >> 8), state.P}
+}
+
+// setRegisters sets the state from the registers in the order the debugger
+// sends them.
+func setRegisters(state *cpu6510.State, values []byte) {
+	state.A = values[0]
+	state.X = values[1]
+	state.Y = values[2]
+	state.SP = values[3]
+	state.PC = cpu6510.ConvertTwoBytesToAddress(values[5], values[4])
+	state.P = values[6]
+}
+
+// writeRegisters answers the G packet.
+func (s *session) writeRegisters(args string) string {
+	values, err := hex.DecodeString(args)
+	if err != nil || len(values) != len(registers(cpu6510.State{})) {
+		return errorReply()
+	}
+
+	state := s.cpu.State()
+	setRegisters(&state, values)
+	s.cpu.SetState(state)
+
+	return "OK"
+}
+
+// registerOffset returns the offset of the register in the registers, and
+// its size.
+func registerOffset(number string) (int, int, bool) {
+	n, err := strconv.ParseUint(number, 16, 8)
+	if err != nil || int(n) >= len(registerSizes) {
+		return 0, 0, false
+	}
+
+	offset := 0
+	for _, size := range registerSizes[:n] {
+		offset += size
+	}
+
+	return offset, registerSizes[n], true
+}
+
+// readRegister answers the p packet.
+func (s *session) readRegister(args string) string {
+	offset, size, ok := registerOffset(args)
+	if !ok {
+		return errorReply()
+	}
+
+	return hex.EncodeToString(registers(s.cpu.State())[offset : offset+size])
+}
+
+// writeRegister answers the P packet.
+func (s *session) writeRegister(args string) string {
+	number, value, found := strings.Cut(args, "=")
+	offset, size, ok := registerOffset(number)
+	if !found || !ok {
+		return errorReply()
+	}
+
+	bytes, err := hex.DecodeString(value)
+	if err != nil || len(bytes) != size {
+		return errorReply()
+	}
+
+	state := s.cpu.State()
+	values := registers(state)
+	copy(values[offset:], bytes)
+	setRegisters(&state, values)
+	s.cpu.SetState(state)
+
+	return "OK"
+}
+
+// parseAddressAndLength parses addr,len.
+func parseAddressAndLength(args string) (uint16, int, bool) {
+	addressArg, lengthArg, found := strings.Cut(args, ",")
+	if !found {
+		return 0, 0, false
+	}
+
+	address, err := strconv.ParseUint(addressArg, 16, 16)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	length, err := strconv.ParseUint(lengthArg, 16, 32)
+	if err != nil || length > packetSize/2 {
+		return 0, 0, false
+	}
+
+	return uint16(address), int(length), true
+}
+
+// readMemory answers the m packet. Reading past $FFFF wraps around.
+func (s *session) readMemory(args string) string {
+	address, length, ok := parseAddressAndLength(args)
+	if !ok {
+		return errorReply()
+	}
+
+	data := make([]byte, length)
+	for i := range data {
+		data[i] = s.cpu.Peek(address + uint16(i))
+	}
+
+	return hex.EncodeToString(data)
+}
+
+// writeMemory answers the M packet.
+func (s *session) writeMemory(args string) string {
+	target, value, found := strings.Cut(args, ":")
+	address, length, ok := parseAddressAndLength(target)
+	if !found || !ok {
+		return errorReply()
+	}
+
+	data, err := hex.DecodeString(value)
+	if err != nil || len(data) != length {
+		return errorReply()
+	}
+
+	for i, b := range data {
+		s.cpu.Poke(address+uint16(i), b)
+	}
+
+	return "OK"
+}
+
+// The kinds of access of the breakpoint types of the Z and z packets.
+var breakpointAccesses = map[byte]cpu6510.Access{
+	'0': cpu6510.AccessExec,
+	'1': cpu6510.AccessExec,
+	'2': cpu6510.AccessWrite,
+	'3': cpu6510.AccessRead,
+	'4': cpu6510.AccessRead | cpu6510.AccessWrite,
+}
+
+// parseBreakpoint parses type,addr,kind, where kind is the length of a
+// watchpoint.
+func parseBreakpoint(args string) (breakpointKey, cpu6510.Breakpoint, bool) {
+	fields := strings.Split(args, ",")
+	if len(fields) < 3 || len(fields[0]) != 1 {
+		return breakpointKey{}, cpu6510.Breakpoint{}, false
+	}
+
+	access, ok := breakpointAccesses[fields[0][0]]
+	if !ok {
+		return breakpointKey{}, cpu6510.Breakpoint{}, false
+	}
+
+	address, length, ok := parseAddressAndLength(fields[1] + "," + fields[2])
+	if !ok {
+		return breakpointKey{}, cpu6510.Breakpoint{}, false
+	}
+
+	breakpoint := cpu6510.Breakpoint{Access: access, Start: address, End: address}
+	if access != cpu6510.AccessExec && length > 1 {
+		breakpoint.End = uint16(min(int(address)+length-1, 0xFFFF))
+	}
+
+	return breakpointKey{kind: fields[0][0], address: address}, breakpoint, true
+}
+
+// setBreakpoint answers the Z packet.
+func (s *session) setBreakpoint(args string) string {
+	key, breakpoint, ok := parseBreakpoint(args)
+	if !ok {
+		return ""
+	}
+
+	if _, exists := s.breakpoints[key]; exists {
+		return "OK"
+	}
+
+	id, err := s.cpu.AddBreakpoint(breakpoint)
+	if err != nil {
+		return errorReply()
+	}
+	s.breakpoints[key] = id
+
+	return "OK"
+}
+
+// removeBreakpoint answers the z packet.
+func (s *session) removeBreakpoint(args string) string {
+	key, _, ok := parseBreakpoint(args)
+	if !ok {
+		return ""
+	}
+
+	if id, exists := s.breakpoints[key]; exists {
+		s.cpu.RemoveBreakpoint(id)
+		delete(s.breakpoints, key)
+	}
+
+	return "OK"
+}
+
+// removeBreakpoints removes the breakpoints the debugger has set.
+func (s *session) removeBreakpoints() {
+	for key, id := range s.breakpoints {
+		s.cpu.RemoveBreakpoint(id)
+		delete(s.breakpoints, key)
+	}
+}
+
+// resume answers the s and c packets, which continue at the address when one
+// is given, and returns the stop reply.
+func (s *session) resume(args string, step bool) (string, error) {
+	if args != "" {
+		address, err := strconv.ParseUint(args, 16, 16)
+		if err != nil {
+			return errorReply(), nil
+		}
+		s.cpu.SetPC(uint16(address))
+	}
+
+	if step {
+		_, err := s.cpu.Step()
+		s.lastStop = stopReply(err)
+
+		return s.lastStop, nil
+	}
+
+	for {
+		interrupted, err := s.interrupted()
+		if err != nil {
+			return "", err
+		}
+		if interrupted {
+			s.lastStop = fmt.Sprintf("S%02x", sigint)
+			return s.lastStop, nil
+		}
+
+		for i := 0; i < stepsBetweenInterruptChecks; i++ {
+			if _, err := s.cpu.Step(); err != nil {
+				s.lastStop = stopReply(err)
+				return s.lastStop, nil
+			}
+		}
+	}
+}
+
+// interrupted reads the bytes that have arrived from the debugger while the
+// CPU runs, and returns true when one of them is ^C. The other bytes are kept
+// for readPacket, to be handled after the stop reply.
+func (s *session) interrupted() (bool, error) {
+	for {
+		select {
+		case b, ok := <-s.in:
+			if !ok {
+				return false, io.EOF
+			}
+			if b == interruptByte {
+				return true, nil
+			}
+			s.pending = append(s.pending, b)
+		default:
+			return false, nil
+		}
+	}
+}
+
+// stopReply returns the stop reply for the error that stopped the CPU, nil
+// when a step completed.
+func stopReply(err error) string {
+	var hit *cpu6510.BreakpointError
+
+	switch {
+	case err == nil:
+		return fmt.Sprintf("S%02x", sigtrap)
+	case errors.As(err, &hit) && hit.Access != cpu6510.AccessExec:
+		watch := "awatch"
+		switch hit.Breakpoint.Access {
+		case cpu6510.AccessWrite:
+			watch = "watch"
+		case cpu6510.AccessRead:
+			watch = "rwatch"
+		}
+		return fmt.Sprintf("T%02x%s:%x;", sigtrap, watch, hit.Address)
+	case errors.As(err, &hit):
+		return fmt.Sprintf("S%02x", sigtrap)
+	}
+
+	return fmt.Sprintf("S%02x", sigill)
+}
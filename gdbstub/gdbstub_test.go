@@ -0,0 +1,326 @@
+package gdbstub
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// client is the debugger side of a session.
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	done   chan error
+}
+
+// newClient starts a session for a CPU with a small program at $0200:
+//
+//	0200 LDA #$42
+//	0202 STA $1000
+//	0205 INX
+//	0206 JMP $0205
+func newClient(t *testing.T) (*client, *cpu6510.CPU) {
+	t.Helper()
+
+	cpu := cpu6510.NewCPU()
+	cpu.LoadAt(0x0200, []byte{0xA9, 0x42, 0x8D, 0x00, 0x10, 0xE8, 0x4C, 0x05, 0x02}, cpu6510.SetPCToLoadAddress())
+
+	serverConn, clientConn := net.Pipe()
+	c := &client{t: t, conn: clientConn, reader: bufio.NewReader(clientConn), done: make(chan error, 1)}
+
+	go func() {
+		c.done <- NewServer(cpu).ServeConn(serverConn)
+		serverConn.Close()
+	}()
+
+	t.Cleanup(func() { clientConn.Close() })
+
+	return c, cpu
+}
+
+// send sends the packet and returns the acknowledgement.
+func (c *client) send(data string) byte {
+	c.t.Helper()
+
+	if _, err := fmt.Fprintf(c.conn, "$%s#%02x", data, checksum([]byte(data))); err != nil {
+		c.t.Fatalf("write error: %v", err)
+	}
+
+	ack, err := c.reader.ReadByte()
+	if err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+
+	return ack
+}
+
+// reply reads a packet.
+func (c *client) reply() string {
+	c.t.Helper()
+
+	if b, err := c.reader.ReadByte(); err != nil || b != '$' {
+		c.t.Fatalf("packet should start with $, got %q and %v", b, err)
+	}
+
+	data, err := c.reader.ReadString('#')
+	if err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+	data = data[:len(data)-1]
+
+	var sum [2]byte
+	if _, err := c.reader.Read(sum[:1]); err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+	if _, err := c.reader.Read(sum[1:]); err != nil {
+		c.t.Fatalf("read error: %v", err)
+	}
+
+	if string(sum[:]) != fmt.Sprintf("%02x", checksum([]byte(data))) {
+		c.t.Errorf("packet %q has a wrong checksum %s", data, sum)
+	}
+
+	return data
+}
+
+// request sends the packet and returns the reply.
+func (c *client) request(data string) string {
+	c.t.Helper()
+
+	if ack := c.send(data); ack != '+' {
+		c.t.Fatalf("packet %q should be acknowledged, got %q", data, ack)
+	}
+
+	return c.reply()
+}
+
+func TestChecksum(t *testing.T) {
+	if checksum([]byte("OK")) != 0x9a {
+		t.Errorf("Checksum of OK should be 9a, got %02x", checksum([]byte("OK")))
+	}
+}
+
+func TestRegisters(t *testing.T) {
+	c, cpu := newClient(t)
+	cpu.SetState(cpu6510.State{A: 0x01, X: 0x02, Y: 0x03, SP: 0xFD, PC: 0xC000, P: 0x24})
+
+	if reply := c.request("g"); reply != "010203fd00c024" {
+		t.Errorf("g should return the registers, got %q", reply)
+	}
+
+	if reply := c.request("G112233f03412a1"); reply != "OK" {
+		t.Errorf("G should be accepted, got %q", reply)
+	}
+
+	expected := cpu6510.State{A: 0x11, X: 0x22, Y: 0x33, SP: 0xF0, PC: 0x1234, P: 0xA1}
+	if cpu.State() != expected {
+		t.Errorf("Registers should be %+v, got %+v", expected, cpu.State())
+	}
+
+	if reply := c.request("p4"); reply != "3412" {
+		t.Errorf("p4 should return the program counter, got %q", reply)
+	}
+
+	if reply := c.request("P0=7f"); reply != "OK" || cpu.A() != 0x7F {
+		t.Errorf("P0 should set the accumulator, got %q", reply)
+	}
+
+	if reply := c.request("P4=0002"); reply != "OK" || cpu.PC() != 0x0200 {
+		t.Errorf("P4 should set the program counter, got %q", reply)
+	}
+
+	for _, packet := range []string{"p6", "P1=1234", "Gzz", "G00"} {
+		if reply := c.request(packet); reply != "E01" {
+			t.Errorf("%q should fail, got %q", packet, reply)
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	c, cpu := newClient(t)
+
+	if reply := c.request("m200,5"); reply != "a9428d0010" {
+		t.Errorf("m should read memory, got %q", reply)
+	}
+
+	if reply := c.request("Mc000,3:010203"); reply != "OK" {
+		t.Errorf("M should be accepted, got %q", reply)
+	}
+
+	if cpu.Peek(0xC000) != 0x01 || cpu.Peek(0xC002) != 0x03 {
+		t.Errorf("M should write memory")
+	}
+
+	for _, packet := range []string{"m200", "mzz,1", "Mc000,2:01", "Mc000,1"} {
+		if reply := c.request(packet); reply != "E01" {
+			t.Errorf("%q should fail, got %q", packet, reply)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	c, cpu := newClient(t)
+
+	if reply := c.request("s"); reply != "S05" || cpu.PC() != 0x0202 || cpu.A() != 0x42 {
+		t.Errorf("s should step a single instruction, got %q", reply)
+	}
+
+	if reply := c.request("s205"); reply != "S05" || cpu.PC() != 0x0206 {
+		t.Errorf("s should step from the given address, got %q", reply)
+	}
+
+	if reply := c.request("?"); reply != "S05" {
+		t.Errorf("? should return the last stop reply, got %q", reply)
+	}
+}
+
+func TestBreakpoint(t *testing.T) {
+	c, cpu := newClient(t)
+
+	if reply := c.request("Z0,206,1"); reply != "OK" {
+		t.Errorf("Z0 should be accepted, got %q", reply)
+	}
+
+	if reply := c.request("c"); reply != "S05" || cpu.PC() != 0x0206 {
+		t.Errorf("c should stop at the breakpoint, got %q at $%04X", reply, cpu.PC())
+	}
+
+	if reply := c.request("c"); reply != "S05" || cpu.PC() != 0x0206 || cpu.X() != 2 {
+		t.Errorf("c should stop at the breakpoint again, got %q", reply)
+	}
+
+	if reply := c.request("z0,206,1"); reply != "OK" || len(cpu.Breakpoints()) != 0 {
+		t.Errorf("z0 should remove the breakpoint, got %q", reply)
+	}
+}
+
+func TestWatchpoints(t *testing.T) {
+	tests := []struct {
+		packet   string
+		expected string
+	}{
+		{"Z2,1000,1", "T05watch:1000;"},
+		{"Z4,ff0,20", "T05awatch:1000;"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.packet, func(t *testing.T) {
+			c, cpu := newClient(t)
+
+			c.request(test.packet)
+
+			if reply := c.request("c"); reply != test.expected || cpu.PC() != 0x0205 {
+				t.Errorf("c should stop after the store, got %q", reply)
+			}
+		})
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	c, _ := newClient(t)
+
+	if ack := c.send("c"); ack != '+' {
+		t.Fatalf("c should be acknowledged")
+	}
+
+	if _, err := c.conn.Write([]byte{interruptByte}); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if reply := c.reply(); reply != "S02" {
+		t.Errorf("^C should stop the CPU with SIGINT, got %q", reply)
+	}
+}
+
+func TestPacketWhileRunning(t *testing.T) {
+	c, _ := newClient(t)
+
+	if ack := c.send("c"); ack != '+' {
+		t.Fatalf("c should be acknowledged")
+	}
+
+	packet := fmt.Sprintf("$qAttached#%02x", checksum([]byte("qAttached")))
+	if _, err := c.conn.Write(append([]byte(packet), interruptByte)); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if reply := c.reply(); reply != "S02" {
+		t.Errorf("^C should stop the CPU with SIGINT, got %q", reply)
+	}
+
+	if ack, _ := c.reader.ReadByte(); ack != '+' {
+		t.Errorf("Packet sent while running should be acknowledged after the stop reply, got %q", ack)
+	}
+
+	if reply := c.reply(); reply != "1" {
+		t.Errorf("Packet sent while running should be answered, got %q", reply)
+	}
+}
+
+func TestJam(t *testing.T) {
+	c, cpu := newClient(t)
+	cpu.Poke(0x0205, 0x02)
+
+	if reply := c.request("c"); reply != "S04" {
+		t.Errorf("JAM should stop the CPU with SIGILL, got %q", reply)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	c, _ := newClient(t)
+
+	tests := []struct {
+		packet   string
+		expected string
+	}{
+		{"qSupported:multiprocess+", "PacketSize=4000"},
+		{"qAttached", "1"},
+		{"Hg0", "OK"},
+		{"vCont?", ""},
+		{"Z9,0,0", ""},
+	}
+
+	for _, test := range tests {
+		if reply := c.request(test.packet); reply != test.expected {
+			t.Errorf("%q should reply %q, got %q", test.packet, test.expected, reply)
+		}
+	}
+}
+
+func TestBadChecksum(t *testing.T) {
+	c, _ := newClient(t)
+
+	if _, err := c.conn.Write([]byte("$g#00")); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if ack, _ := c.reader.ReadByte(); ack != '-' {
+		t.Errorf("Packet with a wrong checksum should be asked for again, got %q", ack)
+	}
+
+	if reply := c.request("qAttached"); reply != "1" {
+		t.Errorf("Session should continue, got %q", reply)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	c, cpu := newClient(t)
+
+	c.request("Z0,206,1")
+
+	if reply := c.request("D"); reply != "OK" {
+		t.Errorf("D should be accepted, got %q", reply)
+	}
+
+	if err := <-c.done; err != nil {
+		t.Errorf("Session should end without an error, got %v", err)
+	}
+
+	if len(cpu.Breakpoints()) != 0 {
+		t.Errorf("Breakpoints of the debugger should be removed")
+	}
+}
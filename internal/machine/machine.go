@@ -0,0 +1,52 @@
+// Package machine sets up a CPU6510 for the commands, either on its own or in
+// the memory map of the C64.
+package machine
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+	"github.com/stefanalfbo/commodore64/memory"
+	"github.com/stefanalfbo/commodore64/rom"
+)
+
+// New creates a CPU with 64KB of flat RAM when no ROMs are given. Otherwise
+// the CPU gets the memory map of the C64 with the ROMs installed, and starts
+// from the reset vector of the KERNAL.
+func New(paths rom.Paths) (*cpu6510.CPU, error) {
+	if paths == (rom.Paths{}) {
+		return cpu6510.NewCPU(), nil
+	}
+
+	set, err := paths.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	mem := memory.New()
+	if err := set.Install(mem); err != nil {
+		return nil, err
+	}
+
+	cpu := cpu6510.NewCPUWithBus(mem)
+	mem.ConnectPort(cpu.IOPort())
+	cpu.Reset()
+
+	return cpu, nil
+}
+
+// LoadPRG loads the PRG file, and sets the program counter to the address of
+// the SYS line in its BASIC stub. A program without one returns an error, as
+// its load address may well be data rather than code, and leaves the program
+// counter where it was.
+func LoadPRG(cpu *cpu6510.CPU, path string) error {
+	prg, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = cpu.LoadPRG(bytes.NewReader(prg), cpu6510.SetPCToSYS())
+
+	return err
+}
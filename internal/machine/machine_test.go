@@ -0,0 +1,61 @@
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/rom"
+)
+
+func TestNewWithoutROMs(t *testing.T) {
+	cpu, err := New(rom.Paths{})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	if cpu.IOPort() != nil {
+		t.Errorf("CPU without ROMs should have flat RAM")
+	}
+}
+
+func TestNewWithMissingROMs(t *testing.T) {
+	if _, err := New(rom.Paths{Directory: t.TempDir()}); err == nil {
+		t.Errorf("New should fail when the ROMs are missing")
+	}
+}
+
+func TestLoadPRG(t *testing.T) {
+	tests := []struct {
+		name     string
+		prg      []byte
+		expected uint16
+		fails    bool
+	}{
+		{"Start at the SYS address", []byte{0x01, 0x08, 0x0B, 0x08, 0x0A, 0x00, 0x9E, '2', '0', '6', '1', 0x00, 0x00, 0x00, 0xE8}, 2061, false},
+		{"Fail without a SYS line", []byte{0x00, 0xC0, 0xE8}, 0x0000, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.prg")
+			if err := os.WriteFile(path, test.prg, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cpu, _ := New(rom.Paths{})
+
+			if err := LoadPRG(cpu, path); (err != nil) != test.fails {
+				t.Fatalf("LoadPRG should fail: %v, got %v", test.fails, err)
+			}
+
+			if cpu.PC() != test.expected {
+				t.Errorf("Program counter should be $%04X, got $%04X", test.expected, cpu.PC())
+			}
+		})
+	}
+
+	if err := LoadPRG(nil, filepath.Join(t.TempDir(), "missing.prg")); err == nil {
+		t.Errorf("LoadPRG should fail for a missing file")
+	}
+}
@@ -0,0 +1,216 @@
+// Package memory implements the memory map of the Commodore 64, where the PLA
+// maps RAM, the BASIC, KERNAL and character ROMs, the I/O area and cartridge
+// ROMs into the address space of the CPU6510.
+package memory
+
+import "fmt"
+
+// The sizes of the ROMs.
+const (
+	BASICSize     = 8192
+	KERNALSize    = 8192
+	CharacterSize = 4096
+	// The size of each of the cartridge ROMs, ROML and ROMH.
+	CartridgeSize = 8192
+)
+
+// The value read from an address where nothing answers.
+const openBus byte = 0xFF
+
+// Port is the part of the 6510 I/O port that selects the memory
+// configuration.
+type Port interface {
+	// LORAM returns the level of the LORAM line, which selects the BASIC ROM.
+	LORAM() bool
+	// HIRAM returns the level of the HIRAM line, which selects the KERNAL ROM.
+	HIRAM() bool
+	// CHAREN returns the level of the CHAREN line, which selects between the
+	// I/O area and the character ROM.
+	CHAREN() bool
+}
+
+// Device is a chip, or group of chips, in the I/O area at $D000-$DFFF.
+type Device interface {
+	// Read returns the byte at the given address.
+	Read(address uint16) byte
+	// Write writes the byte to the given address.
+	Write(address uint16, value byte)
+}
+
+// Memory is the memory of the C64 as seen by the CPU6510. It implements the
+// Bus of the CPU. Reads from an area where a ROM is mapped in return the ROM,
+// while writes to it always go to the RAM underneath.
+type Memory struct {
+	ram       [65536]byte
+	basic     [BASICSize]byte
+	kernal    [KERNALSize]byte
+	character [CharacterSize]byte
+	roml      [CartridgeSize]byte
+	romh      [CartridgeSize]byte
+	// The I/O port of the CPU, nil when it is not connected, which means all
+	// its lines are high.
+	port Port
+	// The chips in the I/O area, nil when none are connected.
+	io Device
+	// The levels of the EXROM and GAME lines of the expansion port, which are
+	// high when no cartridge is inserted.
+	exrom bool
+	game  bool
+}
+
+// New creates the memory of a C64 without any cartridge inserted, and with
+// empty ROMs.
+func New() *Memory {
+	return &Memory{
+		exrom: true,
+		game:  true,
+	}
+}
+
+// loadROM copies the image into the ROM, which must have the same size.
+func loadROM(name string, rom []byte, image []byte) error {
+	if len(image) != len(rom) {
+		return fmt.Errorf("%s ROM must be %d bytes, got %d", name, len(rom), len(image))
+	}
+
+	copy(rom, image)
+
+	return nil
+}
+
+// LoadBASIC loads the image of the BASIC ROM, mapped in at $A000-$BFFF.
+func (m *Memory) LoadBASIC(image []byte) error {
+	return loadROM("BASIC", m.basic[:], image)
+}
+
+// LoadKERNAL loads the image of the KERNAL ROM, mapped in at $E000-$FFFF.
+func (m *Memory) LoadKERNAL(image []byte) error {
+	return loadROM("KERNAL", m.kernal[:], image)
+}
+
+// LoadCharacter loads the image of the character ROM, mapped in at
+// $D000-$DFFF.
+func (m *Memory) LoadCharacter(image []byte) error {
+	return loadROM("Character", m.character[:], image)
+}
+
+// ConnectPort connects the I/O port of the CPU, which selects the memory
+// configuration.
+func (m *Memory) ConnectPort(port Port) {
+	m.port = port
+}
+
+// ConnectIO connects the chips in the I/O area at $D000-$DFFF.
+func (m *Memory) ConnectIO(io Device) {
+	m.io = io
+}
+
+// InsertCartridge inserts a cartridge with the given ROML and ROMH images,
+// either of which may be empty, and the levels it drives the EXROM and GAME
+// lines with. An 8KB cartridge pulls EXROM low, a 16KB cartridge pulls both
+// low, and an Ultimax cartridge pulls GAME low.
+func (m *Memory) InsertCartridge(roml []byte, romh []byte, exrom bool, game bool) error {
+	if len(roml) > CartridgeSize || len(romh) > CartridgeSize {
+		return fmt.Errorf("cartridge ROMs must be at most %d bytes", CartridgeSize)
+	}
+
+	m.roml = [CartridgeSize]byte{}
+	m.romh = [CartridgeSize]byte{}
+	copy(m.roml[:], roml)
+	copy(m.romh[:], romh)
+
+	m.exrom = exrom
+	m.game = game
+
+	return nil
+}
+
+// RemoveCartridge removes the cartridge, which releases the EXROM and GAME
+// lines.
+func (m *Memory) RemoveCartridge() {
+	m.exrom = true
+	m.game = true
+}
+
+// RAM returns the RAM of the C64, also the parts hidden by the ROMs.
+func (m *Memory) RAM() *[65536]byte {
+	return &m.ram
+}
+
+// Mode returns the current PLA mode, made up of the EXROM, GAME, CHAREN,
+// HIRAM and LORAM lines, from the highest bit to the lowest.
+func (m *Memory) Mode() byte {
+	mode := loramBit | hiramBit | charenBit
+
+	if m.port != nil {
+		mode = 0
+		if m.port.LORAM() {
+			mode |= loramBit
+		}
+		if m.port.HIRAM() {
+			mode |= hiramBit
+		}
+		if m.port.CHAREN() {
+			mode |= charenBit
+		}
+	}
+
+	if m.game {
+		mode |= gameBit
+	}
+	if m.exrom {
+		mode |= exromBit
+	}
+
+	return mode
+}
+
+// area returns what is mapped in at the address in the current mode.
+func (m *Memory) area(address uint16) area {
+	return configurations[m.Mode()][address>>12]
+}
+
+// Read returns the byte at the given address.
+func (m *Memory) Read(address uint16) byte {
+	switch m.area(address) {
+	case basicROM:
+		return m.basic[address-0xA000]
+	case kernalROM:
+		return m.kernal[address-0xE000]
+	case characterROM:
+		return m.character[address-0xD000]
+	case io:
+		if m.io == nil {
+			return openBus
+		}
+		return m.io.Read(address)
+	case cartridgeROML:
+		return m.roml[address&(CartridgeSize-1)]
+	case cartridgeROMH:
+		return m.romh[address&(CartridgeSize-1)]
+	case unmapped:
+		return openBus
+	}
+
+	return m.ram[address]
+}
+
+// Write writes the byte to the given address. Writes to a ROM go to the RAM
+// underneath, except in the Ultimax mode where there is no RAM underneath the
+// cartridge ROMs.
+func (m *Memory) Write(address uint16, value byte) {
+	switch m.area(address) {
+	case io:
+		if m.io != nil {
+			m.io.Write(address, value)
+		}
+	case unmapped:
+	case cartridgeROML, cartridgeROMH:
+		if m.exrom && !m.game {
+			return
+		}
+		m.ram[address] = value
+	default:
+		m.ram[address] = value
+	}
+}
@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/cpu6510"
+)
+
+// The I/O port of the CPU selects the memory configuration.
+var _ Port = (*cpu6510.IOPort)(nil)
+
+// port is a Port with fixed lines.
+type port struct {
+	loram, hiram, charen bool
+}
+
+func (p port) LORAM() bool  { return p.loram }
+func (p port) HIRAM() bool  { return p.hiram }
+func (p port) CHAREN() bool { return p.charen }
+
+// device is an I/O device that remembers the last write.
+type device struct {
+	address uint16
+	value   byte
+}
+
+func (d *device) Read(address uint16) byte {
+	return byte(address)
+}
+
+func (d *device) Write(address uint16, value byte) {
+	d.address = address
+	d.value = value
+}
+
+// newMemory returns memory where every ROM is filled with its own value.
+func newMemory(t *testing.T) *Memory {
+	m := New()
+
+	if err := m.LoadBASIC(bytes.Repeat([]byte{0xBA}, BASICSize)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.LoadKERNAL(bytes.Repeat([]byte{0xEE}, KERNALSize)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.LoadCharacter(bytes.Repeat([]byte{0xCC}, CharacterSize)); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func TestDefaultConfiguration(t *testing.T) {
+	m := newMemory(t)
+
+	tests := []struct {
+		address  uint16
+		expected byte
+	}{
+		{0x0801, 0x00},
+		{0xA000, 0xBA},
+		{0xBFFF, 0xBA},
+		{0xC000, 0x00},
+		{0xD000, openBus},
+		{0xE000, 0xEE},
+		{0xFFFF, 0xEE},
+	}
+
+	if m.Mode() != 31 {
+		t.Errorf("Mode should be 31, got %d", m.Mode())
+	}
+
+	for _, test := range tests {
+		if value := m.Read(test.address); value != test.expected {
+			t.Errorf("Address 0x%04X should read 0x%02X, got 0x%02X", test.address, test.expected, value)
+		}
+	}
+}
+
+func TestWriteUnderROM(t *testing.T) {
+	m := newMemory(t)
+
+	m.Write(0xA000, 0x42)
+	m.Write(0xE000, 0x43)
+
+	if m.Read(0xA000) != 0xBA || m.Read(0xE000) != 0xEE {
+		t.Errorf("ROM should still be read")
+	}
+
+	m.ConnectPort(port{})
+
+	if m.Read(0xA000) != 0x42 || m.Read(0xE000) != 0x43 {
+		t.Errorf("RAM underneath the ROM should be written")
+	}
+}
+
+func TestCharacterROMAndIO(t *testing.T) {
+	m := newMemory(t)
+	d := &device{}
+	m.ConnectIO(d)
+
+	m.ConnectPort(port{loram: true, hiram: true, charen: true})
+	m.Write(0xD020, 0x06)
+
+	if d.address != 0xD020 || d.value != 0x06 {
+		t.Errorf("I/O device should be written")
+	}
+
+	if m.Read(0xD012) != 0x12 {
+		t.Errorf("I/O device should be read")
+	}
+
+	if m.RAM()[0xD020] != 0x00 {
+		t.Errorf("RAM underneath the I/O area should not be written")
+	}
+
+	m.ConnectPort(port{loram: true, hiram: true})
+
+	if m.Read(0xD012) != 0xCC {
+		t.Errorf("Character ROM should be read")
+	}
+}
+
+func TestCartridge(t *testing.T) {
+	t.Run("8KB cartridge", func(t *testing.T) {
+		m := newMemory(t)
+
+		if err := m.InsertCartridge([]byte{0x09, 0x80}, nil, false, true); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Read(0x8000) != 0x09 || m.Read(0x8001) != 0x80 {
+			t.Errorf("ROML should be read at $8000")
+		}
+
+		if m.Read(0xA000) != 0xBA {
+			t.Errorf("BASIC should be read at $A000")
+		}
+
+		m.Write(0x8000, 0x42)
+
+		if m.RAM()[0x8000] != 0x42 {
+			t.Errorf("RAM underneath ROML should be written")
+		}
+	})
+
+	t.Run("16KB cartridge", func(t *testing.T) {
+		m := newMemory(t)
+
+		if err := m.InsertCartridge([]byte{0x11}, []byte{0x22}, false, false); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Read(0x8000) != 0x11 || m.Read(0xA000) != 0x22 {
+			t.Errorf("ROML and ROMH should be read")
+		}
+
+		m.RemoveCartridge()
+
+		if m.Read(0x8000) != 0x00 || m.Read(0xA000) != 0xBA {
+			t.Errorf("RAM and BASIC should be read after removing the cartridge")
+		}
+	})
+
+	t.Run("Ultimax cartridge", func(t *testing.T) {
+		m := newMemory(t)
+
+		if err := m.InsertCartridge(nil, []byte{0x33}, true, false); err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Read(0xE000) != 0x33 {
+			t.Errorf("ROMH should be read at $E000")
+		}
+
+		m.Write(0x4000, 0x42)
+		m.Write(0xE000, 0x42)
+
+		if m.Read(0x4000) != openBus || m.RAM()[0x4000] != 0x00 || m.RAM()[0xE000] != 0x00 {
+			t.Errorf("Unmapped area and ROMH should not be written")
+		}
+
+		m.Write(0x0400, 0x42)
+
+		if m.Read(0x0400) != 0x42 {
+			t.Errorf("RAM at $0000-$0FFF should be written")
+		}
+	})
+
+	t.Run("Too large ROM", func(t *testing.T) {
+		m := newMemory(t)
+
+		if err := m.InsertCartridge(make([]byte, CartridgeSize+1), nil, false, true); err == nil {
+			t.Errorf("Cartridge should be rejected")
+		}
+	})
+}
+
+func TestLoadROMWithWrongSize(t *testing.T) {
+	m := New()
+
+	if err := m.LoadKERNAL(make([]byte, 100)); err == nil {
+		t.Errorf("KERNAL should be rejected")
+	}
+}
+
+func TestBankingWithCPU(t *testing.T) {
+	m := newMemory(t)
+
+	// The reset vector points at the program at $0200.
+	kernal := bytes.Repeat([]byte{0xEE}, KERNALSize)
+	kernal[0x1FFC] = 0x00
+	kernal[0x1FFD] = 0x02
+	if err := m.LoadKERNAL(kernal); err != nil {
+		t.Fatal(err)
+	}
+
+	// LDA #$2F, STA $00, LDA #$34, STA $01, LDA $E000, STA $0400, BRK
+	copy(m.RAM()[0x0200:], []byte{
+		0xA9, 0x2F, 0x85, 0x00, 0xA9, 0x34, 0x85, 0x01,
+		0xAD, 0x00, 0xE0, 0x8D, 0x00, 0x04, 0x00,
+	})
+	m.RAM()[0xE000] = 0x42
+
+	cpu := cpu6510.NewCPUWithBus(m)
+	m.ConnectPort(cpu.IOPort())
+	cpu.Reset()
+	cpu.Run()
+
+	if m.Mode() != 28 {
+		t.Errorf("Mode should be 28, got %d", m.Mode())
+	}
+
+	if m.RAM()[0x0400] != 0x42 {
+		t.Errorf("RAM underneath the KERNAL should be read, got 0x%02X", m.RAM()[0x0400])
+	}
+}
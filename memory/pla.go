@@ -0,0 +1,109 @@
+package memory
+
+// area is what the PLA maps into a 4KB block of the address space.
+type area byte
+
+const (
+	ram area = iota
+	basicROM
+	kernalROM
+	characterROM
+	io
+	cartridgeROML
+	cartridgeROMH
+	// Nothing answers in the block, which only happens in the Ultimax mode.
+	unmapped
+)
+
+// The bits of the PLA mode, which is made up of the LORAM, HIRAM and CHAREN
+// lines from the 6510 I/O port, and the GAME and EXROM lines from the
+// expansion port.
+const (
+	loramBit  byte = 1 << 0
+	hiramBit  byte = 1 << 1
+	charenBit byte = 1 << 2
+	gameBit   byte = 1 << 3
+	exromBit  byte = 1 << 4
+)
+
+// The number of PLA modes.
+const modes = 32
+
+// configurations holds the memory map of each PLA mode, one area per 4KB
+// block.
+var configurations = newConfigurations()
+
+// newConfigurations computes the memory map of all PLA modes.
+func newConfigurations() [modes][16]area {
+	var configurations [modes][16]area
+
+	for mode := range configurations {
+		configurations[mode] = configuration(byte(mode))
+	}
+
+	return configurations
+}
+
+// configuration returns the memory map of the PLA mode, one area per 4KB
+// block.
+func configuration(mode byte) [16]area {
+	loram := mode&loramBit != 0
+	hiram := mode&hiramBit != 0
+	charen := mode&charenBit != 0
+	game := mode&gameBit != 0
+	exrom := mode&exromBit != 0
+
+	var blocks [16]area
+
+	// In the Ultimax mode the cartridge takes over the memory map, and only
+	// the first 4KB of RAM, the I/O area and the cartridge ROMs are left.
+	if exrom && !game {
+		for block := 0x1; block <= 0xF; block++ {
+			blocks[block] = unmapped
+		}
+
+		blocks[0x8] = cartridgeROML
+		blocks[0x9] = cartridgeROML
+		blocks[0xD] = io
+		blocks[0xE] = cartridgeROMH
+		blocks[0xF] = cartridgeROMH
+
+		return blocks
+	}
+
+	if !exrom && loram && hiram {
+		blocks[0x8] = cartridgeROML
+		blocks[0x9] = cartridgeROML
+	}
+
+	if game && loram && hiram {
+		blocks[0xA] = basicROM
+		blocks[0xB] = basicROM
+	}
+
+	if !exrom && !game && hiram {
+		blocks[0xA] = cartridgeROMH
+		blocks[0xB] = cartridgeROMH
+	}
+
+	// With both LORAM and HIRAM low there is RAM at $D000. With a 16KB
+	// cartridge and HIRAM low, the character ROM is replaced by RAM as well.
+	switch {
+	case !loram && !hiram:
+	case !exrom && !game && !hiram:
+		if charen {
+			blocks[0xD] = io
+		}
+	case charen:
+		blocks[0xD] = io
+	default:
+		blocks[0xD] = characterROM
+	}
+
+	if hiram {
+		blocks[0xE] = kernalROM
+		blocks[0xF] = kernalROM
+	}
+
+	return blocks
+}
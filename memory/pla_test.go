@@ -0,0 +1,79 @@
+package memory
+
+import "testing"
+
+func TestConfigurations(t *testing.T) {
+	const (
+		R = ram
+		B = basicROM
+		K = kernalROM
+		C = characterROM
+		I = io
+		L = cartridgeROML
+		H = cartridgeROMH
+		U = unmapped
+	)
+
+	// The areas at $1000, $8000, $A000, $C000, $D000 and $E000 in each mode,
+	// as listed in the PLA truth table.
+	tests := [modes][6]area{
+		0:  {R, R, R, R, R, R},
+		1:  {R, R, R, R, R, R},
+		2:  {R, R, H, R, C, K},
+		3:  {R, L, H, R, C, K},
+		4:  {R, R, R, R, R, R},
+		5:  {R, R, R, R, I, R},
+		6:  {R, R, H, R, I, K},
+		7:  {R, L, H, R, I, K},
+		8:  {R, R, R, R, R, R},
+		9:  {R, R, R, R, C, R},
+		10: {R, R, R, R, C, K},
+		11: {R, L, B, R, C, K},
+		12: {R, R, R, R, R, R},
+		13: {R, R, R, R, I, R},
+		14: {R, R, R, R, I, K},
+		15: {R, L, B, R, I, K},
+		16: {U, L, U, U, I, H},
+		17: {U, L, U, U, I, H},
+		18: {U, L, U, U, I, H},
+		19: {U, L, U, U, I, H},
+		20: {U, L, U, U, I, H},
+		21: {U, L, U, U, I, H},
+		22: {U, L, U, U, I, H},
+		23: {U, L, U, U, I, H},
+		24: {R, R, R, R, R, R},
+		25: {R, R, R, R, C, R},
+		26: {R, R, R, R, C, K},
+		27: {R, R, B, R, C, K},
+		28: {R, R, R, R, R, R},
+		29: {R, R, R, R, I, R},
+		30: {R, R, R, R, I, K},
+		31: {R, R, B, R, I, K},
+	}
+
+	// The 4KB blocks each column of the truth table covers.
+	columns := [6][]int{
+		{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7},
+		{0x8, 0x9},
+		{0xA, 0xB},
+		{0xC},
+		{0xD},
+		{0xE, 0xF},
+	}
+
+	for mode, expected := range tests {
+		blocks := configurations[mode]
+
+		if blocks[0x0] != ram {
+			t.Errorf("Mode %d should have RAM at $0000", mode)
+		}
+
+		for column, area := range expected {
+			for _, block := range columns[column] {
+				if blocks[block] != area {
+					t.Errorf("Mode %d should map area %d at $%X000, got %d", mode, area, block, blocks[block])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package rom loads the ROM images of the C64 from files supplied by the
+// user, checks their sizes and recognises known revisions by their CRC32.
+package rom
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stefanalfbo/commodore64/memory"
+)
+
+// Kind is the kind of a ROM in the C64.
+type Kind int
+
+const (
+	BASIC Kind = iota
+	KERNAL
+	Character
+)
+
+// String returns the name of the kind of ROM.
+func (k Kind) String() string {
+	switch k {
+	case BASIC:
+		return "BASIC"
+	case KERNAL:
+		return "KERNAL"
+	case Character:
+		return "Character"
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Size returns the size in bytes of the kind of ROM.
+func (k Kind) Size() int {
+	switch k {
+	case BASIC:
+		return memory.BASICSize
+	case KERNAL:
+		return memory.KERNALSize
+	case Character:
+		return memory.CharacterSize
+	}
+
+	return 0
+}
+
+// ErrSize is returned when an image does not have the size of its kind of ROM.
+var ErrSize = errors.New("wrong ROM size")
+
+// revisions holds the known revisions of each kind of ROM, by their CRC32.
+var revisions = map[Kind]map[uint32]string{
+	BASIC: {
+		0xF833D117: "901226-01",
+	},
+	KERNAL: {
+		0xDCE782FA: "901227-01 (revision 1)",
+		0xA5C687B3: "901227-02 (revision 2)",
+		0xDBE3E7C7: "901227-03 (revision 3)",
+		0x2C5965D4: "251104-04 (SX-64)",
+		0x789C8CC5: "901246-01 (4064)",
+		0x2F79984C: "JiffyDOS 6.01",
+		0x2B5A88F5: "JiffyDOS 6.01 (SX-64)",
+	},
+	Character: {
+		0xEC4272EE: "901225-01",
+	},
+}
+
+// Image is a ROM image.
+type Image struct {
+	Kind Kind
+	Data []byte
+	// The CRC32 (IEEE) of the image.
+	CRC32 uint32
+	// The revision of the image, or an empty string when it is not known.
+	Revision string
+}
+
+// Known returns true if the revision of the image was recognised.
+func (i Image) Known() bool {
+	return i.Revision != ""
+}
+
+// String returns a description of the image.
+func (i Image) String() string {
+	revision := i.Revision
+	if revision == "" {
+		revision = "unknown revision"
+	}
+
+	return fmt.Sprintf("%s %s (CRC32 %08x)", i.Kind, revision, i.CRC32)
+}
+
+// Identify checks the size of the image, and recognises its revision.
+func Identify(kind Kind, data []byte) (Image, error) {
+	if len(data) != kind.Size() {
+		return Image{}, fmt.Errorf("%s image must be %d bytes, got %d: %w", kind, kind.Size(), len(data), ErrSize)
+	}
+
+	image := Image{
+		Kind:  kind,
+		Data:  data,
+		CRC32: crc32.ChecksumIEEE(data),
+	}
+
+	image.Revision = revisions[kind][image.CRC32]
+
+	return image, nil
+}
+
+// LoadFile loads the image of the kind of ROM from the file.
+func LoadFile(kind Kind, path string) (Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Image{}, fmt.Errorf("reading %s ROM: %w", kind, err)
+	}
+
+	image, err := Identify(kind, data)
+	if err != nil {
+		return Image{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return image, nil
+}
+
+// Set is the set of ROMs a C64 needs.
+type Set struct {
+	BASIC     Image
+	KERNAL    Image
+	Character Image
+}
+
+// Install loads the ROMs into the memory of the C64.
+func (s *Set) Install(m *memory.Memory) error {
+	if err := m.LoadBASIC(s.BASIC.Data); err != nil {
+		return err
+	}
+
+	if err := m.LoadKERNAL(s.KERNAL.Data); err != nil {
+		return err
+	}
+
+	return m.LoadCharacter(s.Character.Data)
+}
+
+// Paths holds where to load each ROM from. A ROM without a path of its own
+// is looked up in the directory.
+type Paths struct {
+	Directory string
+	BASIC     string
+	KERNAL    string
+	Character string
+}
+
+// Load loads the set of ROMs from the paths.
+func (p Paths) Load() (*Set, error) {
+	set := &Set{}
+
+	for _, rom := range []struct {
+		kind  Kind
+		path  string
+		image *Image
+	}{
+		{BASIC, p.BASIC, &set.BASIC},
+		{KERNAL, p.KERNAL, &set.KERNAL},
+		{Character, p.Character, &set.Character},
+	} {
+		path := rom.path
+
+		if path == "" {
+			if p.Directory == "" {
+				return nil, fmt.Errorf("no file or directory given for the %s ROM", rom.kind)
+			}
+
+			var err error
+			if path, err = find(p.Directory, rom.kind); err != nil {
+				return nil, err
+			}
+		}
+
+		image, err := LoadFile(rom.kind, path)
+		if err != nil {
+			return nil, err
+		}
+
+		*rom.image = image
+	}
+
+	return set, nil
+}
+
+// RegisterFlags defines the command line flags for the paths of the ROMs on
+// the flag set.
+func (p *Paths) RegisterFlags(flags *flag.FlagSet) {
+	flags.StringVar(&p.Directory, "roms", p.Directory, "directory with the basic, kernal and chargen ROM images")
+	flags.StringVar(&p.BASIC, "basic", p.BASIC, "BASIC ROM image, overrides the one in the ROM directory")
+	flags.StringVar(&p.KERNAL, "kernal", p.KERNAL, "KERNAL ROM image, overrides the one in the ROM directory")
+	flags.StringVar(&p.Character, "chargen", p.Character, "character ROM image, overrides the one in the ROM directory")
+}
+
+// LoadDirectory loads the set of ROMs from the directory.
+func LoadDirectory(directory string) (*Set, error) {
+	return Paths{Directory: directory}.Load()
+}
+
+// prefixes holds the prefixes of the file names each kind of ROM is commonly
+// stored under, like basic, basic.bin or kernal-901227-03.bin.
+var prefixes = map[Kind][]string{
+	BASIC:     {"basic"},
+	KERNAL:    {"kernal"},
+	Character: {"chargen", "characters", "char"},
+}
+
+// find returns the file in the directory that holds the kind of ROM.
+func find(directory string, kind Kind) (string, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return "", fmt.Errorf("looking for the %s ROM: %w", kind, err)
+	}
+
+	var candidates []string
+
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+
+		if entry.IsDir() {
+			continue
+		}
+
+		for _, prefix := range prefixes[kind] {
+			if strings.HasPrefix(name, prefix) {
+				candidates = append(candidates, entry.Name())
+				break
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no %s ROM found in %s, expected a file named like %s", kind, directory, prefixes[kind][0])
+	case 1:
+		return filepath.Join(directory, candidates[0]), nil
+	}
+
+	return "", fmt.Errorf("more than one %s ROM found in %s: %s", kind, directory, strings.Join(candidates, ", "))
+}
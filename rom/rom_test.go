@@ -0,0 +1,197 @@
+package rom
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stefanalfbo/commodore64/memory"
+)
+
+// writeROM writes an image of the kind of ROM, filled with the value, to the
+// file in the directory.
+func writeROM(t *testing.T, directory, name string, kind Kind, value byte) string {
+	path := filepath.Join(directory, name)
+
+	if err := os.WriteFile(path, bytes.Repeat([]byte{value}, kind.Size()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestIdentify(t *testing.T) {
+	t.Run("Unknown revision", func(t *testing.T) {
+		data := make([]byte, KERNAL.Size())
+
+		image, err := Identify(KERNAL, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if image.Known() {
+			t.Errorf("Revision should not be known, got %s", image.Revision)
+		}
+
+		if image.CRC32 != crc32.ChecksumIEEE(data) {
+			t.Errorf("CRC32 should be computed")
+		}
+	})
+
+	t.Run("Known revision", func(t *testing.T) {
+		data := make([]byte, BASIC.Size())
+		revisions[BASIC][crc32.ChecksumIEEE(data)] = "test"
+		defer delete(revisions[BASIC], crc32.ChecksumIEEE(data))
+
+		image, err := Identify(BASIC, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if image.Revision != "test" {
+			t.Errorf("Revision should be recognised, got %q", image.Revision)
+		}
+	})
+
+	t.Run("Not recognised by its text", func(t *testing.T) {
+		data := make([]byte, KERNAL.Size())
+		copy(data[0x1000:], "JIFFYDOS V6.01 (C)1989 CMD")
+
+		image, err := Identify(KERNAL, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if image.Known() {
+			t.Errorf("Patched KERNAL should not be recognised as %q", image.Revision)
+		}
+	})
+
+	t.Run("Wrong size", func(t *testing.T) {
+		_, err := Identify(Character, make([]byte, 8192))
+
+		if !errors.Is(err, ErrSize) {
+			t.Errorf("Image should be rejected because of its size, got %v", err)
+		}
+	})
+}
+
+func TestKnownRevisions(t *testing.T) {
+	for kind, known := range revisions {
+		if len(known) == 0 {
+			t.Errorf("%s should have known revisions", kind)
+		}
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	directory := t.TempDir()
+	writeROM(t, directory, "basic-901226-01.bin", BASIC, 0xBA)
+	writeROM(t, directory, "KERNAL.ROM", KERNAL, 0xEE)
+	writeROM(t, directory, "chargen", Character, 0xCC)
+
+	set, err := LoadDirectory(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := memory.New()
+	if err := set.Install(m); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Read(0xA000) != 0xBA || m.Read(0xE000) != 0xEE {
+		t.Errorf("BASIC and KERNAL should be installed")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("Missing ROM", func(t *testing.T) {
+		directory := t.TempDir()
+		writeROM(t, directory, "basic", BASIC, 0xBA)
+		writeROM(t, directory, "kernal", KERNAL, 0xEE)
+
+		_, err := LoadDirectory(directory)
+
+		if err == nil || !strings.Contains(err.Error(), "no Character ROM found") {
+			t.Errorf("Missing character ROM should be reported, got %v", err)
+		}
+	})
+
+	t.Run("More than one candidate", func(t *testing.T) {
+		directory := t.TempDir()
+		writeROM(t, directory, "basic", BASIC, 0xBA)
+		writeROM(t, directory, "kernal-901227-02.bin", KERNAL, 0xEE)
+		writeROM(t, directory, "kernal-901227-03.bin", KERNAL, 0xEE)
+		writeROM(t, directory, "chargen", Character, 0xCC)
+
+		_, err := LoadDirectory(directory)
+
+		if err == nil || !strings.Contains(err.Error(), "more than one KERNAL ROM") {
+			t.Errorf("Ambiguous KERNAL ROM should be reported, got %v", err)
+		}
+	})
+
+	t.Run("Wrong size", func(t *testing.T) {
+		directory := t.TempDir()
+		writeROM(t, directory, "basic", BASIC, 0xBA)
+		writeROM(t, directory, "kernal", Character, 0xEE)
+		writeROM(t, directory, "chargen", Character, 0xCC)
+
+		_, err := LoadDirectory(directory)
+
+		if !errors.Is(err, ErrSize) {
+			t.Errorf("KERNAL with the wrong size should be reported, got %v", err)
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := Paths{BASIC: "missing", KERNAL: "missing", Character: "missing"}.Load()
+
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Missing file should be reported, got %v", err)
+		}
+	})
+
+	t.Run("No paths", func(t *testing.T) {
+		_, err := Paths{}.Load()
+
+		if err == nil {
+			t.Errorf("Missing paths should be reported")
+		}
+	})
+}
+
+func TestFlags(t *testing.T) {
+	directory := t.TempDir()
+	writeROM(t, directory, "basic", BASIC, 0xBA)
+	writeROM(t, directory, "kernal", KERNAL, 0xEE)
+	writeROM(t, directory, "chargen", Character, 0xCC)
+	custom := writeROM(t, t.TempDir(), "custom.bin", KERNAL, 0xEE)
+
+	var paths Paths
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	paths.RegisterFlags(flags)
+
+	if err := flags.Parse([]string{"-roms", directory, "-kernal", custom}); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths.Directory != directory || paths.KERNAL != custom {
+		t.Fatalf("Flags should set the paths")
+	}
+
+	set, err := paths.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if set.BASIC.Data[0] != 0xBA || set.KERNAL.Data[0] != 0xEE {
+		t.Errorf("ROMs should be loaded from the directory and the flag")
+	}
+}